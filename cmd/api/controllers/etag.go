@@ -0,0 +1,108 @@
+// Package controllers reúne o que é compartilhado entre as versões da API;
+// os handlers e DTOs de cada versão ficam nos subpacotes (v1, ...).
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"gin-quickstart/cmd/api/middlewares"
+	"gin-quickstart/internal/entities"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryETag gera o ETag forte da resposta no formato negociado a partir
+// do ID e da versão de cada categoria. Serve tanto para um recurso quanto
+// para uma listagem: qualquer criação, alteração ou remoção muda o valor. O
+// UpdatedAt fica de fora: o Postgres o guarda em microssegundos, e o ETag
+// devolvido por uma escrita (em nanossegundos) nunca bateria com o de uma
+// leitura posterior. Cada formato (JSON, XML, CSV, ...) é uma representação
+// com bytes diferentes, então entra no hash; o Vary: Accept vem do
+// middlewares.Negotiate.
+func CategoryETag(context *gin.Context, categories ...*entities.Category) string {
+	return categoryETag(middlewares.NegotiatedFormat(context), categories)
+}
+
+// CategoryETags são os ETags das categorias em todos os formatos, para o
+// CheckIfMatch: quem leu em XML pode escrever em JSON.
+func CategoryETags(categories ...*entities.Category) []string {
+	etags := make([]string, 0, len(ListFormats))
+	for _, format := range ListFormats {
+		etags = append(etags, categoryETag(format, categories))
+	}
+	return etags
+}
+
+func categoryETag(format string, categories []*entities.Category) string {
+	hash := sha256.New()
+	hash.Write([]byte(format))
+	buffer := make([]byte, 16)
+
+	for _, category := range categories {
+		binary.BigEndian.PutUint64(buffer[:8], uint64(category.ID))
+		binary.BigEndian.PutUint64(buffer[8:], uint64(category.Version))
+		hash.Write(buffer)
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// NotModified indica se o If-None-Match da requisição já contém o ETag
+// atual, caso em que o handler deve responder 304 sem corpo. A comparação
+// é fraca, como manda a RFC 9110.
+func NotModified(context *gin.Context, etag string) bool {
+	header := context.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range splitETags(header) {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Precondition int
+
+const (
+	PreconditionOK Precondition = iota
+	// PreconditionRequired: a requisição não enviou If-Match (428).
+	PreconditionRequired
+	// PreconditionFailed: o If-Match não bate com o ETag atual (412).
+	PreconditionFailed
+)
+
+// CheckIfMatch valida o If-Match obrigatório das escritas usando comparação
+// forte com qualquer um dos etags: ETags fracos nunca satisfazem a condição.
+func CheckIfMatch(context *gin.Context, etags ...string) Precondition {
+	header := context.GetHeader("If-Match")
+	if header == "" {
+		return PreconditionRequired
+	}
+
+	for _, candidate := range splitETags(header) {
+		if candidate == "*" || slices.Contains(etags, candidate) {
+			return PreconditionOK
+		}
+	}
+
+	return PreconditionFailed
+}
+
+func splitETags(header string) []string {
+	var etags []string
+
+	for _, value := range strings.Split(header, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			etags = append(etags, value)
+		}
+	}
+
+	return etags
+}
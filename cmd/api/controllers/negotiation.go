@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"errors"
+	"gin-quickstart/cmd/api/middlewares"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/entities"
+	"mime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+const (
+	MIMECSV         = "text/csv"
+	MIMEEventStream = "text/event-stream"
+)
+
+// EntityFormats são os tipos oferecidos por qualquer resposta; ListFormats
+// acrescenta CSV, que só representa categorias (sem envelope).
+var (
+	EntityFormats = []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, binding.MIMEYAML2, binding.MIMEYAML, binding.MIMEMSGPACK, binding.MIMEMSGPACK2}
+	ListFormats   = append(append([]string{}, EntityFormats...), MIMECSV)
+)
+
+// BodyFormats são os Content-Types aceitos no corpo das requisições.
+var BodyFormats = []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, binding.MIMEYAML2, binding.MIMEYAML, binding.MIMEMSGPACK, binding.MIMEMSGPACK2}
+
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// Render escreve data no formato negociado pelo middlewares.Negotiate.
+// CSV não representa envelopes, então cai para JSON.
+func Render(context *gin.Context, status int, data any) {
+	switch middlewares.NegotiatedFormat(context) {
+	case binding.MIMEXML, binding.MIMEXML2:
+		context.XML(status, data)
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		context.YAML(status, data)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		context.Render(status, render.MsgPack{Data: data})
+	default:
+		context.JSON(status, data)
+	}
+}
+
+// RenderCategories é o Render de respostas com categorias: em CSV escreve
+// só as linhas das categorias, nos demais formatos o envelope inteiro.
+func RenderCategories(context *gin.Context, status int, data any, categories []*entities.Category) {
+	if middlewares.NegotiatedFormat(context) != MIMECSV {
+		Render(context, status, data)
+		return
+	}
+
+	context.Header("Content-Type", categoryio.CSV.ContentType())
+	context.Status(status)
+
+	writer, err := categoryio.NewWriter(categoryio.CSV, context.Writer)
+	if err != nil {
+		context.Error(err)
+		return
+	}
+
+	for _, category := range categories {
+		if err := writer.Write(category); err != nil {
+			context.Error(err)
+			return
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		context.Error(err)
+	}
+}
+
+// Abort é o Render seguido de context.Abort, para respostas de erro.
+func Abort(context *gin.Context, status int, data any) {
+	Render(context, status, data)
+	context.Abort()
+}
+
+// BodyBinding escolhe o decoder do corpo pelo Content-Type. Sem
+// Content-Type o corpo é lido como JSON.
+func BodyBinding(context *gin.Context) (binding.BindingBody, error) {
+	contentType := context.GetHeader("Content-Type")
+	if contentType == "" {
+		return binding.JSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case binding.MIMEJSON:
+		return binding.JSON, nil
+	case binding.MIMEXML, binding.MIMEXML2:
+		return binding.XML, nil
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		return binding.YAML, nil
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		return binding.MsgPack, nil
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+}
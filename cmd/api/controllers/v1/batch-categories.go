@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+type batchCategoriesInput struct {
+	// atomic (padrão): tudo ou nada; best_effort: cada operação é independente
+	Mode       string                `json:"mode" xml:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Operations []batchOperationInput `json:"operations" xml:"operations>operation" binding:"required,min=1,max=1000,dive"`
+}
+
+type batchOperationInput struct {
+	Op      string `json:"op" xml:"op" binding:"required,oneof=create update delete"`
+	ID      uint   `json:"id" xml:"id" binding:"required_unless=Op create"`
+	Name    string `json:"name" xml:"name" binding:"required_unless=Op delete"`
+	Version uint   `json:"version" xml:"version"`
+}
+
+type batchItemResponse struct {
+	Index    int               `json:"index" xml:"index"`
+	Op       string            `json:"op" xml:"op"`
+	Status   string            `json:"status" xml:"status"`
+	Category *categoryResponse `json:"category,omitempty" xml:"category,omitempty"`
+	Error    *apiError         `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+var BatchCategoriesDoc = openapi.Operation{
+	ID:                   "batchCategories",
+	Summary:              "Cria, altera e remove categorias em lote",
+	Tags:                 []string{"categories"},
+	Request:              batchCategoriesInput{},
+	RequestContentTypes:  controllers.BodyFormats,
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                   {Description: "Lote aplicado; no modo best_effort cada item traz o próprio status", Body: envelope[[]batchItemResponse]{}},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusUnsupportedMediaType: {Body: errorEnvelope{}},
+		http.StatusNotFound:             {Description: "Modo atômico: item com categoria inexistente", Body: errorEnvelope{}},
+		http.StatusConflict:             {Description: "Modo atômico: item com versão desatualizada", Body: errorEnvelope{}},
+		http.StatusUnprocessableEntity:  {Description: "Modo atômico: item inválido, nada foi aplicado", Body: errorEnvelope{}},
+		http.StatusInternalServerError:  {Body: errorEnvelope{}},
+	},
+}
+
+func BatchCategories(context *gin.Context, repository repositories.ICategoryRepository) {
+	var body batchCategoriesInput
+
+	if !bindBody(context, &body) {
+		return
+	}
+
+	operations := make([]use_cases.BatchOperation, len(body.Operations))
+	for i, operation := range body.Operations {
+		operations[i] = use_cases.BatchOperation{
+			Type:    use_cases.BatchOperationType(operation.Op),
+			ID:      operation.ID,
+			Name:    operation.Name,
+			Version: operation.Version,
+		}
+	}
+
+	useCase := use_cases.NewBatchCategoriesUseCase(repository)
+
+	results, err := useCase.Execute(context.Request.Context(), operations, body.Mode != batchModeBestEffort)
+
+	if errors.Is(err, use_cases.ErrBatchAborted) {
+		respondBatchAborted(context, results)
+		return
+	}
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := make([]batchItemResponse, len(results))
+	succeeded, failed := 0, 0
+
+	for i, result := range results {
+		response[i] = batchItemResponse{Index: i, Op: string(result.Operation.Type)}
+
+		if result.Err != nil {
+			_, apiErr := errorStatus(result.Err)
+			response[i].Status = "failed"
+			response[i].Error = &apiErr
+			failed++
+			continue
+		}
+
+		response[i].Status = batchStatus(result.Operation.Type)
+		if result.Category != nil {
+			category := newCategoryResponse(result.Category)
+			response[i].Category = &category
+		}
+		succeeded++
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[[]batchItemResponse]{
+		Data: response,
+		Meta: &meta{Total: len(results), Succeeded: &succeeded, Failed: &failed},
+	})
+}
+
+// respondBatchAborted lista um erro por item que falhou; o status é o do
+// primeiro erro (422, 404 ou 409).
+func respondBatchAborted(context *gin.Context, results []use_cases.BatchResult) {
+	response := errorEnvelope{}
+	status := 0
+
+	for i, result := range results {
+		if result.Err == nil {
+			continue
+		}
+
+		itemStatus, apiErr := errorStatus(result.Err)
+		if status == 0 {
+			status = itemStatus
+		}
+
+		field := fmt.Sprintf("operations[%d]", i)
+		if apiErr.Field != "" {
+			field += "." + apiErr.Field
+		}
+		apiErr.Field = field
+
+		response.Errors = append(response.Errors, apiErr)
+	}
+
+	if status == http.StatusInternalServerError {
+		context.Error(use_cases.ErrBatchAborted)
+	}
+
+	controllers.Abort(context, status, response)
+}
+
+func batchStatus(operationType use_cases.BatchOperationType) string {
+	switch operationType {
+	case use_cases.BatchCreate:
+		return "created"
+	case use_cases.BatchUpdate:
+		return "updated"
+	default:
+		return "deleted"
+	}
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var CategoryChildrenDoc = openapi.Operation{
+	ID:                   "listCategoryChildren",
+	Summary:              "Lista as categorias logo abaixo de uma categoria",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter},
+	ResponseContentTypes: controllers.ListFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]categoryResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func CategoryChildren(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	if _, err := use_cases.NewGetCategoryUseCase(repository).Execute(context.Request.Context(), id); err != nil {
+		respondError(context, err)
+		return
+	}
+
+	children, err := use_cases.NewGetChildrenUseCase(repository).Execute(context.Request.Context(), []uint{id})
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	categories := children[id]
+
+	controllers.RenderCategories(context, http.StatusOK, envelope[[]categoryResponse]{
+		Data: newCategoryListResponse(categories),
+		Meta: &meta{Total: len(categories)},
+	}, categories)
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditEntryResponse é uma alteração da categoria. before não vem em
+// create/restore e after não vem em delete.
+type auditEntryResponse struct {
+	ID        uint              `json:"id" xml:"id"`
+	Action    string            `json:"action" xml:"action"`
+	Actor     string            `json:"actor" xml:"actor"`
+	RequestID string            `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	Before    *categoryResponse `json:"before,omitempty" xml:"before,omitempty"`
+	After     *categoryResponse `json:"after,omitempty" xml:"after,omitempty"`
+	CreatedAt time.Time         `json:"created_at" xml:"created_at"`
+}
+
+var CategoryHistoryDoc = openapi.Operation{
+	ID:                   "getCategoryHistory",
+	Summary:              "Lista as alterações de uma categoria, inclusive removida",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]auditEntryResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func CategoryHistory(context *gin.Context, auditLog audit.IStore) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	useCase := use_cases.NewCategoryHistoryUseCase(auditLog)
+
+	entries, err := useCase.Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := make([]auditEntryResponse, len(entries))
+	for i, entry := range entries {
+		response[i] = auditEntryResponse{
+			ID:        entry.ID,
+			Action:    string(entry.Action),
+			Actor:     entry.Actor,
+			RequestID: entry.RequestID,
+			Before:    newOptionalCategoryResponse(entry.Before),
+			After:     newOptionalCategoryResponse(entry.After),
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[[]auditEntryResponse]{
+		Data: response,
+		Meta: &meta{Total: len(response)},
+	})
+}
+
+func newOptionalCategoryResponse(category *entities.Category) *categoryResponse {
+	if category == nil {
+		return nil
+	}
+	response := newCategoryResponse(category)
+	return &response
+}
@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createCategoryInput struct {
+	Name string `json:"name" xml:"name" binding:"required"`
+}
+
+var CreateCategoryDoc = openapi.Operation{
+	ID:      "createCategory",
+	Summary: "Cria uma categoria",
+	Tags:    []string{"categories"},
+	Parameters: []openapi.Parameter{{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Chave única da requisição; retentativas com a mesma chave repetem a resposta original",
+		Example:     "",
+	}},
+	Request:              createCategoryInput{},
+	RequestContentTypes:  controllers.BodyFormats,
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusCreated:              {Body: envelope[categoryResponse]{}},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusUnsupportedMediaType: {Body: errorEnvelope{}},
+		http.StatusConflict:             {Description: "Requisição com a mesma Idempotency-Key em andamento", Body: errorEnvelope{}},
+		http.StatusUnprocessableEntity:  {Description: "Nome inválido ou Idempotency-Key reutilizada com outro corpo", Body: errorEnvelope{}},
+	},
+}
+
+func CreateCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	var body createCategoryInput
+
+	if !bindBody(context, &body) {
+		return
+	}
+
+	useCase := use_cases.NewCreateCategoryUseCase(repository)
+
+	category, err := useCase.Execute(context.Request.Context(), body.Name)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Header("ETag", controllers.CategoryETag(context, category))
+	context.Header("Location", path.Join(context.FullPath(), strconv.FormatUint(uint64(category.ID), 10)))
+	controllers.Render(context, http.StatusCreated, envelope[categoryResponse]{
+		Data: newCategoryResponse(category),
+	})
+}
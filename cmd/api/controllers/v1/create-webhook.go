@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createWebhookInput struct {
+	URL    string   `json:"url" xml:"url" binding:"required"`
+	Events []string `json:"events" xml:"events>event" binding:"required,min=1"`
+	Secret string   `json:"secret" xml:"secret" binding:"omitempty,min=16"`
+}
+
+var CreateWebhookDoc = openapi.Operation{
+	ID:                   "createWebhook",
+	Summary:              "Assina eventos de categoria num endpoint (o secret só é devolvido aqui)",
+	Tags:                 []string{"webhooks"},
+	Request:              createWebhookInput{},
+	RequestContentTypes:  controllers.BodyFormats,
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusCreated:              {Body: envelope[webhookResponse]{}},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusUnsupportedMediaType: {Body: errorEnvelope{}},
+		http.StatusUnprocessableEntity:  {Description: "URL inválida ou da rede interna, ou evento desconhecido", Body: errorEnvelope{}},
+		http.StatusInternalServerError:  {Body: errorEnvelope{}},
+	},
+}
+
+func CreateWebhook(context *gin.Context, store webhooks.IStore, targets webhooks.Targets) {
+	var body createWebhookInput
+
+	if !bindBody(context, &body) {
+		return
+	}
+
+	useCase := use_cases.NewCreateWebhookUseCase(store, targets)
+
+	subscription, err := useCase.Execute(context.Request.Context(), body.URL, body.Events, body.Secret)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := newWebhookResponse(subscription)
+	response.Secret = subscription.Secret
+
+	context.Header("Location", path.Join(context.FullPath(), strconv.FormatUint(uint64(subscription.ID), 10)))
+	controllers.Render(context, http.StatusCreated, envelope[webhookResponse]{
+		Data: response,
+	})
+}
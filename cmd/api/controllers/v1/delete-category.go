@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var DeleteCategoryDoc = openapi.Operation{
+	ID:                   "deleteCategory",
+	Summary:              "Remove uma categoria",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter, ifMatchParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusNoContent:            {},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusNotFound:             {Body: errorEnvelope{}},
+		http.StatusConflict:             {Body: errorEnvelope{}},
+		http.StatusPreconditionFailed:   {Body: errorEnvelope{}},
+		http.StatusPreconditionRequired: {Body: errorEnvelope{}},
+		http.StatusInternalServerError:  {Body: errorEnvelope{}},
+	},
+}
+
+func DeleteCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	current, err := use_cases.NewGetCategoryUseCase(repository).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	if !respondPrecondition(context, controllers.CheckIfMatch(context, controllers.CategoryETags(current)...)) {
+		return
+	}
+
+	useCase := use_cases.NewDeleteCategoryUseCase(repository)
+
+	if err := useCase.Execute(context.Request.Context(), id, current.Version); err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
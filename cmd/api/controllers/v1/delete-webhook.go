@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var DeleteWebhookDoc = openapi.Operation{
+	ID:                   "deleteWebhook",
+	Summary:              "Remove uma assinatura de webhook e o seu log de entregas",
+	Tags:                 []string{"webhooks"},
+	Parameters:           []openapi.Parameter{webhookIDParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusNoContent:           {},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func DeleteWebhook(context *gin.Context, store webhooks.IStore) {
+	id, ok := idParam(context, "id")
+	if !ok {
+		return
+	}
+
+	if err := use_cases.NewDeleteWebhookUseCase(store).Execute(context.Request.Context(), id); err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"fmt"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type exportCategoriesQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+}
+
+var ExportCategoriesDoc = openapi.Operation{
+	ID:      "exportCategories",
+	Summary: "Exporta todas as categorias em CSV ou JSONL",
+	Tags:    []string{"categories"},
+	Parameters: []openapi.Parameter{{
+		Name:        "format",
+		In:          "query",
+		Description: "csv (padrão) ou jsonl",
+		Example:     "",
+	}},
+	Responses: map[int]openapi.Response{
+		http.StatusOK:         {Body: "", ContentTypes: []string{categoryio.CSV.ContentType(), categoryio.JSONL.ContentType()}},
+		http.StatusBadRequest: {Body: errorEnvelope{}},
+	},
+}
+
+// ExportCategories escreve as linhas direto na resposta, à medida que são
+// lidas do repositório. Depois do primeiro byte enviado o status não muda
+// mais, então erros no meio do caminho só são registrados no log.
+func ExportCategories(context *gin.Context, repository repositories.ICategoryRepository) {
+	var query exportCategoriesQuery
+
+	if err := context.ShouldBindQuery(&query); err != nil {
+		respondBindingError(context, err)
+		return
+	}
+
+	format := categoryio.CSV
+	if query.Format != "" {
+		format = categoryio.Format(query.Format)
+	}
+
+	context.Header("Content-Type", format.ContentType())
+	context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="categories.%s"`, format))
+	context.Status(http.StatusOK)
+
+	writer, err := categoryio.NewWriter(format, context.Writer)
+
+	if err != nil {
+		context.Error(err)
+		return
+	}
+
+	useCase := use_cases.NewExportCategoriesUseCase(repository)
+
+	if _, err := useCase.Execute(context.Request.Context(), writer); err != nil {
+		// Antes do primeiro byte (ex: 403) ainda dá para responder o erro
+		if !context.Writer.Written() {
+			context.Writer.Header().Del("Content-Type")
+			context.Writer.Header().Del("Content-Disposition")
+			respondError(context, err)
+			return
+		}
+		context.Error(err)
+	}
+}
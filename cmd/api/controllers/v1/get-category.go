@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var GetCategoryDoc = openapi.Operation{
+	ID:                   "getCategory",
+	Summary:              "Busca uma categoria pelo ID",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter, ifNoneMatchParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[categoryResponse]{}},
+		http.StatusNotModified:         {},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+var categoryIDParameter = openapi.Parameter{
+	Name:        "id",
+	In:          "path",
+	Description: "ID da categoria",
+	Example:     uint(0),
+}
+
+var ifNoneMatchParameter = openapi.Parameter{
+	Name:        "If-None-Match",
+	In:          "header",
+	Description: "ETag já conhecido pelo cliente; responde 304 se não mudou",
+	Example:     "",
+}
+
+var ifMatchParameter = openapi.Parameter{
+	Name:        "If-Match",
+	In:          "header",
+	Description: "ETag da versão da categoria que o cliente está alterando, lido em qualquer formato",
+	Required:    true,
+	Example:     "",
+}
+
+func GetCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	useCase := use_cases.NewGetCategoryUseCase(repository)
+
+	category, err := useCase.Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	etag := controllers.CategoryETag(context, category)
+	context.Header("ETag", etag)
+
+	if controllers.NotModified(context, etag) {
+		context.Status(http.StatusNotModified)
+		return
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[categoryResponse]{
+		Data: newCategoryResponse(category),
+	})
+}
+
+// categoryIDParam lê o :id da rota, respondendo 400 quando não é numérico.
+func categoryIDParam(context *gin.Context) (uint, bool) {
+	return idParam(context, "id")
+}
+
+// idParam lê o parâmetro numérico name da rota, respondendo 400 quando não
+// é um inteiro positivo.
+func idParam(context *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(context.Param(name), 10, 0)
+
+	if err != nil || id == 0 {
+		controllers.Abort(context, http.StatusBadRequest, errorEnvelope{
+			Errors: []apiError{{Code: "invalid_request", Message: name + " must be a positive integer", Field: name}},
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
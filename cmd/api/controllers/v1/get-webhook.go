@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var GetWebhookDoc = openapi.Operation{
+	ID:                   "getWebhook",
+	Summary:              "Busca uma assinatura de webhook",
+	Tags:                 []string{"webhooks"},
+	Parameters:           []openapi.Parameter{webhookIDParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[webhookResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func GetWebhook(context *gin.Context, store webhooks.IStore) {
+	id, ok := idParam(context, "id")
+	if !ok {
+		return
+	}
+
+	subscription, err := use_cases.NewGetWebhookUseCase(store).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[webhookResponse]{
+		Data: newWebhookResponse(subscription),
+	})
+}
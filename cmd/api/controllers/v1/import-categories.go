@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"errors"
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type importCategoriesInput struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type importCategoriesQuery struct {
+	// Sem format, o formato vem da extensão do arquivo
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type importReportResponse struct {
+	DryRun   bool                 `json:"dry_run" xml:"dry_run"`
+	Total    int                  `json:"total" xml:"total"`
+	Imported int                  `json:"imported" xml:"imported"`
+	Failed   int                  `json:"failed" xml:"failed"`
+	Errors   []importLineResponse `json:"errors" xml:"errors>error"`
+}
+
+type importLineResponse struct {
+	Line    int    `json:"line" xml:"line"`
+	Message string `json:"message" xml:"message"`
+}
+
+var ImportCategoriesDoc = openapi.Operation{
+	ID:      "importCategories",
+	Summary: "Importa categorias de um arquivo CSV ou JSONL",
+	Tags:    []string{"categories"},
+	Parameters: []openapi.Parameter{
+		{Name: "format", In: "query", Description: "csv ou jsonl; por padrão usa a extensão do arquivo", Example: ""},
+		{Name: "dry_run", In: "query", Description: "só valida as linhas (inclusive nomes repetidos ou já usados), sem gravar", Example: false},
+	},
+	Request:              importCategoriesInput{},
+	RequestContentTypes:  []string{"multipart/form-data"},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Description: "Relatório da importação, com os erros por linha", Body: envelope[importReportResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func ImportCategories(context *gin.Context, repository repositories.ICategoryRepository) {
+	var query importCategoriesQuery
+	var body importCategoriesInput
+
+	if err := context.ShouldBindQuery(&query); err != nil {
+		respondBindingError(context, err)
+		return
+	}
+
+	if err := context.ShouldBind(&body); err != nil {
+		respondBindingError(context, err)
+		return
+	}
+
+	format, err := categoryio.FormatFromFilename(body.File.Filename)
+	if query.Format != "" {
+		format, err = categoryio.ParseFormat(query.Format)
+	}
+
+	if err != nil {
+		respondInvalidRequest(context, "format", err)
+		return
+	}
+
+	file, err := body.File.Open()
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+	defer file.Close()
+
+	reader, err := categoryio.NewReader(format, file)
+
+	var rowError *categoryio.RowError
+	if errors.As(err, &rowError) {
+		respondInvalidRequest(context, "file", err)
+		return
+	}
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	useCase := use_cases.NewImportCategoriesUseCase(repository)
+
+	report, err := useCase.Execute(context.Request.Context(), reader, query.DryRun)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := importReportResponse{
+		DryRun:   report.DryRun,
+		Total:    report.Total,
+		Imported: report.Imported,
+		Failed:   report.Failed,
+		Errors:   make([]importLineResponse, 0, len(report.Errors)),
+	}
+
+	for _, lineError := range report.Errors {
+		response.Errors = append(response.Errors, importLineResponse{Line: lineError.Line, Message: lineError.Message})
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[importReportResponse]{
+		Data: response,
+	})
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+type listCategoriesQuery struct {
+	Roots bool `form:"roots"`
+}
+
+var ListCategoryDoc = openapi.Operation{
+	ID:      "listCategories",
+	Summary: "Lista as categorias (também em text/csv via Accept)",
+	Tags:    []string{"categories"},
+	Parameters: []openapi.Parameter{ifNoneMatchParameter, {
+		Name:        "roots",
+		In:          "query",
+		Description: "true lista só as categorias da raiz, para descer a árvore por /children",
+		Example:     false,
+	}},
+	ResponseContentTypes: controllers.ListFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]categoryResponse]{}},
+		http.StatusNotModified:         {},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func ListCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	var query listCategoriesQuery
+
+	if err := context.ShouldBindQuery(&query); err != nil {
+		respondBindingError(context, err)
+		return
+	}
+
+	useCase := use_cases.NewListCategoriesUseCase(repository)
+
+	categories, err := useCase.Execute(context.Request.Context())
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	if query.Roots {
+		categories = slices.DeleteFunc(categories, func(category *entities.Category) bool { return category.ParentID != nil })
+	}
+
+	etag := controllers.CategoryETag(context, categories...)
+	context.Header("ETag", etag)
+
+	if controllers.NotModified(context, etag) {
+		context.Status(http.StatusNotModified)
+		return
+	}
+
+	controllers.RenderCategories(context, http.StatusOK, envelope[[]categoryResponse]{
+		Data: newCategoryListResponse(categories),
+		Meta: &meta{Total: len(categories)},
+	}, categories)
+}
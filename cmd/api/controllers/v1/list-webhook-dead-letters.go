@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ListWebhookDeadLettersDoc = openapi.Operation{
+	ID:                   "listWebhookDeadLetters",
+	Summary:              "Entregas que esgotaram as tentativas (dead-letter)",
+	Tags:                 []string{"webhooks"},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]webhookDeliveryResponse]{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func ListWebhookDeadLetters(context *gin.Context, store webhooks.IStore) {
+	deliveries, err := use_cases.NewListWebhookDeadLettersUseCase(store).Execute(context.Request.Context())
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := newWebhookDeliveryListResponse(deliveries)
+
+	controllers.Render(context, http.StatusOK, envelope[[]webhookDeliveryResponse]{
+		Data: response,
+		Meta: &meta{Total: len(response)},
+	})
+}
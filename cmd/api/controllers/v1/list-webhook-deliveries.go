@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ListWebhookDeliveriesDoc = openapi.Operation{
+	ID:                   "listWebhookDeliveries",
+	Summary:              "Log de entregas de uma assinatura",
+	Tags:                 []string{"webhooks"},
+	Parameters:           []openapi.Parameter{webhookIDParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]webhookDeliveryResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func ListWebhookDeliveries(context *gin.Context, store webhooks.IStore) {
+	id, ok := idParam(context, "id")
+	if !ok {
+		return
+	}
+
+	deliveries, err := use_cases.NewListWebhookDeliveriesUseCase(store).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := newWebhookDeliveryListResponse(deliveries)
+
+	controllers.Render(context, http.StatusOK, envelope[[]webhookDeliveryResponse]{
+		Data: response,
+		Meta: &meta{Total: len(response)},
+	})
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ListWebhooksDoc = openapi.Operation{
+	ID:                   "listWebhooks",
+	Summary:              "Lista as assinaturas de webhook",
+	Tags:                 []string{"webhooks"},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[[]webhookResponse]{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func ListWebhooks(context *gin.Context, store webhooks.IStore) {
+	subscriptions, err := use_cases.NewListWebhooksUseCase(store).Execute(context.Request.Context())
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	response := make([]webhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newWebhookResponse(subscription))
+	}
+
+	controllers.Render(context, http.StatusOK, envelope[[]webhookResponse]{
+		Data: response,
+		Meta: &meta{Total: len(response)},
+	})
+}
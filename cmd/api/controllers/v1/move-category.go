@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// moveCategoryInput sem parent_id (ou com null) leva a categoria para a raiz.
+type moveCategoryInput struct {
+	ParentID *uint `json:"parent_id" xml:"parent_id" binding:"omitempty,gt=0"`
+}
+
+var MoveCategoryDoc = openapi.Operation{
+	ID:                   "moveCategory",
+	Summary:              "Coloca a categoria abaixo de outra, ou na raiz",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter, ifMatchParameter},
+	Request:              moveCategoryInput{},
+	RequestContentTypes:  controllers.BodyFormats,
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                   {Body: envelope[categoryResponse]{}},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusUnsupportedMediaType: {Body: errorEnvelope{}},
+		http.StatusNotFound:             {Body: errorEnvelope{}},
+		http.StatusConflict:             {Body: errorEnvelope{}},
+		http.StatusPreconditionFailed:   {Body: errorEnvelope{}},
+		http.StatusUnprocessableEntity:  {Description: "Pai inexistente ou abaixo da própria categoria", Body: errorEnvelope{}},
+		http.StatusPreconditionRequired: {Body: errorEnvelope{}},
+		http.StatusInternalServerError:  {Body: errorEnvelope{}},
+	},
+}
+
+func MoveCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	var body moveCategoryInput
+
+	if !bindBody(context, &body) {
+		return
+	}
+
+	current, err := use_cases.NewGetCategoryUseCase(repository).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	if !respondPrecondition(context, controllers.CheckIfMatch(context, controllers.CategoryETags(current)...)) {
+		return
+	}
+
+	useCase := use_cases.NewMoveCategoryUseCase(repository)
+
+	category, err := useCase.Execute(context.Request.Context(), id, body.ParentID, current.Version)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Header("ETag", controllers.CategoryETag(context, category))
+	controllers.Render(context, http.StatusOK, envelope[categoryResponse]{
+		Data: newCategoryResponse(category),
+	})
+}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	use_cases "gin-quickstart/internal/use-cases"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var RedeliverWebhookDoc = openapi.Operation{
+	ID:      "redeliverWebhook",
+	Summary: "Tira uma entrega da dead-letter para ser reenviada",
+	Tags:    []string{"webhooks"},
+	Parameters: []openapi.Parameter{{
+		Name:        "id",
+		In:          "path",
+		Description: "ID da entrega",
+		Example:     uint(0),
+	}},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusAccepted:            {Body: envelope[webhookDeliveryResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Body: errorEnvelope{}},
+		http.StatusConflict:            {Description: "A entrega não está na dead-letter", Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func RedeliverWebhook(context *gin.Context, store webhooks.IStore) {
+	id, ok := idParam(context, "id")
+	if !ok {
+		return
+	}
+
+	delivery, err := use_cases.NewRedeliverWebhookUseCase(store).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	controllers.Render(context, http.StatusAccepted, envelope[webhookDeliveryResponse]{
+		Data: newWebhookDeliveryResponse(delivery),
+	})
+}
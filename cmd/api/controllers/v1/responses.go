@@ -0,0 +1,210 @@
+// Package v1 contém os DTOs e handlers da versão 1 da API. Uma nova versão
+// deve viver em controllers/v2 com seus próprios DTOs, reutilizando os
+// mesmos use cases de internal/use-cases.
+package v1
+
+import (
+	"encoding/xml"
+	"errors"
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/webhooks"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// envelope é o formato padrão das respostas de sucesso: o recurso em data e
+// informações auxiliares (ex: paginação) em meta.
+//
+// Em XML o elemento raiz é sempre <response>, já que o nome do tipo
+// genérico não é um nome de elemento válido.
+type envelope[T any] struct {
+	XMLName xml.Name `json:"-" xml:"response" yaml:"-" codec:"-"`
+	Data    T        `json:"data" xml:"data"`
+	Meta    *meta    `json:"meta,omitempty" xml:"meta,omitempty"`
+}
+
+type meta struct {
+	Total     int  `json:"total" xml:"total"`
+	Succeeded *int `json:"succeeded,omitempty" xml:"succeeded,omitempty"`
+	Failed    *int `json:"failed,omitempty" xml:"failed,omitempty"`
+}
+
+// errorEnvelope é o formato padrão das respostas de erro.
+type errorEnvelope struct {
+	XMLName xml.Name   `json:"-" xml:"response" yaml:"-" codec:"-"`
+	Errors  []apiError `json:"errors" xml:"errors>error"`
+}
+
+// UnauthorizedResponse documenta o 401 das rotas autenticadas, respondido
+// pelo middlewares.Authenticate.
+var UnauthorizedResponse = openapi.Response{
+	Description:  "Credenciais ausentes ou inválidas",
+	Body:         errorEnvelope{},
+	ContentTypes: []string{"application/json"},
+}
+
+// ForbiddenResponse documenta o 403 das rotas autenticadas, quando falta ao
+// principal a permissão exigida pelo use case.
+var ForbiddenResponse = openapi.Response{
+	Description: "Sem permissão para a operação (nomeada em permission)",
+	Body:        errorEnvelope{},
+}
+
+// TooManyRequestsResponse documenta o 429 das rotas com limite de
+// requisições, respondido pelo middlewares.RateLimit.
+var TooManyRequestsResponse = openapi.Response{
+	Description:  "Limite de requisições do cliente esgotado; tente de novo depois de Retry-After segundos",
+	Body:         errorEnvelope{},
+	ContentTypes: []string{"application/json"},
+}
+
+type apiError struct {
+	Code       string `json:"code" xml:"code"`
+	Message    string `json:"message" xml:"message"`
+	Field      string `json:"field,omitempty" xml:"field,omitempty"`
+	Permission string `json:"permission,omitempty" xml:"permission,omitempty"`
+}
+
+// categoryResponse é a categoria; parent_id é null na raiz.
+type categoryResponse struct {
+	ID        uint      `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	ParentID  *uint     `json:"parent_id" xml:"parent_id,omitempty"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+	Version   uint      `json:"version" xml:"version"`
+}
+
+func newCategoryResponse(category *entities.Category) categoryResponse {
+	return categoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		ParentID:  category.ParentID,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+		Version:   category.Version,
+	}
+}
+
+func newCategoryListResponse(categories []*entities.Category) []categoryResponse {
+	response := make([]categoryResponse, 0, len(categories))
+	for _, category := range categories {
+		response = append(response, newCategoryResponse(category))
+	}
+	return response
+}
+
+// respondBindingError responde 400 com um erro por campo inválido do body.
+func respondBindingError(context *gin.Context, err error) {
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		controllers.Abort(context, http.StatusBadRequest, errorEnvelope{
+			Errors: []apiError{{Code: "invalid_request", Message: err.Error()}},
+		})
+		return
+	}
+
+	response := errorEnvelope{}
+	for _, fieldError := range validationErrors {
+		response.Errors = append(response.Errors, apiError{
+			Code:    "invalid_request",
+			Message: fieldError.Error(),
+			Field:   fieldError.Field(),
+		})
+	}
+
+	controllers.Abort(context, http.StatusBadRequest, response)
+}
+
+// bindBody decodifica o corpo conforme o Content-Type (JSON, XML, YAML ou
+// MessagePack) e valida as tags binding. Retorna false quando a requisição
+// foi abortada com 415 ou 400.
+func bindBody(context *gin.Context, body any) bool {
+	bodyBinding, err := controllers.BodyBinding(context)
+	if err != nil {
+		controllers.Abort(context, http.StatusUnsupportedMediaType, errorEnvelope{
+			Errors: []apiError{{Code: "unsupported_media_type", Message: "supported content types: " + strings.Join(controllers.BodyFormats, ", ")}},
+		})
+		return false
+	}
+
+	if err := context.ShouldBindBodyWith(body, bodyBinding); err != nil {
+		respondBindingError(context, err)
+		return false
+	}
+
+	return true
+}
+
+// respondInvalidRequest responde 400 para um parâmetro inválido que não
+// passou pelo binding.
+func respondInvalidRequest(context *gin.Context, field string, err error) {
+	controllers.Abort(context, http.StatusBadRequest, errorEnvelope{
+		Errors: []apiError{{Code: "invalid_request", Message: err.Error(), Field: field}},
+	})
+}
+
+// respondError traduz os erros de domínio e de repositório para status HTTP.
+func respondError(context *gin.Context, err error) {
+	status, apiErr := errorStatus(err)
+
+	if status == http.StatusInternalServerError {
+		context.Error(err)
+	}
+
+	controllers.Abort(context, status, errorEnvelope{
+		Errors: []apiError{apiErr},
+	})
+}
+
+func errorStatus(err error) (int, apiError) {
+	var validationError *entities.ValidationError
+	var forbiddenError *authz.ForbiddenError
+
+	switch {
+	case errors.As(err, &validationError):
+		return http.StatusUnprocessableEntity, apiError{Code: "validation_failed", Message: validationError.Message, Field: validationError.Field}
+	case errors.As(err, &forbiddenError):
+		return http.StatusForbidden, apiError{Code: "forbidden", Message: forbiddenError.Error(), Permission: string(forbiddenError.Permission)}
+	case errors.Is(err, authz.ErrUnauthenticated):
+		return http.StatusUnauthorized, apiError{Code: "unauthorized", Message: err.Error()}
+	case errors.Is(err, repositories.ErrCategoryNotFound),
+		errors.Is(err, webhooks.ErrSubscriptionNotFound),
+		errors.Is(err, webhooks.ErrDeliveryNotFound):
+		return http.StatusNotFound, apiError{Code: "not_found", Message: err.Error()}
+	case errors.Is(err, repositories.ErrVersionConflict),
+		errors.Is(err, repositories.ErrDuplicateName),
+		errors.Is(err, webhooks.ErrDeliveryNotDead):
+		return http.StatusConflict, apiError{Code: "conflict", Message: err.Error()}
+	default:
+		return http.StatusInternalServerError, apiError{Code: "internal_error", Message: http.StatusText(http.StatusInternalServerError)}
+	}
+}
+
+// respondPrecondition responde 428/412 quando o If-Match não é satisfeito.
+// Retorna false quando a requisição foi abortada.
+func respondPrecondition(context *gin.Context, precondition controllers.Precondition) bool {
+	switch precondition {
+	case controllers.PreconditionRequired:
+		controllers.Abort(context, http.StatusPreconditionRequired, errorEnvelope{
+			Errors: []apiError{{Code: "precondition_required", Message: "If-Match header is required"}},
+		})
+		return false
+	case controllers.PreconditionFailed:
+		controllers.Abort(context, http.StatusPreconditionFailed, errorEnvelope{
+			Errors: []apiError{{Code: "precondition_failed", Message: "category was modified, fetch it again and retry"}},
+		})
+		return false
+	default:
+		return true
+	}
+}
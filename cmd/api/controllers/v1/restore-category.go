@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var RestoreCategoryDoc = openapi.Operation{
+	ID:                   "restoreCategory",
+	Summary:              "Restaura uma categoria removida",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter},
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                  {Body: envelope[categoryResponse]{}},
+		http.StatusBadRequest:          {Body: errorEnvelope{}},
+		http.StatusNotFound:            {Description: "Categoria inexistente ou que não está removida", Body: errorEnvelope{}},
+		http.StatusInternalServerError: {Body: errorEnvelope{}},
+	},
+}
+
+func RestoreCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	useCase := use_cases.NewRestoreCategoryUseCase(repository)
+
+	category, err := useCase.Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Header("ETag", controllers.CategoryETag(context, category))
+	controllers.Render(context, http.StatusOK, envelope[categoryResponse]{
+		Data: newCategoryResponse(category),
+	})
+}
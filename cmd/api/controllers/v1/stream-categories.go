@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"fmt"
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/stream"
+	"gin-quickstart/internal/tenancy"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// ResetEvent avisa o cliente que eventos se perderam e que ele deve
+// recarregar a listagem.
+const ResetEvent = stream.ResetEvent
+
+// categoryStreamEvent documenta o formato de cada evento SSE: id, event
+// (ex: category.created) e data com o JSON do evento de domínio.
+type categoryStreamEvent struct {
+	ID    string         `json:"id"`
+	Event string         `json:"event"`
+	Data  map[string]any `json:"data"`
+}
+
+var StreamCategoriesDoc = openapi.Operation{
+	ID:      "streamCategories",
+	Summary: "Acompanha as alterações de categorias via Server-Sent Events",
+	Tags:    []string{"categories"},
+	Parameters: []openapi.Parameter{{
+		Name:        "Last-Event-ID",
+		In:          "header",
+		Description: "Último id recebido; os eventos seguintes ainda no buffer são reenviados",
+		Example:     uint(0),
+	}},
+	ResponseContentTypes: []string{controllers.MIMEEventStream},
+	Responses: map[int]openapi.Response{
+		http.StatusOK:            {Description: "Stream de eventos com heartbeats (comentários SSE)", Body: categoryStreamEvent{}},
+		http.StatusBadRequest:    {Body: errorEnvelope{}, ContentTypes: []string{"application/json"}},
+		http.StatusNotAcceptable: {Body: errorEnvelope{}, ContentTypes: []string{"application/json"}},
+	},
+}
+
+// StreamCategories mantém a conexão aberta até o cliente desconectar. Um
+// cliente que não acompanha as publicações tem a conexão encerrada e deve
+// reconectar com Last-Event-ID (o EventSource do navegador já faz isso).
+func StreamCategories(context *gin.Context, broadcaster *stream.Broadcaster, heartbeat time.Duration) {
+	// O stream não passa por um use case, então a permissão é checada aqui
+	if err := authz.Require(context.Request.Context(), authz.CategoriesRead); err != nil {
+		respondError(context, err)
+		return
+	}
+
+	tenant := tenancy.From(context.Request.Context())
+
+	var client *stream.Client
+	missed, complete := []stream.Message(nil), true
+
+	if header := context.GetHeader("Last-Event-ID"); header != "" {
+		lastEventID, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			respondInvalidRequest(context, "Last-Event-ID", fmt.Errorf("Last-Event-ID must be a non-negative integer"))
+			return
+		}
+		client, missed, complete = broadcaster.SubscribeAfter(tenant, lastEventID)
+	} else {
+		client = broadcaster.Subscribe(tenant)
+	}
+
+	defer broadcaster.Unsubscribe(client)
+
+	context.Header("Content-Type", controllers.MIMEEventStream)
+	context.Header("Cache-Control", "no-cache")
+	context.Header("Connection", "keep-alive")
+	// Desliga o buffer de proxies como o nginx
+	context.Header("X-Accel-Buffering", "no")
+	context.Status(http.StatusOK)
+
+	if !complete {
+		context.Render(-1, sse.Event{Event: ResetEvent, Data: "{}"})
+	}
+
+	for _, message := range missed {
+		renderMessage(context, message)
+	}
+	context.Writer.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-context.Request.Context().Done():
+			return
+		case message, ok := <-client.Messages():
+			if !ok {
+				return
+			}
+			renderMessage(context, message)
+		case <-ticker.C:
+			fmt.Fprint(context.Writer, ": heartbeat\n\n")
+		}
+		context.Writer.Flush()
+	}
+}
+
+func renderMessage(context *gin.Context, message stream.Message) {
+	event := sse.Event{Event: message.Event, Data: string(message.Data)}
+	// Sem id o navegador mantém o último Last-Event-ID (um "id:" vazio o
+	// apagaria)
+	if message.ID != 0 {
+		event.Id = strconv.FormatUint(message.ID, 10)
+	}
+
+	context.Render(-1, event)
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"gin-quickstart/cmd/api/controllers"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type updateCategoryInput struct {
+	Name string `json:"name" xml:"name" binding:"required"`
+}
+
+var UpdateCategoryDoc = openapi.Operation{
+	ID:                   "updateCategory",
+	Summary:              "Renomeia uma categoria",
+	Tags:                 []string{"categories"},
+	Parameters:           []openapi.Parameter{categoryIDParameter, ifMatchParameter},
+	Request:              updateCategoryInput{},
+	RequestContentTypes:  controllers.BodyFormats,
+	ResponseContentTypes: controllers.EntityFormats,
+	Responses: map[int]openapi.Response{
+		http.StatusOK:                   {Body: envelope[categoryResponse]{}},
+		http.StatusBadRequest:           {Body: errorEnvelope{}},
+		http.StatusUnsupportedMediaType: {Body: errorEnvelope{}},
+		http.StatusNotFound:             {Body: errorEnvelope{}},
+		http.StatusConflict:             {Body: errorEnvelope{}},
+		http.StatusPreconditionFailed:   {Body: errorEnvelope{}},
+		http.StatusUnprocessableEntity:  {Body: errorEnvelope{}},
+		http.StatusPreconditionRequired: {Body: errorEnvelope{}},
+		http.StatusInternalServerError:  {Body: errorEnvelope{}},
+	},
+}
+
+func UpdateCategory(context *gin.Context, repository repositories.ICategoryRepository) {
+	id, ok := categoryIDParam(context)
+	if !ok {
+		return
+	}
+
+	var body updateCategoryInput
+
+	if !bindBody(context, &body) {
+		return
+	}
+
+	current, err := use_cases.NewGetCategoryUseCase(repository).Execute(context.Request.Context(), id)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	if !respondPrecondition(context, controllers.CheckIfMatch(context, controllers.CategoryETags(current)...)) {
+		return
+	}
+
+	useCase := use_cases.NewUpdateCategoryUseCase(repository)
+
+	category, err := useCase.Execute(context.Request.Context(), id, body.Name, current.Version)
+
+	if err != nil {
+		respondError(context, err)
+		return
+	}
+
+	context.Header("ETag", controllers.CategoryETag(context, category))
+	controllers.Render(context, http.StatusOK, envelope[categoryResponse]{
+		Data: newCategoryResponse(category),
+	})
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"encoding/json"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/webhooks"
+	"time"
+)
+
+// webhookResponse é uma assinatura. O secret só aparece na resposta da
+// criação.
+type webhookResponse struct {
+	ID        uint      `json:"id" xml:"id"`
+	URL       string    `json:"url" xml:"url"`
+	Events    []string  `json:"events" xml:"events>event"`
+	Secret    string    `json:"secret,omitempty" xml:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+// webhookDeliveryResponse é uma entrada do log de entregas. payload é o
+// corpo exato enviado (e assinado) ao receptor.
+type webhookDeliveryResponse struct {
+	ID             uint           `json:"id" xml:"id"`
+	SubscriptionID uint           `json:"subscription_id" xml:"subscription_id"`
+	Event          string         `json:"event" xml:"event"`
+	Status         string         `json:"status" xml:"status"`
+	Attempts       int            `json:"attempts" xml:"attempts"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" xml:"next_attempt_at,omitempty"`
+	LastStatusCode int            `json:"last_status_code,omitempty" xml:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty" xml:"last_error,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" xml:"delivered_at,omitempty"`
+	Payload        map[string]any `json:"payload" xml:"-"`
+	CreatedAt      time.Time      `json:"created_at" xml:"created_at"`
+}
+
+var webhookIDParameter = openapi.Parameter{
+	Name:        "id",
+	In:          "path",
+	Description: "ID da assinatura",
+	Example:     uint(0),
+}
+
+func newWebhookResponse(subscription *webhooks.Subscription) webhookResponse {
+	return webhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryListResponse(deliveries []*webhooks.Delivery) []webhookDeliveryResponse {
+	response := make([]webhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, newWebhookDeliveryResponse(delivery))
+	}
+	return response
+}
+
+func newWebhookDeliveryResponse(delivery *webhooks.Delivery) webhookDeliveryResponse {
+	response := webhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		Event:          delivery.EventName,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	// O payload foi gerado por nós em JSON; decodificá-lo permite devolvê-lo
+	// nos outros formatos
+	_ = json.Unmarshal(delivery.Payload, &response.Payload)
+
+	if delivery.Status == webhooks.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+
+	return response
+}
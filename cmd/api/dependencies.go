@@ -0,0 +1,241 @@
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"gin-quickstart/cmd/api/graph"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/config"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/idempotency"
+	"gin-quickstart/internal/outbox"
+	"gin-quickstart/internal/ratelimit"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/stream"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+	"log"
+	"maps"
+	"time"
+)
+
+// categoryStore é o repositório de categorias junto com a sua outbox e o
+// seu log de auditoria.
+type categoryStore interface {
+	repositories.ICategoryRepository
+	outbox.IStore
+	audit.IStore
+}
+
+// dependencies reúne os repositórios e serviços compartilhados pelas rotas.
+// Os eventos gravados na outbox chegam ao eventBus pelo outboxRelay, e do
+// bus ao webhookDispatcher. As leituras de categorias passam pelo
+// categoryCache (nil com CATEGORY_CACHE_SIZE=0); o outboxRelay usa o
+// repositório direto.
+//
+// Em memória o categoryStream (SSE) também assina o bus. Com o Postgres
+// quem o alimenta é o categoryListener, que recebe por LISTEN/NOTIFY as
+// escritas de todas as réplicas e invalida o cache desta: o relay entrega
+// cada evento em uma réplica só.
+type dependencies struct {
+	categoryRepository repositories.ICategoryRepository
+	categoryCache      *repositories.CategoryCache
+	categoryListener   *repositories.CategoryListener
+	auditLog           audit.IStore
+	eventBus           *events.Bus
+	outboxRelay        *outbox.Relay
+	webhookStore       webhooks.IStore
+	webhookDispatcher  *webhooks.Dispatcher
+	webhookTargets     webhooks.Targets
+	categoryStream     *stream.Broadcaster
+	streamHeartbeat    time.Duration
+	idempotencyStore   idempotency.IStore
+	idempotencyTTL     time.Duration
+	idempotencyLease   time.Duration
+	authenticator      auth.IAuthenticator
+	policy             *authz.Policy
+	tenantResolver     tenancy.Resolver
+	rateLimiter        *ratelimit.Limiter
+	trustedProxies     []string
+	graphqlLimits      graph.Limits
+}
+
+// newDependencies monta as dependências de acordo com o REPOSITORY_DRIVER
+// e as variáveis AUTH_*, AUTHZ_POLICY_FILE e RATE_LIMITS_FILE. Com o
+// Postgres os buckets de rate limit também ficam no banco, e a cota é
+// comum a todas as réplicas.
+func newDependencies() (*dependencies, error) {
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := authz.DefaultPolicy()
+	if path := config.AuthzPolicyFile(); path != "" {
+		if policy, err = authz.LoadPolicy(path); err != nil {
+			return nil, err
+		}
+	}
+
+	rateLimits := ratelimit.DefaultRules
+	if path := config.RateLimitsFile(); path != "" {
+		if rateLimits, err = ratelimit.LoadRules(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.RepositoryDriver() != "postgres" {
+		deps := inMemoryDependencies()
+		deps.authenticator = authenticator
+		deps.policy = policy
+		deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), rateLimits)
+		deps.trustedProxies = config.TrustedProxies()
+		return deps, nil
+	}
+
+	cfg := config.NewConfig()
+
+	if err := cfg.ConnectDB(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.MigrateDB(); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	deps := newCategoryDependencies(
+		repositories.NewPostgresCategoryRepository(cfg.DB),
+		webhooks.NewPostgresStore(cfg.DB),
+		idempotency.NewPostgresStore(cfg.DB),
+	)
+	deps.categoryListener = repositories.NewCategoryListener(
+		config.DatabaseDSN(),
+		cfg.DB,
+		events.NewRegistry(entities.CategoryEvents...),
+		deps.categoryCache,
+		deps.categoryStream,
+	)
+	deps.authenticator = authenticator
+	deps.policy = policy
+	deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewPostgresStore(cfg.DB), rateLimits)
+	deps.trustedProxies = config.TrustedProxies()
+	return deps, nil
+}
+
+// inMemoryDependencies não exige autenticação e usa a política e os
+// limites padrão; newDependencies troca os três pelos configurados.
+func inMemoryDependencies() *dependencies {
+	deps := newCategoryDependencies(
+		repositories.NewInMemotyCategoryRepository(),
+		webhooks.NewInMemoryStore(),
+		idempotency.NewInMemoryStore(),
+	)
+	deps.eventBus.Subscribe(events.AllEvents, deps.categoryStream.Broadcast)
+	return deps
+}
+
+func newCategoryDependencies(store categoryStore, webhookStore webhooks.IStore, idempotencyStore idempotency.IStore) *dependencies {
+	eventBus := events.NewBus(nil)
+	webhookTargets := webhooks.Targets{AllowPrivate: config.WebhookAllowPrivateTargets()}
+	webhookDispatcher := webhooks.NewDispatcher(webhookStore, webhookTargets.Client())
+
+	// Síncrono: se a gravação das entregas falhar, o relay reentrega o evento
+	eventBus.Subscribe(events.AllEvents, webhookDispatcher.Enqueue)
+
+	categoryStream := stream.NewBroadcaster(stream.DefaultReplaySize, stream.DefaultClientBuffer)
+
+	var repository repositories.ICategoryRepository = store
+	var categoryCache *repositories.CategoryCache
+	if size := config.CategoryCacheSize(); size > 0 {
+		categoryCache = repositories.NewCategoryCache(repositories.CacheOptions{Size: size, TTL: config.CategoryCacheTTL()})
+		repository = repositories.NewCachingCategoryRepository(store, categoryCache)
+	}
+
+	return &dependencies{
+		categoryRepository: repository,
+		categoryCache:      categoryCache,
+		auditLog:           store,
+		eventBus:           eventBus,
+		outboxRelay:        outbox.NewRelay(store, events.NewRegistry(entities.CategoryEvents...), eventBus),
+		webhookStore:       webhookStore,
+		webhookDispatcher:  webhookDispatcher,
+		webhookTargets:     webhookTargets,
+		categoryStream:     categoryStream,
+		streamHeartbeat:    config.StreamHeartbeatInterval(),
+		idempotencyStore:   idempotencyStore,
+		idempotencyTTL:     config.IdempotencyTTL(),
+		idempotencyLease:   config.IdempotencyLease(),
+		authenticator:      auth.Disabled(),
+		policy:             authz.DefaultPolicy(),
+		tenantResolver:     tenancy.Resolver{Header: config.TenantHeader(), BaseDomain: config.TenantBaseDomain()},
+		rateLimiter:        ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.DefaultRules),
+		graphqlLimits:      graph.Limits{MaxDepth: config.GraphQLMaxDepth(), MaxComplexity: config.GraphQLMaxComplexity()},
+	}
+}
+
+// newAuthenticator monta o authenticator a partir das variáveis AUTH_*.
+// Sem nenhum método configurado a API não sobe, a não ser com
+// AUTH_DISABLED=true.
+func newAuthenticator() (auth.IAuthenticator, error) {
+	if config.AuthDisabled() {
+		log.Println("WARNING: authentication is disabled (AUTH_DISABLED=true)")
+		return auth.Disabled(), nil
+	}
+
+	jwtConfig := auth.JWTConfig{
+		Secret:     []byte(config.JWTSecret()),
+		PublicKeys: map[string]*rsa.PublicKey{},
+		Issuer:     config.JWTIssuer(),
+		Audience:   config.JWTAudience(),
+	}
+
+	if path := config.JWTPublicKeyFile(); path != "" {
+		key, err := auth.LoadRSAPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		jwtConfig.PublicKeys[""] = key
+	}
+
+	if path := config.JWKSFile(); path != "" {
+		keys, err := auth.LoadJWKS(path)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(jwtConfig.PublicKeys, keys)
+	}
+
+	var verifier *auth.JWTVerifier
+	if len(jwtConfig.Secret) > 0 || len(jwtConfig.PublicKeys) > 0 {
+		var err error
+		if verifier, err = auth.NewJWTVerifier(jwtConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	var apiKeys *auth.APIKeys
+	if path := config.APIKeysFile(); path != "" {
+		var err error
+		if apiKeys, err = auth.LoadAPIKeys(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var clientCertificates *auth.ClientCertificates
+	if path := config.ClientCertificatesFile(); path != "" {
+		var err error
+		if clientCertificates, err = auth.LoadClientCertificates(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if verifier == nil && apiKeys == nil && clientCertificates == nil {
+		return nil, errors.New("no authentication configured: set AUTH_JWT_SECRET, AUTH_JWT_PUBLIC_KEY_FILE, AUTH_JWKS_FILE, AUTH_API_KEYS_FILE or AUTH_CLIENT_CERTIFICATES_FILE (or AUTH_DISABLED=true for local development)")
+	}
+
+	return auth.NewAuthenticator(verifier, apiKeys, clientCertificates), nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+
+	"gin-quickstart/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed static/docs.html
+var docsPage []byte
+
+func DocsRoutes(router *gin.Engine, spec *openapi.Document) {
+	router.GET("/openapi.json", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, spec)
+	})
+
+	router.GET("/docs", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+	})
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"errors"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"log"
+)
+
+// Error é um erro de resolver com os mesmos códigos das respostas de erro
+// da API REST (not_found, forbidden, ...) em extensions.code.
+type Error struct {
+	Code       string
+	Message    string
+	Field      string
+	Permission string
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Extensions implementa gqlerrors.ExtendedError.
+func (err *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": err.Code}
+	if err.Field != "" {
+		extensions["field"] = err.Field
+	}
+	if err.Permission != "" {
+		extensions["permission"] = err.Permission
+	}
+	return extensions
+}
+
+// graphError traduz os erros de domínio e de repositório, como o
+// errorStatus dos controllers.
+func graphError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var validationError *entities.ValidationError
+	var forbiddenError *authz.ForbiddenError
+
+	switch {
+	case errors.As(err, &validationError):
+		return &Error{Code: "validation_failed", Message: validationError.Message, Field: validationError.Field}
+	case errors.As(err, &forbiddenError):
+		return &Error{Code: "forbidden", Message: forbiddenError.Error(), Permission: string(forbiddenError.Permission)}
+	case errors.Is(err, authz.ErrUnauthenticated):
+		return &Error{Code: "unauthorized", Message: err.Error()}
+	case errors.Is(err, repositories.ErrCategoryNotFound):
+		return &Error{Code: "not_found", Message: err.Error()}
+	case errors.Is(err, repositories.ErrVersionConflict),
+		errors.Is(err, repositories.ErrDuplicateName):
+		return &Error{Code: "conflict", Message: err.Error()}
+	default:
+		log.Printf("graphql: %v", err)
+		return &Error{Code: "internal_error", Message: "Internal Server Error"}
+	}
+}
@@ -0,0 +1,115 @@
+// Package graph serve o endpoint GraphQL de categorias, resolvido pelos
+// mesmos use cases da API REST.
+package graph
+
+import (
+	"context"
+	"errors"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// response documenta o formato do GraphQL sobre HTTP.
+type response struct {
+	Data   map[string]any `json:"data,omitempty"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path,omitempty"`
+		Extensions map[string]any `json:"extensions,omitempty"`
+	} `json:"errors,omitempty"`
+}
+
+var Doc = openapi.Operation{
+	ID:      "graphql",
+	Summary: "Consulta e altera categorias via GraphQL",
+	Tags:    []string{"graphql"},
+	Request: request{},
+	Responses: map[int]openapi.Response{
+		http.StatusOK:         {Description: "Resultado da execução; erros dos resolvers vêm em errors, com extensions.code", Body: response{}},
+		http.StatusBadRequest: {Description: "Consulta inválida ou acima dos limites de profundidade e complexidade", Body: response{}},
+	},
+}
+
+type Handler struct {
+	schema     graphql.Schema
+	repository repositories.ICategoryRepository
+	auditLog   audit.IStore
+	limits     Limits
+}
+
+func NewHandler(repository repositories.ICategoryRepository, auditLog audit.IStore, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{schema: schema, repository: repository, auditLog: auditLog, limits: limits}, nil
+}
+
+// Serve executa a consulta. Erros de sintaxe, de validação contra o schema
+// e de limites respondem 400 sem executar nada; o resto responde 200, com
+// os erros dos resolvers ao lado dos dados parciais.
+func (handler *Handler) Serve(context *gin.Context) {
+	var body request
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"errors": []gqlerrors.FormattedError{requestError(err)}})
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(body.Query), Name: "GraphQL request"})})
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"errors": gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&handler.schema, document, nil); !validation.IsValid {
+		context.JSON(http.StatusBadRequest, gin.H{"errors": validation.Errors})
+		return
+	}
+
+	if err := handler.limits.check(handler.schema, document, body.OperationName); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"errors": []gqlerrors.FormattedError{requestError(err)}})
+		return
+	}
+
+	ctx := withLoaders(context.Request.Context(), newLoaders(context.Request.Context(), handler.repository, handler.auditLog))
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        handler.schema,
+		AST:           document,
+		OperationName: body.OperationName,
+		Args:          body.Variables,
+		Context:       ctx,
+	})
+
+	context.JSON(http.StatusOK, result)
+}
+
+func withLoaders(ctx context.Context, requestLoaders *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, requestLoaders)
+}
+
+func requestError(err error) gqlerrors.FormattedError {
+	code := "invalid_request"
+
+	var limitError *LimitError
+	if errors.As(err, &limitError) {
+		code = "query_too_complex"
+	}
+
+	return gqlerrors.FormattedError{Message: err.Error(), Extensions: map[string]any{"code": code}}
+}
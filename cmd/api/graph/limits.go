@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ListMultiplier é quantos itens se estima por campo de lista no cálculo
+// da complexidade: sem paginação no schema, cada lista pode trazer muitos.
+const ListMultiplier = 10
+
+// Limits recusa consultas caras antes de executá-las. MaxDepth limita o
+// aninhamento de campos; MaxComplexity, a soma de um ponto por campo, com
+// os campos dentro de listas multiplicados por ListMultiplier. Zero
+// desliga o limite. Campos de introspecção (__schema, __type) não contam.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+var DefaultLimits = Limits{MaxDepth: 6, MaxComplexity: 1000}
+
+// LimitError indica uma consulta acima dos Limits.
+type LimitError struct {
+	Limit string
+	Value int
+	Max   int
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("query %s %d exceeds the maximum of %d", err.Limit, err.Value, err.Max)
+}
+
+// check mede a operação que será executada.
+func (limits Limits) check(schema graphql.Schema, document *ast.Document, operationName string) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok || (operationName != "" && (operation.Name == nil || operation.Name.Value != operationName)) {
+			continue
+		}
+
+		root := schema.QueryType()
+		if operation.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+		}
+
+		measure := measurer{schema: schema, fragments: fragments}
+		complexity, depth := measure.selectionSet(operation.SelectionSet, root)
+
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return &LimitError{Limit: "depth", Value: depth, Max: limits.MaxDepth}
+		}
+		if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+			return &LimitError{Limit: "complexity", Value: complexity, Max: limits.MaxComplexity}
+		}
+	}
+
+	return nil
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// visiting evita laços entre fragmentos; a validação já os recusa, mas
+	// a medida não depende dela
+	visiting map[string]bool
+}
+
+// selectionSet devolve a complexidade e a profundidade da seleção sobre
+// parent.
+func (measure *measurer) selectionSet(selectionSet *ast.SelectionSet, parent graphql.Type) (complexity, depth int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+
+	for _, selection := range selectionSet.Selections {
+		var cost, nested int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost, nested = measure.field(selection, parent)
+		case *ast.InlineFragment:
+			cost, nested = measure.selectionSet(selection.SelectionSet, measure.typeCondition(selection.TypeCondition, parent))
+		case *ast.FragmentSpread:
+			fragment, ok := measure.fragments[selection.Name.Value]
+			if !ok || measure.visiting[fragment.Name.Value] {
+				continue
+			}
+			if measure.visiting == nil {
+				measure.visiting = map[string]bool{}
+			}
+			measure.visiting[fragment.Name.Value] = true
+			cost, nested = measure.selectionSet(fragment.SelectionSet, measure.typeCondition(fragment.TypeCondition, parent))
+			delete(measure.visiting, fragment.Name.Value)
+		}
+
+		complexity += cost
+		depth = max(depth, nested)
+	}
+
+	return complexity, depth
+}
+
+func (measure *measurer) field(field *ast.Field, parent graphql.Type) (complexity, depth int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+
+	fieldType, list := measure.fieldType(parent, field.Name.Value)
+	children, nested := measure.selectionSet(field.SelectionSet, fieldType)
+
+	if list {
+		children *= ListMultiplier
+	}
+
+	return 1 + children, 1 + nested
+}
+
+// fieldType devolve o tipo nomeado do campo e se ele é uma lista.
+func (measure *measurer) fieldType(parent graphql.Type, name string) (graphql.Type, bool) {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil, false
+	}
+
+	definition, ok := object.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+
+	fieldType, list := definition.Type, false
+	for {
+		switch wrapped := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = wrapped.OfType
+		case *graphql.List:
+			fieldType, list = wrapped.OfType, true
+		default:
+			return fieldType, list
+		}
+	}
+}
+
+func (measure *measurer) typeCondition(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	return measure.schema.Type(condition.Name.Value)
+}
@@ -0,0 +1,66 @@
+package graph
+
+import "sync"
+
+// Loader junta as chaves pedidas pelos resolvers de uma requisição e as
+// busca numa só chamada a fetch, no estilo do DataLoader. Load não busca
+// nada: devolve um thunk, e o executor do graphql-go só chama os thunks
+// depois de resolver todos os campos do mesmo nível, então o primeiro
+// thunk chamado busca as chaves de todos os irmãos de uma vez. Os
+// resultados ficam em cache até o fim da requisição.
+type Loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	mutex   sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errors  map[K]error
+}
+
+// NewLoader recebe a busca em lote; chaves ausentes do map devolvido
+// resolvem para o valor zero de V.
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		queued:  make(map[K]bool),
+		results: make(map[K]V),
+		errors:  make(map[K]error),
+	}
+}
+
+func (loader *Loader[K, V]) Load(key K) func() (V, error) {
+	loader.mutex.Lock()
+	if !loader.queued[key] {
+		loader.queued[key] = true
+		loader.pending = append(loader.pending, key)
+	}
+	loader.mutex.Unlock()
+
+	return func() (V, error) {
+		loader.mutex.Lock()
+		defer loader.mutex.Unlock()
+
+		if len(loader.pending) > 0 {
+			loader.dispatch()
+		}
+
+		return loader.results[key], loader.errors[key]
+	}
+}
+
+// dispatch busca as chaves pendentes; um erro vale para todas elas.
+func (loader *Loader[K, V]) dispatch() {
+	keys := loader.pending
+	loader.pending = nil
+
+	results, err := loader.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			loader.errors[key] = err
+			continue
+		}
+		if value, ok := results[key]; ok {
+			loader.results[key] = value
+		}
+	}
+}
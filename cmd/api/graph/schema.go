@@ -0,0 +1,270 @@
+package graph
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	use_cases "gin-quickstart/internal/use-cases"
+	"slices"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+)
+
+// loaders são os Loaders de uma requisição, guardados no context.
+type loaders struct {
+	categories *Loader[uint, *entities.Category]
+	children   *Loader[uint, []*entities.Category]
+	history    *Loader[uint, []*audit.Entry]
+}
+
+type contextKey int
+
+const loadersKey contextKey = iota
+
+func newLoaders(ctx context.Context, repository repositories.ICategoryRepository, auditLog audit.IStore) *loaders {
+	return &loaders{
+		categories: NewLoader(func(ids []uint) (map[uint]*entities.Category, error) {
+			return use_cases.NewGetCategoriesUseCase(repository).Execute(ctx, ids)
+		}),
+		children: NewLoader(func(ids []uint) (map[uint][]*entities.Category, error) {
+			return use_cases.NewGetChildrenUseCase(repository).Execute(ctx, ids)
+		}),
+		history: NewLoader(func(ids []uint) (map[uint][]*audit.Entry, error) {
+			return use_cases.NewCategoriesHistoryUseCase(auditLog).Execute(ctx, ids)
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+// NewSchema monta o schema de categorias. Os resolvers chamam os mesmos
+// use cases da API REST, com o context da requisição (principal, política
+// e tenant).
+func NewSchema(repository repositories.ICategoryRepository) (graphql.Schema, error) {
+	snapshotType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CategorySnapshot",
+		Description: "A categoria como estava antes ou depois de uma alteração",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: categoryField(func(c *entities.Category) any { return strconv.FormatUint(uint64(c.ID), 10) })},
+			"name":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: categoryField(func(c *entities.Category) any { return c.Name })},
+			"version": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: categoryField(func(c *entities.Category) any { return c.Version })},
+		},
+	})
+
+	auditEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AuditEntry",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: entryField(func(e *audit.Entry) any { return strconv.FormatUint(uint64(e.ID), 10) })},
+			"action":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: entryField(func(e *audit.Entry) any { return string(e.Action) })},
+			"actor":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: entryField(func(e *audit.Entry) any { return e.Actor })},
+			"requestId": &graphql.Field{Type: graphql.String, Resolve: entryField(func(e *audit.Entry) any { return e.RequestID })},
+			"before":    &graphql.Field{Type: snapshotType, Resolve: entryField(func(e *audit.Entry) any { return e.Before })},
+			"after":     &graphql.Field{Type: snapshotType, Resolve: entryField(func(e *audit.Entry) any { return e.After })},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: entryField(func(e *audit.Entry) any { return e.CreatedAt })},
+		},
+	})
+
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: categoryField(func(c *entities.Category) any { return strconv.FormatUint(uint64(c.ID), 10) })},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: categoryField(func(c *entities.Category) any { return c.Name })},
+			"parentId":  &graphql.Field{Type: graphql.ID, Resolve: categoryField(func(c *entities.Category) any { return optionalID(c.ParentID) })},
+			"version":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: categoryField(func(c *entities.Category) any { return c.Version })},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: categoryField(func(c *entities.Category) any { return c.CreatedAt })},
+			"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: categoryField(func(c *entities.Category) any { return c.UpdatedAt })},
+			"history": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(auditEntryType))),
+				Description: "Alterações da categoria em ordem cronológica, buscadas em lote para todas as categorias da resposta",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					load := loadersFrom(p.Context).history.Load(p.Source.(*entities.Category).ID)
+					return func() (any, error) {
+						entries, err := load()
+						if err != nil {
+							return nil, graphError(err)
+						}
+						if entries == nil {
+							entries = []*audit.Entry{}
+						}
+						return entries, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	// children referencia o próprio tipo, então entra depois da criação
+	categoryType.AddFieldConfig("children", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+		Description: "Categorias logo abaixo desta, em ordem de ID, buscadas em lote para todas as categorias do mesmo nível da resposta",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			load := loadersFrom(p.Context).children.Load(p.Source.(*entities.Category).ID)
+			return func() (any, error) {
+				children, err := load()
+				if err != nil {
+					return nil, graphError(err)
+				}
+				if children == nil {
+					children = []*entities.Category{}
+				}
+				return children, nil
+			}, nil
+		},
+	})
+
+	idArgument := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	versionArgument := &graphql.ArgumentConfig{Type: graphql.Int, Description: "Versão esperada; sem ela vale a atual"}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"categories": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Description: "Todas as categorias ou, com roots, só as da raiz, para descer a árvore por children",
+				Args:        graphql.FieldConfigArgument{"roots": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					categories, err := use_cases.NewListCategoriesUseCase(repository).Execute(p.Context)
+					if err != nil {
+						return nil, graphError(err)
+					}
+					if roots, _ := p.Args["roots"].(bool); roots {
+						categories = slices.DeleteFunc(categories, func(category *entities.Category) bool { return category.ParentID != nil })
+					}
+					return categories, nil
+				},
+			},
+			"category": &graphql.Field{
+				Type:        categoryType,
+				Description: "A categoria, ou null se ela não existir. Vários category na mesma consulta são buscados juntos",
+				Args:        graphql.FieldConfigArgument{"id": idArgument},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					load := loadersFrom(p.Context).categories.Load(id)
+					return func() (any, error) {
+						category, err := load()
+						if err != nil {
+							return nil, graphError(err)
+						}
+						if category == nil {
+							return nil, nil
+						}
+						return category, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCategory": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Args: graphql.FieldConfigArgument{"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					category, err := use_cases.NewCreateCategoryUseCase(repository).Execute(p.Context, p.Args["name"].(string))
+					return category, graphError(err)
+				},
+			},
+			"updateCategory": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Args: graphql.FieldConfigArgument{"id": idArgument, "name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}, "version": versionArgument},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					version, _ := p.Args["version"].(int)
+					category, err := use_cases.NewUpdateCategoryUseCase(repository).Execute(p.Context, id, p.Args["name"].(string), uint(max(version, 0)))
+					return category, graphError(err)
+				},
+			},
+			"moveCategory": &graphql.Field{
+				Type:        graphql.NewNonNull(categoryType),
+				Description: "Coloca a categoria abaixo de parentId; sem parentId ela vai para a raiz",
+				Args:        graphql.FieldConfigArgument{"id": idArgument, "parentId": &graphql.ArgumentConfig{Type: graphql.ID}, "version": versionArgument},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					var parentID *uint
+					if value, ok := p.Args["parentId"].(string); ok {
+						parent, err := strconv.ParseUint(value, 10, 64)
+						if err != nil {
+							return nil, &Error{Code: "invalid_request", Message: "parentId must be a positive integer", Field: "parentId"}
+						}
+						parentID = new(uint)
+						*parentID = uint(parent)
+					}
+					version, _ := p.Args["version"].(int)
+					category, err := use_cases.NewMoveCategoryUseCase(repository).Execute(p.Context, id, parentID, uint(max(version, 0)))
+					return category, graphError(err)
+				},
+			},
+			"deleteCategory": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": idArgument, "version": versionArgument},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					version, _ := p.Args["version"].(int)
+					if err := use_cases.NewDeleteCategoryUseCase(repository).Execute(p.Context, id, uint(max(version, 0))); err != nil {
+						return nil, graphError(err)
+					}
+					return true, nil
+				},
+			},
+			"restoreCategory": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Args: graphql.FieldConfigArgument{"id": idArgument},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+					category, err := use_cases.NewRestoreCategoryUseCase(repository).Execute(p.Context, id)
+					return category, graphError(err)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func categoryField(get func(category *entities.Category) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*entities.Category)), nil
+	}
+}
+
+func entryField(get func(entry *audit.Entry) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*audit.Entry)), nil
+	}
+}
+
+func optionalID(id *uint) any {
+	if id == nil {
+		return nil
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func idArg(p graphql.ResolveParams) (uint, error) {
+	id, err := strconv.ParseUint(p.Args["id"].(string), 10, 64)
+	if err != nil {
+		return 0, &Error{Code: "invalid_request", Message: "id must be a positive integer", Field: "id"}
+	}
+	return uint(id), nil
+}
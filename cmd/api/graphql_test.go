@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countingStore conta as chamadas em lote que os loaders fazem.
+type countingStore struct {
+	repositories.ICategoryRepository
+	findByIDs    *int
+	findChildren *int
+}
+
+func (store countingStore) ForTenant(tenant string) repositories.ICategoryRepository {
+	store.ICategoryRepository = store.ICategoryRepository.ForTenant(tenant)
+	return store
+}
+
+func (store countingStore) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	*store.findByIDs++
+	return store.ICategoryRepository.FindByIDs(ids)
+}
+
+func (store countingStore) FindChildren(parentIDs []uint) ([]*entities.Category, error) {
+	*store.findChildren++
+	return store.ICategoryRepository.FindChildren(parentIDs)
+}
+
+type countingAuditLog struct {
+	audit.IStore
+	listByCategories int
+}
+
+func (store *countingAuditLog) ListByCategories(tenant string, categoryIDs []uint) (map[uint][]*audit.Entry, error) {
+	store.listByCategories++
+	return store.IStore.ListByCategories(tenant, categoryIDs)
+}
+
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func graphqlServer(t *testing.T, deps *dependencies) func(query string) (int, graphqlResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(deps)
+
+	return func(query string) (int, graphqlResponse) {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+
+		var response graphqlResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Error decoding %s: %v", recorder.Body.String(), err)
+		}
+		return recorder.Code, response
+	}
+}
+
+func TestGraphQL_BatchesNestedLookups(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	findByIDs := 0
+	auditLog := &countingAuditLog{IStore: deps.auditLog}
+	deps.categoryRepository = countingStore{ICategoryRepository: deps.categoryRepository, findByIDs: &findByIDs}
+	deps.auditLog = auditLog
+	serve := graphqlServer(t, deps)
+
+	for _, name := range []string{"Electronics", "Literature", "Outdoors"} {
+		if code, response := serve(`mutation { createCategory(name: "` + name + `") { id } }`); code != http.StatusOK || len(response.Errors) > 0 {
+			t.Fatalf("Expected %s created, got %d %v", name, code, response.Errors)
+		}
+	}
+
+	// Act
+	code, listed := serve(`{ categories { name history { action actor } } }`)
+	_, aliased := serve(`{ a: category(id: "1") { name history { action } } b: category(id: "3") { name } missing: category(id: "42") { name } }`)
+
+	// Assert
+	if code != http.StatusOK || len(listed.Errors) > 0 {
+		t.Fatalf("Expected 200 without errors, got %d %v", code, listed.Errors)
+	}
+
+	if categories := string(listed.Data["categories"]); strings.Count(categories, `"action":"create"`) != 3 {
+		t.Errorf("Expected each category with its create entry, got %s", categories)
+	}
+
+	if string(aliased.Data["a"]) != `{"history":[{"action":"create"}],"name":"Electronics"}` || string(aliased.Data["b"]) != `{"name":"Outdoors"}` || string(aliased.Data["missing"]) != "null" {
+		t.Errorf("Expected both categories and null for the missing one, got %v", aliased.Data)
+	}
+
+	if findByIDs != 1 {
+		t.Errorf("Expected the three category lookups in one FindByIDs, got %d calls", findByIDs)
+	}
+
+	if auditLog.listByCategories != 2 {
+		t.Errorf("Expected one ListByCategories per query, got %d calls", auditLog.listByCategories)
+	}
+}
+
+func TestGraphQL_NestedChildrenOneLookupPerLevel(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	findByIDs, findChildren := 0, 0
+	deps.categoryRepository = countingStore{ICategoryRepository: deps.categoryRepository, findByIDs: &findByIDs, findChildren: &findChildren}
+	serve := graphqlServer(t, deps)
+
+	for _, name := range []string{"Electronics", "Outdoors", "Phones", "Laptops", "Phone cases", "Tents"} {
+		serve(`mutation { createCategory(name: "` + name + `") { id } }`)
+	}
+	for _, move := range [][2]string{{"3", "1"}, {"4", "1"}, {"5", "3"}, {"6", "2"}} {
+		if _, response := serve(`mutation { moveCategory(id: "` + move[0] + `", parentId: "` + move[1] + `") { parentId } }`); len(response.Errors) > 0 {
+			t.Fatalf("Expected category %s moved, got %v", move[0], response.Errors)
+		}
+	}
+	findChildren = 0
+
+	// Act
+	code, tree := serve(`{ category(id: "1") { name children { name children { name } } } }`)
+	_, roots := serve(`{ categories(roots: true) { name children { name } } }`)
+	_, cycle := serve(`mutation { moveCategory(id: "1", parentId: "5") { id } }`)
+	_, toRoot := serve(`mutation { moveCategory(id: "5") { parentId } }`)
+
+	// Assert
+	if code != http.StatusOK || len(tree.Errors) > 0 {
+		t.Fatalf("Expected 200 without errors, got %d %v", code, tree.Errors)
+	}
+
+	expected := `{"children":[{"children":[{"name":"Phone cases"}],"name":"Phones"},{"children":[],"name":"Laptops"}],"name":"Electronics"}`
+	if string(tree.Data["category"]) != expected {
+		t.Errorf("Expected the tree %s, got %s", expected, tree.Data["category"])
+	}
+
+	expected = `[{"children":[{"name":"Phones"},{"name":"Laptops"}],"name":"Electronics"},{"children":[{"name":"Tents"}],"name":"Outdoors"}]`
+	if string(roots.Data["categories"]) != expected {
+		t.Errorf("Expected only the roots %s, got %s", expected, roots.Data["categories"])
+	}
+
+	// Dois níveis na primeira consulta e um na segunda
+	if findChildren != 3 {
+		t.Errorf("Expected one FindChildren per level, got %d calls", findChildren)
+	}
+
+	if len(cycle.Errors) != 1 || cycle.Errors[0].Extensions["code"] != "validation_failed" {
+		t.Errorf("Expected a validation error for a cycle, got %v", cycle.Errors)
+	}
+
+	if string(toRoot.Data["moveCategory"]) != `{"parentId":null}` {
+		t.Errorf("Expected the category moved to the root, got %s", toRoot.Data["moveCategory"])
+	}
+}
+
+func TestGraphQL_MutationsAndErrors(t *testing.T) {
+	// Arrange
+	serve := graphqlServer(t, inMemoryDependencies())
+	serve(`mutation { createCategory(name: "Electronics") { id } }`)
+
+	// Act
+	_, renamed := serve(`mutation { updateCategory(id: "1", name: "Gadgets", version: 1) { name version } }`)
+	_, stale := serve(`mutation { updateCategory(id: "1", name: "Stale", version: 1) { name } }`)
+	_, invalid := serve(`mutation { createCategory(name: "") { id } }`)
+	_, deleted := serve(`mutation { deleteCategory(id: "1") }`)
+	_, missing := serve(`mutation { updateCategory(id: "1", name: "Gone") { name } }`)
+	_, restored := serve(`mutation { restoreCategory(id: "1") { name version } }`)
+
+	// Assert
+	if string(renamed.Data["updateCategory"]) != `{"name":"Gadgets","version":2}` {
+		t.Errorf("Expected the category renamed at version 2, got %v %v", renamed.Data, renamed.Errors)
+	}
+
+	for name, response := range map[string]graphqlResponse{"conflict": stale, "validation_failed": invalid, "not_found": missing} {
+		if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != name {
+			t.Errorf("Expected a %s error, got %v", name, response.Errors)
+		}
+	}
+
+	if invalid.Errors[0].Extensions["field"] != "name" {
+		t.Errorf("Expected the invalid field in the extensions, got %v", invalid.Errors[0].Extensions)
+	}
+
+	if string(deleted.Data["deleteCategory"]) != "true" {
+		t.Errorf("Expected deleteCategory true, got %v %v", deleted.Data, deleted.Errors)
+	}
+
+	if string(restored.Data["restoreCategory"]) != `{"name":"Gadgets","version":4}` {
+		t.Errorf("Expected the category restored at version 4, got %v %v", restored.Data, restored.Errors)
+	}
+}
+
+func TestGraphQL_RejectsInvalidAndExpensiveQueries(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	deps.graphqlLimits.MaxDepth = 3
+	deps.graphqlLimits.MaxComplexity = 50
+	serve := graphqlServer(t, deps)
+
+	cases := map[string]struct {
+		query string
+		code  string
+	}{
+		"syntax":     {`{ categories { name `, ""},
+		"unknown":    {`{ categories { color } }`, ""},
+		"depth":      {`{ categories { history { before { name } } } }`, "query_too_complex"},
+		"complexity": {`{ categories { id name version createdAt updatedAt history { action } } }`, "query_too_complex"},
+		"fragments":  {`{ categories { ...deep } } fragment deep on Category { history { after { name } } }`, "query_too_complex"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			code, response := serve(tc.query)
+
+			// Assert
+			if code != http.StatusBadRequest || len(response.Errors) == 0 {
+				t.Fatalf("Expected 400 with errors, got %d %v", code, response.Errors)
+			}
+			if tc.code != "" && response.Errors[0].Extensions["code"] != tc.code {
+				t.Errorf("Expected code %s, got %v", tc.code, response.Errors[0].Extensions)
+			}
+		})
+	}
+
+	// A introspecção não conta para os limites
+	if code, response := serve(`{ __schema { types { name fields { name type { name ofType { name } } } } } }`); code != http.StatusOK {
+		t.Errorf("Expected introspection allowed, got %d %v", code, response.Errors)
+	}
+}
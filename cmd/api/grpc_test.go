@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"gin-quickstart/cmd/api/rpc"
+	"gin-quickstart/cmd/api/rpc/categorypb"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/ratelimit"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tlsconfig"
+	"gin-quickstart/internal/tlsconfig/tlsconfigtest"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGRPCClient sobe o servidor gRPC das deps em memória (bufconn).
+func newGRPCClient(t *testing.T, deps *dependencies) categorypb.CategoryServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := newGRPCServer(deps, nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return categorypb.NewCategoryServiceClient(connection)
+}
+
+func TestGRPC_CategoryLifecycle(t *testing.T) {
+	// Arrange
+	client := newGRPCClient(t, inMemoryDependencies())
+	ctx := context.Background()
+
+	// Act
+	var header metadata.MD
+	created, err := client.CreateCategory(ctx, &categorypb.CreateCategoryRequest{Name: "Electronics"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("Expected no error creating, got %v", err)
+	}
+
+	got, _ := client.GetCategory(ctx, &categorypb.GetCategoryRequest{Id: created.Id})
+	renamed, _ := client.UpdateCategory(ctx, &categorypb.UpdateCategoryRequest{Id: created.Id, Name: "Gadgets & Gizmos", Version: created.Version})
+	_, staleErr := client.UpdateCategory(ctx, &categorypb.UpdateCategoryRequest{Id: created.Id, Name: "Stale rename", Version: created.Version})
+	_, deleteErr := client.DeleteCategory(ctx, &categorypb.DeleteCategoryRequest{Id: created.Id})
+	_, missingErr := client.GetCategory(ctx, &categorypb.GetCategoryRequest{Id: created.Id})
+
+	// Assert
+	if created.Id != 1 || created.Version != 1 || created.CreateTime == nil {
+		t.Errorf("Expected category 1 at version 1, got %v", created)
+	}
+
+	if len(header.Get(rpc.RequestIDKey)) != 1 {
+		t.Errorf("Expected an %s header, got %v", rpc.RequestIDKey, header)
+	}
+
+	if !proto.Equal(got, created) {
+		t.Errorf("Expected GetCategory to return %v, got %v", created, got)
+	}
+
+	if renamed.GetName() != "Gadgets & Gizmos" || renamed.GetVersion() != 2 {
+		t.Errorf("Expected the category renamed at version 2, got %v", renamed)
+	}
+
+	if status.Code(staleErr) != codes.Aborted {
+		t.Errorf("Expected ABORTED for a stale version, got %v", staleErr)
+	}
+
+	if deleteErr != nil || status.Code(missingErr) != codes.NotFound {
+		t.Errorf("Expected the category deleted, got %v and %v", deleteErr, missingErr)
+	}
+}
+
+func TestGRPC_ValidationErrorCarriesTheField(t *testing.T) {
+	client := newGRPCClient(t, inMemoryDependencies())
+
+	_, err := client.CreateCategory(context.Background(), &categorypb.CreateCategoryRequest{Name: "TV"})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+		t.Fatalf("Expected INVALID_ARGUMENT with details, got %v", err)
+	}
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || badRequest.FieldViolations[0].Field != "name" {
+		t.Errorf("Expected a field violation on name, got %v", st.Details())
+	}
+}
+
+// pagedStore falha List: a listagem paginada tem que buscar só a página.
+type pagedStore struct {
+	repositories.ICategoryRepository
+}
+
+func (store pagedStore) ForTenant(tenant string) repositories.ICategoryRepository {
+	return pagedStore{store.ICategoryRepository.ForTenant(tenant)}
+}
+
+func (pagedStore) List() ([]*entities.Category, error) {
+	return nil, errors.New("List must not be called to paginate")
+}
+
+func TestGRPC_ListCategoriesPaginates(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	deps.categoryRepository = pagedStore{deps.categoryRepository}
+	client := newGRPCClient(t, deps)
+	ctx := context.Background()
+	for _, name := range []string{"Electronics", "Home & Garden", "Furniture", "Books & Media", "Outdoor"} {
+		client.CreateCategory(ctx, &categorypb.CreateCategoryRequest{Name: name})
+	}
+
+	// Act
+	var names []string
+	pages := 0
+	request := &categorypb.ListCategoriesRequest{PageSize: 2}
+	for {
+		page, err := client.ListCategories(ctx, request)
+		if err != nil {
+			t.Fatalf("Expected no error listing, got %v", err)
+		}
+		pages++
+		if page.TotalSize != 5 {
+			t.Errorf("Expected total_size 5, got %d", page.TotalSize)
+		}
+		for _, category := range page.Categories {
+			names = append(names, category.Name)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		request.PageToken = page.NextPageToken
+	}
+
+	// Assert
+	if pages != 3 || len(names) != 5 || names[0] != "Electronics" || names[4] != "Outdoor" {
+		t.Errorf("Expected 5 categories in 3 pages, got %v in %d", names, pages)
+	}
+
+	if _, err := client.ListCategories(ctx, &categorypb.ListCategoriesRequest{PageToken: "not a token"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected INVALID_ARGUMENT for a bad page token, got %v", err)
+	}
+}
+
+func TestGRPC_SharesAuthenticationAndTenancy(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	editorKey, editorHash := auth.NewAPIKey()
+	viewerKey, viewerHash := auth.NewAPIKey()
+	unboundKey, unboundHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "importer", Hash: editorHash, Roles: []string{"editor"}, Tenant: "acme"},
+		auth.APIKey{Name: "dashboard", Hash: viewerHash, Roles: []string{"viewer"}, Tenant: auth.AnyTenant},
+		auth.APIKey{Name: "storefront", Hash: unboundHash, Roles: []string{"viewer"}},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	client := newGRPCClient(t, deps)
+
+	withKey := func(apiKey string, pairs ...string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), append([]string{"x-api-key", apiKey}, pairs...)...)
+	}
+
+	// Act
+	_, anonymousErr := client.ListCategories(context.Background(), &categorypb.ListCategoriesRequest{})
+	created, createErr := client.CreateCategory(withKey(editorKey), &categorypb.CreateCategoryRequest{Name: "Electronics"})
+	_, viewerCreateErr := client.CreateCategory(withKey(viewerKey), &categorypb.CreateCategoryRequest{Name: "Furniture"})
+	_, mismatchErr := client.ListCategories(withKey(editorKey, "x-tenant-id", "globex"), &categorypb.ListCategoriesRequest{})
+	otherTenant, _ := client.ListCategories(withKey(viewerKey), &categorypb.ListCategoriesRequest{})
+	sameTenant, _ := client.ListCategories(withKey(viewerKey, "x-tenant-id", "acme"), &categorypb.ListCategoriesRequest{})
+	_, unboundErr := client.ListCategories(withKey(unboundKey, "x-tenant-id", "acme"), &categorypb.ListCategoriesRequest{})
+
+	// Assert
+	if status.Code(anonymousErr) != codes.Unauthenticated {
+		t.Errorf("Expected UNAUTHENTICATED without credentials, got %v", anonymousErr)
+	}
+
+	if createErr != nil || created.Id == 0 {
+		t.Fatalf("Expected the editor to create, got %v", createErr)
+	}
+
+	if status.Code(viewerCreateErr) != codes.PermissionDenied {
+		t.Errorf("Expected PERMISSION_DENIED for a viewer creating, got %v", viewerCreateErr)
+	}
+
+	if status.Code(mismatchErr) != codes.PermissionDenied {
+		t.Errorf("Expected PERMISSION_DENIED for another tenant, got %v", mismatchErr)
+	}
+
+	if status.Code(unboundErr) != codes.PermissionDenied {
+		t.Errorf("Expected PERMISSION_DENIED for another tenant without a tenant in the credentials, got %v", unboundErr)
+	}
+
+	if len(otherTenant.GetCategories()) != 0 || len(sameTenant.GetCategories()) != 1 {
+		t.Errorf("Expected acme's category only in acme, got %v and %v", otherTenant, sameTenant)
+	}
+}
+
+func TestGRPC_RateLimit(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	scriptKey, scriptHash := auth.NewAPIKey()
+	otherKey, otherHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "script", Hash: scriptHash, Roles: []string{"editor"}},
+		auth.APIKey{Name: "other", Hash: otherHash, Roles: []string{"editor"}},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.Rules{
+		Default: ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Operations: map[string]ratelimit.Limit{
+			"createCategory":                {Requests: 1, Per: time.Minute},
+			ratelimit.AuthenticateOperation: {Requests: 4, Per: time.Minute},
+		},
+	})
+	client := newGRPCClient(t, deps)
+
+	withKey := func(apiKey string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", apiKey)
+	}
+
+	// Act
+	_, firstErr := client.CreateCategory(withKey(scriptKey), &categorypb.CreateCategoryRequest{Name: "Electronics"})
+	var header metadata.MD
+	_, limitedErr := client.CreateCategory(withKey(scriptKey), &categorypb.CreateCategoryRequest{Name: "Furniture"}, grpc.Header(&header))
+	_, otherErr := client.CreateCategory(withKey(otherKey), &categorypb.CreateCategoryRequest{Name: "Books"})
+	_, invalidErr := client.ListCategories(withKey("invalid"), &categorypb.ListCategoriesRequest{})
+	_, blockedErr := client.ListCategories(withKey(otherKey), &categorypb.ListCategoriesRequest{})
+
+	// Assert
+	if firstErr != nil || otherErr != nil {
+		t.Fatalf("Expected each client's first create to pass, got %v and %v", firstErr, otherErr)
+	}
+
+	if status.Code(limitedErr) != codes.ResourceExhausted {
+		t.Errorf("Expected RESOURCE_EXHAUSTED for the script's second create, got %v", limitedErr)
+	}
+
+	if retryAfter := header.Get("retry-after"); len(retryAfter) != 1 || retryAfter[0] != "60" {
+		t.Errorf("Expected retry-after 60, got %v", header)
+	}
+
+	if status.Code(invalidErr) != codes.Unauthenticated {
+		t.Errorf("Expected UNAUTHENTICATED for an invalid key within the IP limit, got %v", invalidErr)
+	}
+
+	if status.Code(blockedErr) != codes.ResourceExhausted {
+		t.Errorf("Expected RESOURCE_EXHAUSTED once the IP spent its authenticate quota, got %v", blockedErr)
+	}
+}
+
+func TestGRPC_MutualTLSAuthenticatesServices(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, ca.Server(t))
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: ca.WriteCA(t, dir), ClientAuth: tlsconfig.ClientAuthOptional})
+	if err != nil {
+		t.Fatalf("Error loading TLS: %v", err)
+	}
+
+	clientCertificates, _ := auth.NewClientCertificates(auth.ClientCertificate{Subject: "billing", Name: "billing-service", Roles: []string{"viewer"}})
+	deps := inMemoryDependencies()
+	deps.authenticator = auth.NewAuthenticator(nil, nil, clientCertificates)
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	server := newGRPCServer(deps, reloader.TLSConfig())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connect := func(certificates ...tls.Certificate) categorypb.CategoryServiceClient {
+		connection, err := grpc.NewClient(listener.Addr().String(),
+			grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: ca.Pool(), Certificates: certificates})),
+		)
+		if err != nil {
+			t.Fatalf("Error connecting: %v", err)
+		}
+		t.Cleanup(func() { connection.Close() })
+		return categorypb.NewCategoryServiceClient(connection)
+	}
+	billing := connect(ca.Client(t, pkix.Name{CommonName: "billing"}))
+
+	// Act
+	_, readErr := billing.ListCategories(context.Background(), &categorypb.ListCategoriesRequest{})
+	_, createErr := billing.CreateCategory(context.Background(), &categorypb.CreateCategoryRequest{Name: "Electronics"})
+	_, anonymousErr := connect().ListCategories(context.Background(), &categorypb.ListCategoriesRequest{})
+
+	// Assert
+	if readErr != nil {
+		t.Errorf("Expected the viewer certificate to read, got %v", readErr)
+	}
+
+	if status.Code(createErr) != codes.PermissionDenied {
+		t.Errorf("Expected PERMISSION_DENIED for the viewer certificate creating, got %v", createErr)
+	}
+
+	if status.Code(anonymousErr) != codes.Unauthenticated {
+		t.Errorf("Expected UNAUTHENTICATED without a certificate, got %v", anonymousErr)
+	}
+}
+
+func TestGRPC_WatchCategories(t *testing.T) {
+	// Arrange
+	deps := inMemoryDependencies()
+	client := newGRPCClient(t, deps)
+	create := func(ctx context.Context, name string) {
+		t.Helper()
+		if _, err := client.CreateCategory(ctx, &categorypb.CreateCategoryRequest{Name: name}); err != nil {
+			t.Fatalf("Error creating %s: %v", name, err)
+		}
+		if _, err := deps.outboxRelay.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("Error dispatching outbox: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	create(ctx, "Electronics")
+
+	// Act
+	watch, err := client.WatchCategories(ctx, &categorypb.WatchCategoriesRequest{LastEventId: proto.Uint64(0)})
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+
+	// Assert
+	// last_event_id 0 reenvia o evento publicado antes da chamada
+	replayed, err := watch.Recv()
+	if err != nil || replayed.Id != 1 || replayed.Event != "category.created" || replayed.Data.Fields["name"].GetStringValue() != "Electronics" {
+		t.Fatalf("Expected the replayed category.created, got %v (%v)", replayed, err)
+	}
+
+	// Eventos de outro tenant não chegam
+	create(metadata.AppendToOutgoingContext(ctx, "x-tenant-id", "acme"), "Furniture")
+	create(ctx, "Home & Garden")
+
+	next, err := watch.Recv()
+	if err != nil || next.Data.Fields["name"].GetStringValue() != "Home & Garden" {
+		t.Errorf("Expected only the default tenant's event, got %v (%v)", next, err)
+	}
+}
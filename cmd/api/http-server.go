@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"gin-quickstart/internal/config"
+	"gin-quickstart/internal/tlsconfig"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// httpServer serve o router em HTTP/1.1 e HTTP/2 sobre TLS e, com HTTP/3
+// habilitado, também em QUIC na mesma porta em UDP. As respostas que não
+// vêm por HTTP/3 anunciam o QUIC no Alt-Svc, e os clientes que o suportam
+// migram nas próximas requisições.
+type httpServer struct {
+	tcp  *http.Server
+	quic *http3.Server
+}
+
+func newHTTPServer(handler http.Handler, tlsConfig *tls.Config, http3Enabled bool) *httpServer {
+	server := &httpServer{tcp: &http.Server{Handler: handler, TLSConfig: tlsConfig}}
+
+	if http3Enabled {
+		server.quic = &http3.Server{Handler: handler, TLSConfig: tlsConfig}
+		server.tcp.Handler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// Sem listener QUIC ainda não há porta para anunciar; o erro só
+			// significa que não há Alt-Svc nesta resposta
+			server.quic.SetQUICHeaders(writer.Header())
+			handler.ServeHTTP(writer, request)
+		})
+	}
+
+	return server
+}
+
+// Serve atende o listener TCP e, com HTTP/3, a conexão UDP; devolve o
+// primeiro erro de qualquer um deles.
+func (server *httpServer) Serve(listener net.Listener, packetConn net.PacketConn) error {
+	if server.tcp.TLSConfig == nil {
+		return server.tcp.Serve(listener)
+	}
+
+	if server.quic == nil {
+		return server.tcp.ServeTLS(listener, "", "")
+	}
+
+	errs := make(chan error, 2)
+	go func() { errs <- server.quic.Serve(packetConn) }()
+	go func() { errs <- server.tcp.ServeTLS(listener, "", "") }()
+
+	return <-errs
+}
+
+func (server *httpServer) Close() error {
+	err := server.tcp.Close()
+	if server.quic != nil {
+		err = errors.Join(err, server.quic.Close())
+	}
+	return err
+}
+
+// newTLSReloader monta o TLS a partir das variáveis TLS_*; sem
+// certificado, a API fica em HTTP e o reloader é nil.
+func newTLSReloader() (*tlsconfig.Reloader, error) {
+	clientAuth, err := tlsconfig.ParseClientAuth(config.TLSClientAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	if config.TLSCertFile() == "" && config.TLSKeyFile() == "" {
+		if clientAuth != tlsconfig.ClientAuthNone {
+			return nil, errors.New("TLS_CLIENT_AUTH requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+
+	return tlsconfig.NewReloader(tlsconfig.Options{
+		CertFile:     config.TLSCertFile(),
+		KeyFile:      config.TLSKeyFile(),
+		ClientCAFile: config.TLSClientCAFile(),
+		ClientAuth:   clientAuth,
+	})
+}
+
+func serveHTTP(handler http.Handler, address string, tlsConfig *tls.Config, http3Enabled bool) {
+	if http3Enabled && tlsConfig == nil {
+		log.Fatal("HTTP/3 requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var packetConn net.PacketConn
+	if http3Enabled {
+		if packetConn, err = net.ListenPacket("udp", address); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("HTTP/3 listening on %s (udp)", address)
+	}
+
+	log.Printf("HTTP listening on %s", address)
+	if err := newHTTPServer(handler, tlsConfig, http3Enabled).Serve(listener, packetConn); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/cmd/api/middlewares"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/tlsconfig"
+	"gin-quickstart/internal/tlsconfig/tlsconfigtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quic-go/quic-go/http3"
+)
+
+func TestHTTPServer_AdvertisesAndServesHTTP3(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certificate, pool := ca.Server(t), ca.Pool()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on tcp: %v", err)
+	}
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on udp: %v", err)
+	}
+	t.Cleanup(func() { packetConn.Close() })
+
+	server := newHTTPServer(router, &tls.Config{Certificates: []tls.Certificate{certificate}}, true)
+	go server.Serve(listener, packetConn)
+	t.Cleanup(func() { server.Close() })
+
+	tcpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}, ForceAttemptHTTP2: true}}
+	quicTransport := &http3.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	t.Cleanup(func() { quicTransport.Close() })
+	quicClient := &http.Client{Transport: quicTransport, Timeout: 5 * time.Second}
+
+	quicPort := packetConn.LocalAddr().(*net.UDPAddr).Port
+
+	// Act
+	overTCP, err := tcpClient.Get("https://" + listener.Addr().String() + "/helthz")
+	if err != nil {
+		t.Fatalf("Expected no error over TLS, got %v", err)
+	}
+	overTCP.Body.Close()
+
+	overQUIC, err := quicClient.Get(fmt.Sprintf("https://127.0.0.1:%d/helthz", quicPort))
+	if err != nil {
+		t.Fatalf("Expected no error over QUIC, got %v", err)
+	}
+	overQUIC.Body.Close()
+
+	// Assert
+	if overTCP.StatusCode != http.StatusOK || overTCP.ProtoMajor != 2 {
+		t.Errorf("Expected 200 over HTTP/2, got %d over %s", overTCP.StatusCode, overTCP.Proto)
+	}
+
+	if expected := fmt.Sprintf(`h3=":%d"; ma=2592000`, quicPort); overTCP.Header.Get("Alt-Svc") != expected {
+		t.Errorf("Expected Alt-Svc %s, got %q", expected, overTCP.Header.Get("Alt-Svc"))
+	}
+
+	if overQUIC.StatusCode != http.StatusOK || overQUIC.ProtoMajor != 3 {
+		t.Errorf("Expected 200 over HTTP/3, got %d over %s", overQUIC.StatusCode, overQUIC.Proto)
+	}
+
+	if overQUIC.Header.Get(middlewares.RequestIDHeader) == "" {
+		t.Error("Expected the HTTP/3 request to go through the gin middlewares")
+	}
+}
+
+func TestHTTPServer_WithoutHTTP3HasNoAltSvc(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certificate, pool := ca.Server(t), ca.Pool()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on tcp: %v", err)
+	}
+
+	server := newHTTPServer(router, &tls.Config{Certificates: []tls.Certificate{certificate}}, false)
+	go server.Serve(listener, nil)
+	t.Cleanup(func() { server.Close() })
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	// Act
+	response, err := client.Get("https://" + listener.Addr().String() + "/helthz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response.Body.Close()
+
+	// Assert
+	if response.StatusCode != http.StatusOK || response.Header.Get("Alt-Svc") != "" {
+		t.Errorf("Expected 200 without Alt-Svc, got %d %q", response.StatusCode, response.Header.Get("Alt-Svc"))
+	}
+}
+
+func TestHTTPServer_MutualTLSAuthenticatesServices(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, ca.Server(t))
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: ca.WriteCA(t, dir), ClientAuth: tlsconfig.ClientAuthOptional})
+	if err != nil {
+		t.Fatalf("Error loading TLS: %v", err)
+	}
+
+	apiKey, hash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(auth.APIKey{Name: "importer", Hash: hash, Roles: []string{"admin"}})
+	clientCertificates, _ := auth.NewClientCertificates(auth.ClientCertificate{Subject: "billing", Name: "billing-service", Roles: []string{"viewer"}})
+
+	deps := inMemoryDependencies()
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, clientCertificates)
+	router, _ := setupRouter(deps)
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	packetConn, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	t.Cleanup(func() { packetConn.Close() })
+
+	server := newHTTPServer(router, reloader.TLSConfig(), true)
+	go server.Serve(listener, packetConn)
+	t.Cleanup(func() { server.Close() })
+
+	clientConfig := func(certificates ...tls.Certificate) *tls.Config {
+		return &tls.Config{RootCAs: ca.Pool(), Certificates: certificates}
+	}
+	billing := ca.Client(t, pkix.Name{CommonName: "billing"})
+
+	tcpClient := func(config *tls.Config) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: config, ForceAttemptHTTP2: true}}
+	}
+	quicTransport := &http3.Transport{TLSClientConfig: clientConfig(billing)}
+	t.Cleanup(func() { quicTransport.Close() })
+
+	tcpURL := "https://" + listener.Addr().String()
+	quicURL := fmt.Sprintf("https://127.0.0.1:%d", packetConn.LocalAddr().(*net.UDPAddr).Port)
+
+	do := func(client *http.Client, method, url, body string, headers ...string) int {
+		request, _ := http.NewRequest(method, url, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			t.Fatalf("Expected no error on %s %s, got %v", method, url, err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	// Act
+	read := do(tcpClient(clientConfig(billing)), http.MethodGet, tcpURL+"/v1/categories", "")
+	readOverQUIC := do(&http.Client{Transport: quicTransport}, http.MethodGet, quicURL+"/v1/categories", "")
+	forbidden := do(tcpClient(clientConfig(billing)), http.MethodPost, tcpURL+"/v1/categories", `{"name":"Electronics"}`)
+	withAPIKey := do(tcpClient(clientConfig()), http.MethodPost, tcpURL+"/v1/categories", `{"name":"Electronics"}`, auth.APIKeyHeader, apiKey)
+	anonymous := do(tcpClient(clientConfig()), http.MethodGet, tcpURL+"/v1/categories", "")
+	unmapped := do(tcpClient(clientConfig(ca.Client(t, pkix.Name{CommonName: "someone-else"}))), http.MethodGet, tcpURL+"/v1/categories", "")
+
+	// Assert
+	if read != http.StatusOK || readOverQUIC != http.StatusOK {
+		t.Errorf("Expected the viewer certificate to read over HTTP/2 and HTTP/3, got %d and %d", read, readOverQUIC)
+	}
+
+	if forbidden != http.StatusForbidden {
+		t.Errorf("Expected 403 for the viewer certificate creating, got %d", forbidden)
+	}
+
+	// Em modo optional, quem não tem certificado segue com API key
+	if withAPIKey != http.StatusCreated {
+		t.Errorf("Expected 201 with an API key and no certificate, got %d", withAPIKey)
+	}
+
+	if anonymous != http.StatusUnauthorized || unmapped != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials and for an unmapped certificate, got %d and %d", anonymous, unmapped)
+	}
+}
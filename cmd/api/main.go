@@ -1,20 +1,96 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"expvar"
+	"gin-quickstart/cmd/api/middlewares"
+	"gin-quickstart/internal/config"
+	"log"
 	"net/http"
 
+	"gin-quickstart/internal/openapi"
+
 	"github.com/gin-gonic/gin"
 )
 
+var helthzDoc = openapi.Operation{
+	ID:      "helthz",
+	Summary: "Health check",
+	Tags:    []string{"health"},
+	Responses: map[int]openapi.Response{
+		http.StatusOK: {Body: struct {
+			Success bool `json:"success"`
+		}{}},
+	},
+}
+
 func main() {
-  router := gin.Default()
-  router.GET("/helthz", helthz)
-	CategoryRoutes(router)
-  router.Run(":8000")
+	deps, err := newDependencies()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go deps.outboxRelay.Run(context.Background(), config.OutboxPollInterval(), config.OutboxRetention())
+	go deps.webhookDispatcher.Run(context.Background(), config.WebhookPollInterval())
+	go deps.rateLimiter.Run(context.Background(), config.RateLimitPruneInterval())
+
+	if deps.categoryListener != nil {
+		go deps.categoryListener.Run(context.Background(), config.CategoryListenerRetryInterval())
+	}
+
+	if deps.categoryCache != nil {
+		expvar.Publish("category_cache", expvar.Func(func() any { return deps.categoryCache.Stats() }))
+	}
+
+	tlsReloader, err := newTLSReloader()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var tlsConfig *tls.Config
+	if tlsReloader != nil {
+		go tlsReloader.Run(context.Background(), config.TLSReloadInterval())
+		tlsConfig = tlsReloader.TLSConfig()
+	}
+
+	go serveGRPC(deps, config.GRPCAddress(), tlsConfig)
+
+	router, _ := setupRouter(deps)
+	serveHTTP(router, config.HTTPAddress(), tlsConfig, config.HTTP3Enabled())
+}
+
+func setupRouter(deps *dependencies) (*gin.Engine, *openapi.Document) {
+	registerJSONFieldNames()
+
+	router := gin.Default()
+	// O gin confia em qualquer proxy por padrão, e aí o ClientIP (chave dos
+	// limites por IP) seria o X-Forwarded-For que o próprio cliente manda
+	if err := router.SetTrustedProxies(deps.trustedProxies); err != nil {
+		log.Printf("invalid trusted proxies, trusting none: %v", err)
+		router.SetTrustedProxies(nil)
+	}
+	router.Use(middlewares.RequestID())
+	spec := openapi.NewDocument("Gin Quickstart API", "1.0.0")
+	SecuritySchemes(spec)
+
+	api := apiGroup{engine: router, group: &router.RouterGroup, spec: spec, limiter: deps.rateLimiter}
+	api.handle(http.MethodGet, "/helthz", helthzDoc, helthz)
+	CategoryRoutes(router, spec, deps)
+	WebhookRoutes(router, spec, deps)
+	GraphQLRoutes(router, spec, deps)
+	DocsRoutes(router, spec)
+
+	// Fora do spec e sem autenticação: só para a rede interna
+	if config.DebugVarsEnabled() {
+		router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+
+	return router, spec
 }
 
 func helthz(context *gin.Context) {
 	context.JSON(http.StatusOK, gin.H{
 		"success": true,
 	})
-}
\ No newline at end of file
+}
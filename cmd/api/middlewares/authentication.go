@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// Authenticate exige credenciais válidas (401 caso contrário) e guarda o
+// principal no gin.Context e no context da requisição, de onde os use cases
+// o leem; o Subject também vira o ator da auditoria.
+func Authenticate(authenticator auth.IAuthenticator) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		principal, err := authenticator.Authenticate(context.Request)
+
+		if err != nil {
+			context.Header("WWW-Authenticate", `Bearer realm="gin-quickstart"`)
+			abortWithError(context, http.StatusUnauthorized, "unauthorized", err.Error())
+			return
+		}
+
+		context.Set(principalKey, principal)
+
+		ctx := auth.WithPrincipal(context.Request.Context(), principal)
+		ctx = audit.WithActor(ctx, principal.Subject)
+		context.Request = context.Request.WithContext(ctx)
+		context.Next()
+	}
+}
+
+// Principal devolve quem foi autenticado por Authenticate.
+func Principal(context *gin.Context) (*auth.Principal, bool) {
+	value, ok := context.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+
+	principal, ok := value.(*auth.Principal)
+	return principal, ok
+}
@@ -0,0 +1,16 @@
+package middlewares
+
+import (
+	"gin-quickstart/internal/authz"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorize coloca a política no context da requisição. A verificação em si
+// fica nos use cases (authz.Require), que respondem com *authz.ForbiddenError.
+func Authorize(policy *authz.Policy) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Request = context.Request.WithContext(authz.WithPolicy(context.Request.Context(), policy))
+		context.Next()
+	}
+}
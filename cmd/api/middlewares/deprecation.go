@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marca as respostas de rotas legadas com os headers
+// Deprecation (RFC 9745), Sunset (RFC 8594) e um Link para a rota
+// equivalente na versão que a substitui.
+func Deprecated(deprecatedAt, sunsetAt time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+
+	return func(context *gin.Context) {
+		context.Header("Deprecation", deprecation)
+		context.Header("Sunset", sunset)
+		context.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, context.Request.URL.Path))
+		context.Next()
+	}
+}
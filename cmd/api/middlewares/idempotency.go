@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/idempotency"
+	"gin-quickstart/internal/tenancy"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Headers da resposta original que são repetidos no replay
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// Idempotency guarda a resposta de requisições enviadas com Idempotency-Key
+// e a repete quando o cliente reenvia a mesma requisição. Reusar a chave com
+// outro corpo responde 422; reenviar enquanto a original ainda está em
+// andamento responde 409, mas só durante o lease: se ela não terminar até
+// lá (ex: a réplica caiu), o reenvio assume a chave e é executado. Respostas
+// 5xx não são guardadas, para que o cliente possa tentar de novo. As chaves
+// são por tenant e por principal: a mesma chave em outro tenant, ou enviada
+// por outro cliente, é outra requisição.
+//
+// operation identifica a rota independente da versão (o operationId, como
+// no RateLimit): reenviar para /v1/categories o que foi enviado para
+// /categories repete a resposta em vez de responder 422.
+func Idempotency(store idempotency.IStore, ttl, lease time.Duration, operation string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key := context.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			context.Next()
+			return
+		}
+		key = idempotencyScope(context) + "/" + key
+
+		body, err := io.ReadAll(context.Request.Body)
+		if err != nil {
+			abortWithError(context, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		context.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(operation, context, body)
+
+		record, created, err := store.Reserve(key, fingerprint, ttl, lease)
+		if err != nil {
+			context.Error(err)
+			abortWithError(context, http.StatusInternalServerError, "internal_error", http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		if !created {
+			replay(context, record, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: context.Writer}
+		context.Writer = recorder
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				store.Release(key)
+				panic(recovered)
+			}
+		}()
+
+		context.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			store.Release(key)
+			return
+		}
+
+		header := http.Header{}
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				header.Set(name, value)
+			}
+		}
+
+		if err := store.Complete(key, recorder.Status(), header, recorder.body.Bytes()); err != nil {
+			context.Error(err)
+		}
+	}
+}
+
+func replay(context *gin.Context, record *idempotency.Record, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		abortWithError(context, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used with a different request")
+		return
+	}
+
+	if !record.Completed {
+		abortWithError(context, http.StatusConflict, "idempotency_key_in_use", "a request with this Idempotency-Key is still being processed")
+		return
+	}
+
+	for name, values := range record.Header {
+		for _, value := range values {
+			context.Writer.Header().Add(name, value)
+		}
+	}
+	context.Header("Idempotent-Replayed", "true")
+	context.Writer.WriteHeader(record.StatusCode)
+	context.Writer.Write(record.Body)
+	context.Abort()
+}
+
+// idempotencyScope é o tenant e o principal da requisição; o subject é
+// escapado para que uma "/" nele não se confunda com o separador.
+func idempotencyScope(context *gin.Context) string {
+	scope := tenancy.From(context.Request.Context())
+	if principal, ok := auth.PrincipalFrom(context.Request.Context()); ok {
+		scope += "/" + url.PathEscape(principal.Subject)
+	}
+	return scope
+}
+
+// requestFingerprint identifica a requisição pela operação, método,
+// parâmetros da rota e corpo.
+func requestFingerprint(operation string, context *gin.Context, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, context.Request.Method)
+	io.WriteString(hash, " ")
+	io.WriteString(hash, operation)
+	for _, param := range context.Params {
+		io.WriteString(hash, " "+param.Key+"="+param.Value)
+	}
+	io.WriteString(hash, "\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copia o corpo escrito pelo handler para poder guardá-lo.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *responseRecorder) WriteString(data string) (int, error) {
+	recorder.body.WriteString(data)
+	return recorder.ResponseWriter.WriteString(data)
+}
+
+// abortWithError responde no mesmo formato do errorEnvelope dos controllers.
+func abortWithError(context *gin.Context, status int, code, message string) {
+	context.AbortWithStatusJSON(status, gin.H{
+		"errors": []gin.H{{"code": code, "message": message}},
+	})
+}
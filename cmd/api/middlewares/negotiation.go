@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const negotiatedFormatKey = "negotiated_format"
+
+// Negotiate escolhe, entre os tipos oferecidos pela rota, o que melhor
+// atende o header Accept (respeitando os pesos q=) e guarda a escolha para
+// os handlers (NegotiatedFormat). Sem nenhum tipo aceitável responde 406
+// antes do handler rodar.
+func Negotiate(offered ...string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Header("Vary", "Accept")
+
+		format := NegotiateFormat(context.GetHeader("Accept"), offered)
+
+		if format == "" {
+			abortWithError(context, http.StatusNotAcceptable, "not_acceptable", "supported media types: "+strings.Join(offered, ", "))
+			return
+		}
+
+		context.Set(negotiatedFormatKey, format)
+		context.Next()
+	}
+}
+
+// NegotiatedFormat devolve o tipo escolhido pelo Negotiate, ou "" quando a
+// rota não passou pelo middleware.
+func NegotiatedFormat(context *gin.Context) string {
+	return context.GetString(negotiatedFormatKey)
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// NegotiateFormat implementa a negociação da RFC 9110: para cada tipo
+// oferecido vale o peso do range mais específico que o aceita; ganha o
+// maior peso e, no empate, a ordem de offered. Accept vazio aceita tudo.
+func NegotiateFormat(accept string, offered []string) string {
+	if strings.TrimSpace(accept) == "" && len(offered) > 0 {
+		return offered[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+
+	for _, candidate := range offered {
+		quality, specificity := 0.0, -1
+
+		for _, accepted := range ranges {
+			if matched, level := matchMediaType(accepted.mediaType, candidate); matched && level > specificity {
+				quality, specificity = accepted.quality, level
+			}
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+
+	return best
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+
+	return ranges
+}
+
+// matchMediaType diz se o range (ex: application/*) aceita o tipo e quão
+// específico ele é: 0 para */*, 1 para type/* e 2 para type/subtype.
+func matchMediaType(accepted, candidate string) (bool, int) {
+	if accepted == "*/*" {
+		return true, 0
+	}
+
+	acceptedType, acceptedSubtype, _ := strings.Cut(accepted, "/")
+	candidateType, candidateSubtype, _ := strings.Cut(candidate, "/")
+
+	if acceptedType != candidateType {
+		return false, 0
+	}
+
+	if acceptedSubtype == "*" {
+		return true, 1
+	}
+
+	return acceptedSubtype == candidateSubtype, 2
+}
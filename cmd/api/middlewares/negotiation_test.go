@@ -0,0 +1,31 @@
+package middlewares
+
+import "testing"
+
+func TestNegotiateFormat(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/csv"}
+
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{"Empty accepts the first offered", "", "application/json"},
+		{"Exact match", "text/csv", "text/csv"},
+		{"Any type", "*/*", "application/json"},
+		{"Type wildcard", "text/*", "text/csv"},
+		{"Highest quality wins", "application/json;q=0.2, application/xml;q=0.8", "application/xml"},
+		{"Specific range overrides wildcard", "*/*;q=0.9, application/json;q=0", "application/xml"},
+		{"Tie keeps server order", "application/xml, application/json", "application/json"},
+		{"Nothing acceptable", "text/html", ""},
+		{"Zero quality excludes", "application/json;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NegotiateFormat(tt.accept, offered); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
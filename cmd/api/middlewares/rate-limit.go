@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"gin-quickstart/internal/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit consome um token do bucket do cliente na operação e responde
+// 429 com Retry-After quando ele está vazio. Toda resposta limitada leva os
+// headers RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset e
+// RateLimit-Policy. Se o store falhar a requisição passa: é melhor não
+// limitar do que derrubar a API junto com o banco.
+func RateLimit(limiter *ratelimit.Limiter, operation string) gin.HandlerFunc {
+	return rateLimit(limiter, operation, clientKey)
+}
+
+// RateLimitByIP é o RateLimit que identifica o cliente só pelo IP, para
+// rodar antes da autenticação.
+func RateLimitByIP(limiter *ratelimit.Limiter, operation string) gin.HandlerFunc {
+	return rateLimit(limiter, operation, func(context *gin.Context) string {
+		return "ip:" + context.ClientIP()
+	})
+}
+
+func rateLimit(limiter *ratelimit.Limiter, operation string, clientKey func(context *gin.Context) string) gin.HandlerFunc {
+	limit := limiter.Limit(operation)
+
+	return func(context *gin.Context) {
+		if limit.Unlimited() {
+			context.Next()
+			return
+		}
+
+		result, err := limiter.Allow(operation, clientKey(context))
+		if err != nil {
+			context.Error(err)
+			context.Next()
+			return
+		}
+
+		context.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		context.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		context.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+		context.Header("RateLimit-Policy", limit.Policy())
+
+		if !result.Allowed {
+			context.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			abortWithError(context, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded, retry after "+ceilSeconds(result.RetryAfter)+"s")
+			return
+		}
+
+		context.Next()
+	}
+}
+
+func clientKey(context *gin.Context) string {
+	principal, _ := Principal(context)
+	return ratelimit.ClientKey(principal, context.ClientIP())
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
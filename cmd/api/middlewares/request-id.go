@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"gin-quickstart/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID repassa o X-Request-ID recebido (ou gera um novo), devolve o
+// mesmo valor na resposta e o guarda no context da requisição, de onde os
+// use cases o levam para o log de auditoria.
+func RequestID() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		requestID := context.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+
+		context.Header(RequestIDHeader, requestID)
+		context.Request = context.Request.WithContext(audit.WithRequestID(context.Request.Context(), requestID))
+		context.Next()
+	}
+}
+
+func newRequestID() string {
+	buffer := make([]byte, 16)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
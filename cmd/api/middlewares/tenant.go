@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"errors"
+	"gin-quickstart/internal/tenancy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant resolve o tenant da requisição (depois de Authenticate, que pode
+// prendê-lo ao tenant das credenciais) e o coloca no context dos use cases.
+// Um tenant malformado responde 400; um tenant diferente do das
+// credenciais, 403.
+func Tenant(resolver tenancy.Resolver) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		principal, _ := Principal(context)
+
+		tenant, err := resolver.Resolve(context.Request, principal)
+
+		var mismatchError *tenancy.MismatchError
+		switch {
+		case errors.As(err, &mismatchError):
+			abortWithError(context, http.StatusForbidden, "forbidden", err.Error())
+			return
+		case err != nil:
+			abortWithError(context, http.StatusBadRequest, "invalid_tenant", err.Error())
+			return
+		}
+
+		context.Request = context.Request.WithContext(tenancy.WithTenant(context.Request.Context(), tenant))
+		context.Next()
+	}
+}
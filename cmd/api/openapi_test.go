@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"gin-quickstart/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+const specPath = "../../docs/openapi.json"
+
+var update = flag.Bool("update", false, "regrava docs/openapi.json a partir das rotas")
+
+// Rotas que servem a própria documentação e não entram no spec
+var undocumentedRoutes = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+}
+
+func TestOpenAPI_AllRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, spec := setupRouter(inMemoryDependencies())
+
+	var routes []string
+	actionRoutes := map[string]bool{}
+	for _, route := range router.Routes() {
+		key := route.Method + " " + openapi.ConvertPath(route.Path)
+		if undocumentedRoutes[key] {
+			continue
+		}
+		// Rotas de ação (/categories:action) são documentadas por ação (/categories:batch)
+		if strings.HasSuffix(route.Path, actionParam) {
+			actionRoutes[strings.TrimSuffix(key, actionParam)] = true
+			continue
+		}
+		routes = append(routes, key)
+	}
+	sort.Strings(routes)
+
+	var operations []string
+	for _, operation := range spec.Operations() {
+		if prefix, ok := cutAction(operation); ok {
+			if !actionRoutes[prefix] {
+				t.Errorf("Action %s has no matching route", operation)
+			}
+			delete(actionRoutes, prefix)
+			continue
+		}
+		operations = append(operations, operation)
+	}
+
+	for prefix := range actionRoutes {
+		t.Errorf("Action route %s%s is not documented", prefix, actionParam)
+	}
+
+	if len(routes) != len(operations) {
+		t.Fatalf("Expected %d documented operations, got %d\nroutes: %v\nspec: %v", len(routes), len(operations), routes, operations)
+	}
+
+	for i := range routes {
+		if routes[i] != operations[i] {
+			t.Errorf("Route %s is not documented (spec has %s)", routes[i], operations[i])
+		}
+	}
+}
+
+// cutAction devolve "POST /v1/categories" para "POST /v1/categories:batch".
+func cutAction(operation string) (prefix string, ok bool) {
+	index := strings.LastIndex(operation, ":")
+	if index == -1 || strings.Contains(operation[index:], "/") {
+		return "", false
+	}
+	return operation[:index], true
+}
+
+// Falha quando o docs/openapi.json versionado não bate com o código.
+// Para regravar: go test ./cmd/api -run TestOpenAPI_SpecIsUpToDate -update
+func TestOpenAPI_SpecIsUpToDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, spec := setupRouter(inMemoryDependencies())
+
+	generated, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		t.Fatalf("Error marshaling spec: %v", err)
+	}
+	generated = append(generated, '\n')
+
+	if *update {
+		if err := os.WriteFile(specPath, generated, 0o644); err != nil {
+			t.Fatalf("Error writing %s: %v", specPath, err)
+		}
+	}
+
+	committed, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatalf("Error reading %s: %v", specPath, err)
+	}
+
+	if string(committed) != string(generated) {
+		t.Fatalf("%s is out of date, run: go test ./cmd/api -run TestOpenAPI_SpecIsUpToDate -update", specPath)
+	}
+}
+
+func TestOpenAPI_ServesSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var document openapi.Document
+	if err := json.Unmarshal(recorder.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Error decoding spec: %v", err)
+	}
+
+	if document.OpenAPI != openapi.Version {
+		t.Errorf("Expected openapi %s, got %s", openapi.Version, document.OpenAPI)
+	}
+
+	if _, ok := document.Components.Schemas["CreateCategoryInput"]; !ok {
+		t.Error("Expected CreateCategoryInput schema to be generated")
+	}
+}
@@ -2,20 +2,300 @@ package main
 
 import (
 	"gin-quickstart/cmd/api/controllers"
-	"gin-quickstart/internal/repositories"
+	v1 "gin-quickstart/cmd/api/controllers/v1"
+	"gin-quickstart/cmd/api/graph"
+	"gin-quickstart/cmd/api/middlewares"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/openapi"
+	"gin-quickstart/internal/ratelimit"
+	"gin-quickstart/internal/tenancy"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CategoryRoutes(router *gin.Engine) {
-	routes := router.Group("/categories")
-	inMemoryCategoryRepository := repositories.NewInMemotyCategoryRepository()
-	
-	routes.POST("", func(ctx *gin.Context) {
-		controllers.CreateCategory(ctx, inMemoryCategoryRepository)
+const actionParam = ":action"
+
+// Datas de depreciação das rotas sem versão (/categories)
+var (
+	unversionedDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	unversionedSunsetAt     = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// Security schemes aceitos pelas rotas autenticadas
+var securitySchemes = []string{"bearerAuth", "apiKeyAuth", "mutualTLS"}
+
+// tenantParameter documenta o header de tenant das rotas autenticadas
+var tenantParameter = openapi.Parameter{
+	Name:        tenancy.Header,
+	In:          "header",
+	Description: "Catálogo (tenant) da requisição; se as credenciais tiverem um, precisa ser o mesmo. Sem ele vale o subdomínio ou \"default\"",
+	Example:     "loja1",
+}
+
+// apiGroup agrupa o router group do gin com o spec OpenAPI, para que toda
+// rota registrada seja também documentada. middlewares são os mesmos
+// passados ao criar o group, reaplicados nas rotas de ação (handleAction).
+// security, quando preenchido, documenta as rotas como autenticadas.
+// limiter, quando preenchido, limita cada rota pelo operationId (o mesmo
+// na rota sem versão, que divide a cota com a v1).
+type apiGroup struct {
+	engine      *gin.Engine
+	group       *gin.RouterGroup
+	middlewares []gin.HandlerFunc
+	spec        *openapi.Document
+	deprecated  bool
+	security    []string
+	limiter     *ratelimit.Limiter
+}
+
+func newAPIGroup(engine *gin.Engine, spec *openapi.Document, basePath string, deprecated bool, middlewares ...gin.HandlerFunc) apiGroup {
+	return apiGroup{
+		engine:      engine,
+		group:       engine.Group(basePath, middlewares...),
+		middlewares: middlewares,
+		spec:        spec,
+		deprecated:  deprecated,
+	}
+}
+
+// newAuthenticatedAPIGroup é um newAPIGroup cujas rotas exigem credenciais
+// (middlewares.Authenticate antes dos demais middlewares), são autorizadas
+// pela política, pertencem a um tenant e têm limite de requisições por
+// cliente. Antes da autenticação vale ainda um limite por IP, que também
+// conta as requisições com credenciais inválidas.
+func newAuthenticatedAPIGroup(engine *gin.Engine, spec *openapi.Document, basePath string, deprecated bool, deps *dependencies, handlers ...gin.HandlerFunc) apiGroup {
+	var chain []gin.HandlerFunc
+	if deps.rateLimiter != nil && !deps.rateLimiter.Limit(ratelimit.AuthenticateOperation).Unlimited() {
+		chain = append(chain, middlewares.RateLimitByIP(deps.rateLimiter, ratelimit.AuthenticateOperation))
+	}
+	chain = append(chain,
+		middlewares.Authenticate(deps.authenticator),
+		middlewares.Authorize(deps.policy),
+		middlewares.Tenant(deps.tenantResolver),
+	)
+
+	api := newAPIGroup(engine, spec, basePath, deprecated, append(chain, handlers...)...)
+	api.security = securitySchemes
+	api.limiter = deps.rateLimiter
+	return api
+}
+
+// SecuritySchemes documenta as formas de autenticação aceitas.
+func SecuritySchemes(spec *openapi.Document) {
+	spec.AddSecurityScheme("bearerAuth", openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "JWT HS256 ou RS256 com as claims sub e roles",
+	})
+	spec.AddSecurityScheme("apiKeyAuth", openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        auth.APIKeyHeader,
+		Description: "API key de conta de serviço",
+	})
+	spec.AddSecurityScheme("mutualTLS", openapi.SecurityScheme{
+		Type:        "mutualTLS",
+		Description: "Certificado de cliente de conta de serviço (TLS_CLIENT_AUTH)",
+	})
+}
+
+func CategoryRoutes(router *gin.Engine, spec *openapi.Document, deps *dependencies) {
+	v1CategoryRoutes(newAuthenticatedAPIGroup(router, spec, "/v1/categories", false, deps), deps)
+
+	// Alias legado sem versão, mantido até a data de sunset
+	deprecation := middlewares.Deprecated(unversionedDeprecatedAt, unversionedSunsetAt, "/v1")
+	v1CategoryRoutes(newAuthenticatedAPIGroup(router, spec, "/categories", true, deps, deprecation), deps)
+}
+
+func v1CategoryRoutes(api apiGroup, deps *dependencies) {
+	repository := deps.categoryRepository
+	auditLog := deps.auditLog
+
+	// Negociação antes da idempotência: um 406 não deve reservar a chave
+	negotiateEntity := middlewares.Negotiate(controllers.EntityFormats...)
+	negotiateList := middlewares.Negotiate(controllers.ListFormats...)
+
+	api.handle(http.MethodPost, "", v1.CreateCategoryDoc, negotiateEntity, middlewares.Idempotency(deps.idempotencyStore, deps.idempotencyTTL, deps.idempotencyLease, v1.CreateCategoryDoc.ID), func(ctx *gin.Context) {
+		v1.CreateCategory(ctx, repository)
+	})
+
+	api.handleAction(http.MethodPost, "batch", v1.BatchCategoriesDoc, negotiateEntity, middlewares.Idempotency(deps.idempotencyStore, deps.idempotencyTTL, deps.idempotencyLease, v1.BatchCategoriesDoc.ID), func(ctx *gin.Context) {
+		v1.BatchCategories(ctx, repository)
+	})
+
+	api.handle(http.MethodGet, "", v1.ListCategoryDoc, negotiateList, func(ctx *gin.Context) {
+		v1.ListCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodGet, "/export", v1.ExportCategoriesDoc, func(ctx *gin.Context) {
+		v1.ExportCategories(ctx, repository)
+	})
+
+	api.handle(http.MethodPost, "/import", v1.ImportCategoriesDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.ImportCategories(ctx, repository)
+	})
+
+	api.handle(http.MethodGet, "/stream", v1.StreamCategoriesDoc, middlewares.Negotiate(controllers.MIMEEventStream), func(ctx *gin.Context) {
+		v1.StreamCategories(ctx, deps.categoryStream, deps.streamHeartbeat)
+	})
+
+	api.handle(http.MethodGet, "/:id", v1.GetCategoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.GetCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodPut, "/:id", v1.UpdateCategoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.UpdateCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodDelete, "/:id", v1.DeleteCategoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.DeleteCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodPost, "/:id/restore", v1.RestoreCategoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.RestoreCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodPost, "/:id/move", v1.MoveCategoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.MoveCategory(ctx, repository)
+	})
+
+	api.handle(http.MethodGet, "/:id/children", v1.CategoryChildrenDoc, negotiateList, func(ctx *gin.Context) {
+		v1.CategoryChildren(ctx, repository)
+	})
+
+	api.handle(http.MethodGet, "/:id/history", v1.CategoryHistoryDoc, negotiateEntity, func(ctx *gin.Context) {
+		v1.CategoryHistory(ctx, auditLog)
+	})
+}
+
+// WebhookRoutes só existe na v1: não há alias legado para recursos novos.
+func WebhookRoutes(router *gin.Engine, spec *openapi.Document, deps *dependencies) {
+	api := newAuthenticatedAPIGroup(router, spec, "/v1/webhooks", false, deps, middlewares.Negotiate(controllers.EntityFormats...))
+	store := deps.webhookStore
+
+	api.handle(http.MethodPost, "", v1.CreateWebhookDoc, func(ctx *gin.Context) {
+		v1.CreateWebhook(ctx, store, deps.webhookTargets)
+	})
+
+	api.handle(http.MethodGet, "", v1.ListWebhooksDoc, func(ctx *gin.Context) {
+		v1.ListWebhooks(ctx, store)
+	})
+
+	api.handle(http.MethodGet, "/dead-letters", v1.ListWebhookDeadLettersDoc, func(ctx *gin.Context) {
+		v1.ListWebhookDeadLetters(ctx, store)
+	})
+
+	api.handle(http.MethodPost, "/deliveries/:id/redeliver", v1.RedeliverWebhookDoc, func(ctx *gin.Context) {
+		v1.RedeliverWebhook(ctx, store)
+	})
+
+	api.handle(http.MethodGet, "/:id", v1.GetWebhookDoc, func(ctx *gin.Context) {
+		v1.GetWebhook(ctx, store)
+	})
+
+	api.handle(http.MethodDelete, "/:id", v1.DeleteWebhookDoc, func(ctx *gin.Context) {
+		v1.DeleteWebhook(ctx, store)
 	})
 
-	routes.GET("", func(ctx *gin.Context) {
-		controllers.ListCategory(ctx, inMemoryCategoryRepository)
+	api.handle(http.MethodGet, "/:id/deliveries", v1.ListWebhookDeliveriesDoc, func(ctx *gin.Context) {
+		v1.ListWebhookDeliveries(ctx, store)
 	})
-}
\ No newline at end of file
+}
+
+// GraphQLRoutes expõe as categorias em /graphql, com a mesma autenticação,
+// política, tenant e limite de requisições das rotas REST.
+func GraphQLRoutes(router *gin.Engine, spec *openapi.Document, deps *dependencies) {
+	api := newAuthenticatedAPIGroup(router, spec, "/graphql", false, deps)
+
+	handler, err := graph.NewHandler(deps.categoryRepository, deps.auditLog, deps.graphqlLimits)
+	if err != nil {
+		// O schema é montado em código: um erro aqui é bug, não configuração
+		panic(err)
+	}
+
+	api.handle(http.MethodPost, "", graph.Doc, handler.Serve)
+}
+
+// handle registra a rota no gin e a operação correspondente no spec OpenAPI,
+// mantendo os dois sempre em sincronia.
+func (api apiGroup) handle(method, relativePath string, doc openapi.Operation, handlers ...gin.HandlerFunc) {
+	handlers = api.rateLimited(doc, handlers)
+	doc = api.document(doc)
+
+	api.group.Handle(method, relativePath, handlers...)
+	api.spec.AddOperation(method, joinPaths(api.group.BasePath(), relativePath), doc)
+}
+
+// handleAction registra um método customizado no estilo /categories:batch.
+// O gin só trata ":" literal no meio do path depois do router.Run, então a
+// rota real é um parâmetro no mesmo segmento (/categories:action) que só
+// aceita a ação registrada. Cada método HTTP comporta uma ação por group.
+func (api apiGroup) handleAction(method, action string, doc openapi.Operation, handlers ...gin.HandlerFunc) {
+	handlers = api.rateLimited(doc, handlers)
+	doc = api.document(doc)
+
+	dispatch := func(ctx *gin.Context) {
+		if ctx.Param("action") != ":"+action {
+			ctx.AbortWithStatus(http.StatusNotFound)
+		}
+	}
+
+	chain := append(append(append([]gin.HandlerFunc{}, api.middlewares...), dispatch), handlers...)
+
+	api.engine.Handle(method, api.group.BasePath()+actionParam, chain...)
+	api.spec.AddOperation(method, api.group.BasePath()+":"+action, doc)
+}
+
+// rateLimited põe o middlewares.RateLimit da operação antes dos handlers.
+// Recebe a operação antes de document, com o operationId ainda sem o
+// sufixo das rotas sem versão.
+func (api apiGroup) rateLimited(doc openapi.Operation, handlers []gin.HandlerFunc) []gin.HandlerFunc {
+	if api.limiter == nil || api.limiter.Limit(doc.ID).Unlimited() {
+		return handlers
+	}
+
+	return append([]gin.HandlerFunc{middlewares.RateLimit(api.limiter, doc.ID)}, handlers...)
+}
+
+// document ajusta a operação ao group: depreciação; em groups autenticados,
+// o security, o header de tenant e as respostas 401 e 403; e, com limite de
+// requisições (da operação ou, nos groups autenticados, por IP), a resposta
+// 429.
+func (api apiGroup) document(doc openapi.Operation) openapi.Operation {
+	limited := api.limiter != nil && (!api.limiter.Limit(doc.ID).Unlimited() ||
+		len(api.security) > 0 && !api.limiter.Limit(ratelimit.AuthenticateOperation).Unlimited())
+
+	if api.deprecated {
+		doc.ID += "Unversioned"
+		doc.Deprecated = true
+	}
+
+	if len(api.security) > 0 {
+		doc.Security = api.security
+		doc.Parameters = append(slices.Clone(doc.Parameters), tenantParameter)
+		doc.Responses = maps.Clone(doc.Responses)
+		doc.Responses[http.StatusUnauthorized] = v1.UnauthorizedResponse
+		doc.Responses[http.StatusForbidden] = v1.ForbiddenResponse
+	}
+
+	if limited {
+		doc.Responses = maps.Clone(doc.Responses)
+		doc.Responses[http.StatusTooManyRequests] = v1.TooManyRequestsResponse
+	}
+
+	return doc
+}
+
+func joinPaths(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+
+	return path.Join(basePath, relativePath)
+}
@@ -0,0 +1,1323 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/events/eventstest"
+	"gin-quickstart/internal/ratelimit"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRoutes_VersionedPathHasNoDeprecationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/categories", nil))
+
+	if recorder.Code >= http.StatusBadRequest {
+		t.Fatalf("Expected success status, got %d", recorder.Code)
+	}
+
+	if recorder.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected no Deprecation header, got %q", recorder.Header().Get("Deprecation"))
+	}
+}
+
+func TestRoutes_UnversionedPathIsDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/categories", nil))
+
+	if recorder.Code >= http.StatusBadRequest {
+		t.Fatalf("Expected success status, got %d", recorder.Code)
+	}
+
+	if got := recorder.Header().Get("Deprecation"); got != "@1792368000" {
+		t.Errorf("Expected Deprecation header @1792368000, got %q", got)
+	}
+
+	if got := recorder.Header().Get("Sunset"); got != "Mon, 19 Apr 2027 00:00:00 GMT" {
+		t.Errorf("Unexpected Sunset header %q", got)
+	}
+
+	if got := recorder.Header().Get("Link"); got != `</v1/categories>; rel="successor-version"` {
+		t.Errorf("Unexpected Link header %q", got)
+	}
+}
+
+func TestRoutes_CreateReturnsLocationOfCreatedCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/v1/categories", strings.NewReader(`{"name":"Electronics"}`))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	location := recorder.Header().Get("Location")
+	if location != "/v1/categories/1" {
+		t.Fatalf("Expected Location /v1/categories/1, got %q", location)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, location, nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var body struct {
+		Data struct {
+			ID   uint   `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Error decoding body: %v", err)
+	}
+
+	if body.Data.ID != 1 || body.Data.Name != "Electronics" {
+		t.Errorf("Unexpected category %+v", body.Data)
+	}
+}
+
+func TestRoutes_StatusCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"List", http.MethodGet, "/v1/categories", "", http.StatusOK, ""},
+		{"Missing name", http.MethodPost, "/v1/categories", `{}`, http.StatusBadRequest, "invalid_request"},
+		{"Short name", http.MethodPost, "/v1/categories", `{"name":"abc"}`, http.StatusUnprocessableEntity, "validation_failed"},
+		{"Invalid id", http.MethodGet, "/v1/categories/abc", "", http.StatusBadRequest, "invalid_request"},
+		{"Unknown id", http.MethodGet, "/v1/categories/42", "", http.StatusNotFound, "not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupRouter(inMemoryDependencies())
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			if tt.expectedCode == "" {
+				return
+			}
+
+			var body struct {
+				Errors []struct {
+					Code string `json:"code"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Error decoding body: %v", err)
+			}
+
+			if len(body.Errors) == 0 || body.Errors[0].Code != tt.expectedCode {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRoutes_ConditionalRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+	etag := created.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag on create")
+	}
+
+	// Leitura condicional
+	if recorder := serve(http.MethodGet, "/v1/categories/1", "", map[string]string{"If-None-Match": etag}); recorder.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching If-None-Match, got %d", recorder.Code)
+	}
+
+	listETag := serve(http.MethodGet, "/v1/categories", "", nil).Header().Get("ETag")
+	if recorder := serve(http.MethodGet, "/v1/categories", "", map[string]string{"If-None-Match": listETag}); recorder.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for unchanged list, got %d", recorder.Code)
+	}
+
+	// Escritas exigem If-Match
+	if recorder := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets & Co"}`, nil); recorder.Code != http.StatusPreconditionRequired {
+		t.Errorf("Expected 428 without If-Match, got %d", recorder.Code)
+	}
+
+	updated := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets & Co"}`, map[string]string{"If-Match": etag})
+	if updated.Code != http.StatusOK {
+		t.Fatalf("Expected 200 on update, got %d: %s", updated.Code, updated.Body.String())
+	}
+
+	newETag := updated.Header().Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Fatalf("Expected a new ETag after update, got %q", newETag)
+	}
+
+	// O ETag antigo ficou obsoleto
+	if recorder := serve(http.MethodPut, "/v1/categories/1", `{"name":"Stale Name"}`, map[string]string{"If-Match": etag}); recorder.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for stale If-Match, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodDelete, "/v1/categories/1", "", map[string]string{"If-Match": etag}); recorder.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for stale delete, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories", "", map[string]string{"If-None-Match": listETag}); recorder.Code != http.StatusOK {
+		t.Errorf("Expected 200 for changed list, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodDelete, "/v1/categories/1", "", map[string]string{"If-Match": newETag}); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 on delete, got %d", recorder.Code)
+	}
+}
+
+// microsecondStore devolve as categorias com os timestamps em
+// microssegundos, como o Postgres.
+type microsecondStore struct {
+	repositories.ICategoryRepository
+}
+
+func (store microsecondStore) ForTenant(tenant string) repositories.ICategoryRepository {
+	return microsecondStore{store.ICategoryRepository.ForTenant(tenant)}
+}
+
+func (store microsecondStore) FindByID(id uint) (*entities.Category, error) {
+	category, err := store.ICategoryRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	category.CreatedAt = category.CreatedAt.Truncate(time.Microsecond)
+	category.UpdatedAt = category.UpdatedAt.Truncate(time.Microsecond)
+	return category, nil
+}
+
+func TestRoutes_ETagSurvivesTimestampPrecision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	deps.categoryRepository = microsecondStore{deps.categoryRepository}
+	router, _ := setupRouter(deps)
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+	read := serve(http.MethodGet, "/v1/categories/1", "", nil)
+	updated := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets & Co"}`, map[string]string{"If-Match": read.Header().Get("ETag")})
+
+	// Assert
+	if created.Header().Get("ETag") != read.Header().Get("ETag") {
+		t.Errorf("Expected the ETag from the create on the read, got %q and %q", created.Header().Get("ETag"), read.Header().Get("ETag"))
+	}
+
+	if updated.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the ETag from the read, got %d: %s", updated.Code, updated.Body.String())
+	}
+}
+
+func TestRoutes_IdempotentCreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	create := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/v1/categories", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Idempotency-Key", "b5f3c1de-retry")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := create(`{"name":"Electronics"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", first.Code)
+	}
+
+	retry := create(`{"name":"Electronics"}`)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("Expected replayed 201, got %d", retry.Code)
+	}
+
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected Idempotent-Replayed header on retry")
+	}
+
+	if retry.Body.String() != first.Body.String() || retry.Header().Get("Location") != first.Header().Get("Location") {
+		t.Errorf("Expected identical response, got %s vs %s", retry.Body.String(), first.Body.String())
+	}
+
+	if reused := create(`{"name":"Another Category"}`); reused.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for key reused with another body, got %d", reused.Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/categories", nil))
+
+	var body struct {
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	if body.Meta.Total != 1 {
+		t.Errorf("Expected a single category to be created, got %d", body.Meta.Total)
+	}
+}
+
+func TestRoutes_IdempotencyKeyAcrossVersionsAndPrincipals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	importerKey, importerHash := auth.NewAPIKey()
+	dashboardKey, dashboardHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "importer", Hash: importerHash, Roles: []string{"editor"}},
+		auth.APIKey{Name: "dashboard", Hash: dashboardHash, Roles: []string{"editor"}},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	router, _ := setupRouter(deps)
+
+	create := func(path, apiKey, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Idempotency-Key", "b5f3c1de-retry")
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	first := create("/categories", importerKey, `{"name":"Electronics"}`)
+	retryOnV1 := create("/v1/categories", importerKey, `{"name":"Electronics"}`)
+	otherPrincipal := create("/v1/categories", dashboardKey, `{"name":"Home & Garden"}`)
+
+	// Assert
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+
+	if retryOnV1.Code != http.StatusCreated || retryOnV1.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Expected the retry on /v1 to replay the original 201, got %d: %s", retryOnV1.Code, retryOnV1.Body.String())
+	}
+
+	if otherPrincipal.Code != http.StatusCreated || otherPrincipal.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("Expected the same key from another client to create, got %d: %s", otherPrincipal.Code, otherPrincipal.Body.String())
+	}
+}
+
+func TestRoutes_BatchCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	batch := func(path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	atomicFailure := batch("/v1/categories:batch", `{"operations":[{"op":"create","name":"Electronics"},{"op":"create","name":"abc"}]}`)
+	if atomicFailure.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 for invalid atomic batch, got %d: %s", atomicFailure.Code, atomicFailure.Body.String())
+	}
+	if !strings.Contains(atomicFailure.Body.String(), `"field":"operations[1].name"`) {
+		t.Errorf("Expected error pointing to operations[1].name, got %s", atomicFailure.Body.String())
+	}
+
+	bestEffort := batch("/v1/categories:batch", `{"mode":"best_effort","operations":[{"op":"create","name":"Electronics"},{"op":"create","name":"abc"},{"op":"delete","id":99}]}`)
+	if bestEffort.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for best effort batch, got %d: %s", bestEffort.Code, bestEffort.Body.String())
+	}
+
+	var body struct {
+		Data []struct {
+			Status string `json:"status"`
+		} `json:"data"`
+		Meta struct {
+			Succeeded int `json:"succeeded"`
+			Failed    int `json:"failed"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(bestEffort.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Error decoding body: %v", err)
+	}
+
+	if body.Meta.Succeeded != 1 || body.Meta.Failed != 2 {
+		t.Errorf("Expected 1 succeeded and 2 failed, got %+v", body.Meta)
+	}
+	if body.Data[0].Status != "created" || body.Data[2].Status != "failed" {
+		t.Errorf("Unexpected item statuses %+v", body.Data)
+	}
+
+	if missing := batch("/v1/categories:batch", `{"operations":[{"op":"update","name":"Renamed"}]}`); missing.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for update without id, got %d", missing.Code)
+	}
+
+	if unknown := batch("/v1/categories:purge", `{}`); unknown.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown action, got %d", unknown.Code)
+	}
+
+	if legacy := batch("/categories:batch", `{"operations":[{"op":"create","name":"Home & Garden"}]}`); legacy.Code != http.StatusOK || legacy.Header().Get("Deprecation") == "" {
+		t.Errorf("Expected deprecated alias to work, got %d", legacy.Code)
+	}
+}
+
+func TestRoutes_ImportAndExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	upload := func(query, filename, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+		writer.Close()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/v1/categories/import"+query, &body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	dryRun := upload("?dry_run=true", "categories.csv", "name\nElectronics\nabc\n")
+	if dryRun.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", dryRun.Code, dryRun.Body.String())
+	}
+	if !strings.Contains(dryRun.Body.String(), `"errors":[{"line":3,"message":"name must be greater than 5, got 3"}]`) {
+		t.Errorf("Expected line 3 error in report, got %s", dryRun.Body.String())
+	}
+
+	imported := upload("", "categories.jsonl", "{\"name\":\"Electronics\"}\n{\"name\":\"Home & Garden\"}\n")
+	if imported.Code != http.StatusOK || !strings.Contains(imported.Body.String(), `"imported":2`) {
+		t.Fatalf("Expected 2 imported rows, got %d: %s", imported.Code, imported.Body.String())
+	}
+
+	if unknown := upload("", "categories.xlsx", "whatever"); unknown.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown format, got %d", unknown.Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/categories/export?format=csv", nil))
+
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("Expected CSV export, got %d %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	if len(lines) != 3 || lines[0] != "id,name,created_at,updated_at,version" || !strings.HasPrefix(lines[2], "2,Home & Garden,") {
+		t.Errorf("Unexpected export:\n%s", recorder.Body.String())
+	}
+}
+
+func TestRoutes_ContentNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	send := func(method, path, contentType, accept, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Corpo em XML, resposta em XML
+	created := send(http.MethodPost, "/v1/categories", "application/xml", "application/xml", `<category><name>Electronics</name></category>`)
+	if created.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", created.Code, created.Body.String())
+	}
+	if !strings.HasPrefix(created.Header().Get("Content-Type"), "application/xml") || !strings.Contains(created.Body.String(), "<response><data><id>1</id><name>Electronics</name>") {
+		t.Errorf("Unexpected XML response %s: %s", created.Header().Get("Content-Type"), created.Body.String())
+	}
+
+	// Corpo em YAML
+	if recorder := send(http.MethodPost, "/v1/categories", "application/yaml", "", "name: Home & Garden\n"); recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for YAML body, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		contains    string
+	}{
+		{"Default", "", "application/json", `"name":"Electronics"`},
+		{"Wildcard", "*/*", "application/json", `"name":"Electronics"`},
+		{"YAML", "application/yaml", "application/yaml", "name: Electronics"},
+		{"Quality", "application/json;q=0.5, application/xml", "application/xml", "<name>Electronics</name>"},
+		{"MessagePack", "application/msgpack", "application/msgpack", "Electronics"},
+		{"CSV", "text/csv", "text/csv", "1,Electronics,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := send(http.MethodGet, "/v1/categories", "", tt.accept, "")
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+			}
+			if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if !strings.Contains(recorder.Body.String(), tt.contains) {
+				t.Errorf("Expected body to contain %q, got %s", tt.contains, recorder.Body.String())
+			}
+		})
+	}
+
+	// CSV só é oferecido na listagem
+	if recorder := send(http.MethodGet, "/v1/categories/1", "", "text/csv", ""); recorder.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 for CSV on a single category, got %d", recorder.Code)
+	}
+
+	if recorder := send(http.MethodGet, "/v1/categories", "", "text/html", ""); recorder.Code != http.StatusNotAcceptable || !strings.Contains(recorder.Body.String(), "not_acceptable") {
+		t.Errorf("Expected 406 not_acceptable, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder := send(http.MethodPost, "/v1/categories", "text/plain", "", "Electronics"); recorder.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415 for text/plain body, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestRoutes_CategoryHierarchy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	etag := func(id string) map[string]string {
+		return map[string]string{"If-Match": serve(http.MethodGet, "/v1/categories/"+id, "", nil).Header().Get("ETag")}
+	}
+
+	serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+	serve(http.MethodPost, "/v1/categories", `{"name":"Phones"}`, nil)
+
+	// Act
+	withoutIfMatch := serve(http.MethodPost, "/v1/categories/2/move", `{"parent_id":1}`, nil)
+	moved := serve(http.MethodPost, "/v1/categories/2/move", `{"parent_id":1}`, etag("2"))
+	cycle := serve(http.MethodPost, "/v1/categories/1/move", `{"parent_id":2}`, etag("1"))
+	missingParent := serve(http.MethodPost, "/v1/categories/1/move", `{"parent_id":42}`, etag("1"))
+	roots := serve(http.MethodGet, "/v1/categories?roots=true", "", nil)
+	children := serve(http.MethodGet, "/v1/categories/1/children", "", nil)
+	missingChildren := serve(http.MethodGet, "/v1/categories/42/children", "", nil)
+	toRoot := serve(http.MethodPost, "/v1/categories/2/move", `{"parent_id":null}`, etag("2"))
+
+	// Assert
+	if withoutIfMatch.Code != http.StatusPreconditionRequired {
+		t.Errorf("Expected 428 without If-Match, got %d", withoutIfMatch.Code)
+	}
+
+	if moved.Code != http.StatusOK || !strings.Contains(moved.Body.String(), `"parent_id":1`) {
+		t.Fatalf("Expected Phones below Electronics, got %d: %s", moved.Code, moved.Body.String())
+	}
+
+	if cycle.Code != http.StatusUnprocessableEntity || missingParent.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a cycle and a missing parent, got %d and %d", cycle.Code, missingParent.Code)
+	}
+
+	if !strings.Contains(roots.Body.String(), "Electronics") || strings.Contains(roots.Body.String(), "Phones") {
+		t.Errorf("Expected only Electronics at the root, got %s", roots.Body.String())
+	}
+
+	if children.Code != http.StatusOK || !strings.Contains(children.Body.String(), "Phones") || strings.Contains(children.Body.String(), "Electronics") {
+		t.Errorf("Expected Phones as the only child, got %d: %s", children.Code, children.Body.String())
+	}
+
+	if missingChildren.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for the children of a missing category, got %d", missingChildren.Code)
+	}
+
+	if toRoot.Code != http.StatusOK || !strings.Contains(toRoot.Body.String(), `"parent_id":null`) {
+		t.Errorf("Expected Phones back at the root, got %d: %s", toRoot.Code, toRoot.Body.String())
+	}
+}
+
+func TestRoutes_ETagPerRepresentation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+
+	// Act
+	asJSON := serve(http.MethodGet, "/v1/categories/1", "", nil)
+	asXML := serve(http.MethodGet, "/v1/categories/1", "", map[string]string{"Accept": "application/xml"})
+	listAsJSON := serve(http.MethodGet, "/v1/categories", "", nil)
+	listAsCSV := serve(http.MethodGet, "/v1/categories", "", map[string]string{"Accept": "text/csv"})
+	xmlWithJSONETag := serve(http.MethodGet, "/v1/categories/1", "", map[string]string{"Accept": "application/xml", "If-None-Match": asJSON.Header().Get("ETag")})
+	updated := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets & Co"}`, map[string]string{"If-Match": asXML.Header().Get("ETag")})
+
+	// Assert
+	if asJSON.Header().Get("ETag") == asXML.Header().Get("ETag") || listAsJSON.Header().Get("ETag") == listAsCSV.Header().Get("ETag") {
+		t.Errorf("Expected a different ETag per format, got %q and %q", asJSON.Header().Get("ETag"), asXML.Header().Get("ETag"))
+	}
+
+	if asJSON.Header().Get("Vary") != "Accept" || listAsCSV.Header().Get("Vary") != "Accept" {
+		t.Errorf("Expected Vary: Accept, got %q and %q", asJSON.Header().Get("Vary"), listAsCSV.Header().Get("Vary"))
+	}
+
+	if xmlWithJSONETag.Code != http.StatusOK {
+		t.Errorf("Expected 200 for XML with the JSON ETag, got %d", xmlWithJSONETag.Code)
+	}
+
+	// Qualquer representação da versão atual serve para escrever
+	if updated.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the XML ETag on a JSON update, got %d: %s", updated.Code, updated.Body.String())
+	}
+}
+
+func TestRoutes_HistoryAndRestore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := setupRouter(inMemoryDependencies())
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, map[string]string{"X-Request-ID": "req-create"})
+	if got := created.Header().Get("X-Request-ID"); got != "req-create" {
+		t.Errorf("Expected X-Request-ID to be echoed, got %q", got)
+	}
+
+	renamed := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets & Co"}`, map[string]string{"If-Match": created.Header().Get("ETag")})
+	serve(http.MethodDelete, "/v1/categories/1", "", map[string]string{"If-Match": renamed.Header().Get("ETag")})
+
+	if recorder := serve(http.MethodGet, "/v1/categories/1", "", nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 for deleted category, got %d", recorder.Code)
+	}
+
+	restored := serve(http.MethodPost, "/v1/categories/1/restore", "", nil)
+	if restored.Code != http.StatusOK || !strings.Contains(restored.Body.String(), `"version":4`) {
+		t.Fatalf("Expected 200 with restored category, got %d: %s", restored.Code, restored.Body.String())
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/categories/1/restore", "", nil); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 restoring a category that is not deleted, got %d", recorder.Code)
+	}
+
+	history := serve(http.MethodGet, "/v1/categories/1/history", "", nil)
+	if history.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", history.Code, history.Body.String())
+	}
+
+	var body struct {
+		Data []struct {
+			Action    string `json:"action"`
+			Actor     string `json:"actor"`
+			RequestID string `json:"request_id"`
+			Before    *struct {
+				Name string `json:"name"`
+			} `json:"before"`
+			After *struct {
+				Name string `json:"name"`
+			} `json:"after"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(history.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Error decoding body: %v", err)
+	}
+
+	var actions []string
+	for _, entry := range body.Data {
+		actions = append(actions, entry.Action)
+	}
+	if strings.Join(actions, ",") != "create,update,delete,restore" {
+		t.Fatalf("Unexpected history %v", actions)
+	}
+
+	if body.Data[0].RequestID != "req-create" || body.Data[0].Actor != "anonymous" {
+		t.Errorf("Unexpected create entry actor %q request %q", body.Data[0].Actor, body.Data[0].RequestID)
+	}
+
+	if body.Data[1].Before.Name != "Electronics" || body.Data[1].After.Name != "Gadgets & Co" {
+		t.Errorf("Unexpected rename snapshots %s", history.Body.String())
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories/42/history", "", nil); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown category history, got %d", recorder.Code)
+	}
+}
+
+func TestRoutes_EventsReachBusThroughOutbox(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	router, _ := setupRouter(deps)
+
+	recorder := eventstest.NewRecorder()
+	deps.eventBus.Subscribe(events.AllEvents, func(ctx context.Context, event events.Event) error {
+		recorder.Publish(ctx, event)
+		return nil
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/categories", strings.NewReader(`{"name":"Electronics"}`))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Nada é publicado antes do relay passar pela outbox
+	recorder.AssertPublished(t)
+
+	if _, err := deps.outboxRelay.DispatchPending(context.Background()); err != nil {
+		t.Fatalf("Error dispatching outbox: %v", err)
+	}
+
+	recorder.AssertPublished(t, "category.created")
+}
+
+func TestRoutes_WebhooksDeliverSignedEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// O receptor do teste está no loopback
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	deps := inMemoryDependencies()
+	router, _ := setupRouter(deps)
+
+	var signatureErr error
+	var receivedEvent string
+	receiver := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		signatureErr = webhooks.Verify("a-very-secret-value", request.Header.Get(webhooks.SignatureHeader), body, time.Now(), time.Minute)
+		receivedEvent = request.Header.Get(webhooks.EventHeader)
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/webhooks", `{"url":"ftp://example.com","events":["*"]}`); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a non-http URL, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/webhooks", `{"url":"`+receiver.URL+`","events":["category.exploded"]}`); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an unknown event, got %d", recorder.Code)
+	}
+
+	created := serve(http.MethodPost, "/v1/webhooks", `{"url":"`+receiver.URL+`","events":["category.created"],"secret":"a-very-secret-value"}`)
+	if created.Code != http.StatusCreated || created.Header().Get("Location") != "/v1/webhooks/1" {
+		t.Fatalf("Expected 201 with Location, got %d: %s", created.Code, created.Body.String())
+	}
+
+	if listed := serve(http.MethodGet, "/v1/webhooks", ""); strings.Contains(listed.Body.String(), "a-very-secret-value") {
+		t.Errorf("Expected the secret to be returned only on creation, got %s", listed.Body.String())
+	}
+
+	serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`)
+
+	if _, err := deps.outboxRelay.DispatchPending(context.Background()); err != nil {
+		t.Fatalf("Error dispatching outbox: %v", err)
+	}
+	if delivered, err := deps.webhookDispatcher.DeliverDue(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("Expected 1 webhook delivery, got %d (%v)", delivered, err)
+	}
+
+	if receivedEvent != "category.created" || signatureErr != nil {
+		t.Errorf("Expected a signed category.created, got %q (%v)", receivedEvent, signatureErr)
+	}
+
+	deliveries := serve(http.MethodGet, "/v1/webhooks/1/deliveries", "")
+	var log struct {
+		Data []struct {
+			Event          string `json:"event"`
+			Status         string `json:"status"`
+			Attempts       int    `json:"attempts"`
+			LastStatusCode int    `json:"last_status_code"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(deliveries.Body.Bytes(), &log); err != nil || len(log.Data) != 1 {
+		t.Fatalf("Expected one logged delivery, got %s", deliveries.Body.String())
+	}
+	if entry := log.Data[0]; entry.Status != "succeeded" || entry.Attempts != 1 || entry.LastStatusCode != http.StatusNoContent {
+		t.Errorf("Unexpected delivery log %+v", entry)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/webhooks/dead-letters", ""); !strings.Contains(recorder.Body.String(), `"total":0`) {
+		t.Errorf("Expected an empty dead-letter list, got %s", recorder.Body.String())
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/webhooks/deliveries/1/redeliver", ""); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected 409 redelivering a delivery that is not dead, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodDelete, "/v1/webhooks/1", ""); recorder.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/webhooks/1/deliveries", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted webhook, got %d", recorder.Code)
+	}
+}
+
+func TestRoutes_CategoryStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	deps.streamHeartbeat = 20 * time.Millisecond
+	router, _ := setupRouter(deps)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		if _, err := deps.outboxRelay.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("Error dispatching outbox: %v", err)
+		}
+		return recorder
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories/stream", "", map[string]string{"Accept": "application/json"}); recorder.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 without text/event-stream, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories/stream", "", map[string]string{"Last-Event-ID": "abc"}); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid Last-Event-ID, got %d", recorder.Code)
+	}
+
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/categories/stream", nil)
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Last-Event-ID", "0")
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("Error opening stream: %v", err)
+	}
+	defer response.Body.Close()
+
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("Expected text/event-stream, got %q", response.Header.Get("Content-Type"))
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	expect := func(want string) {
+		t.Helper()
+		for line := range lines {
+			if line == want {
+				return
+			}
+		}
+		t.Fatalf("Stream ended before %q", want)
+	}
+
+	// Last-Event-ID: 0 reenvia o evento publicado antes da conexão
+	expect("id:1")
+	expect("event:category.created")
+
+	// Eventos de outro tenant não chegam ao stream
+	serve(http.MethodPost, "/v1/categories", `{"name":"Furniture"}`, map[string]string{"X-Tenant-ID": "acme"})
+	serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets"}`, map[string]string{"If-Match": created.Header().Get("ETag")})
+	for line := range lines {
+		if strings.Contains(line, "Furniture") {
+			t.Fatalf("Expected no events from another tenant, got %q", line)
+		}
+		if line == "event:category.renamed" {
+			break
+		}
+	}
+
+	expect(": heartbeat")
+}
+
+func TestRoutes_Authentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+
+	secret := []byte("test-secret-test-secret-test-secret")
+	verifier, _ := auth.NewJWTVerifier(auth.JWTConfig{Secret: secret})
+	apiKey, hash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(auth.APIKey{Name: "importer", Hash: hash, Roles: []string{"editor"}})
+	deps.authenticator = auth.NewAuthenticator(verifier, apiKeys, nil)
+
+	router, _ := setupRouter(deps)
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "maria",
+		"roles": []string{"editor"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if recorder := serve(http.MethodGet, "/helthz", "", nil); recorder.Code != http.StatusOK {
+		t.Errorf("Expected /helthz to stay public, got %d", recorder.Code)
+	}
+
+	unauthenticated := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil)
+	if unauthenticated.Code != http.StatusUnauthorized || unauthenticated.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("Expected 401 with WWW-Authenticate, got %d", unauthenticated.Code)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/webhooks", "", map[string]string{"Authorization": "Bearer " + token + "x"}); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a tampered token, got %d", recorder.Code)
+	}
+
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, map[string]string{"Authorization": "Bearer " + token})
+	if created.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 with a valid token, got %d: %s", created.Code, created.Body.String())
+	}
+
+	if recorder := serve(http.MethodPut, "/v1/categories/1", `{"name":"Gadgets"}`, map[string]string{auth.APIKeyHeader: apiKey, "If-Match": created.Header().Get("ETag")}); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with a valid API key, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// O principal chega aos use cases como ator da auditoria
+	history := serve(http.MethodGet, "/v1/categories/1/history", "", map[string]string{"Authorization": "Bearer " + token})
+	if body := history.Body.String(); !strings.Contains(body, `"actor":"maria"`) || !strings.Contains(body, `"actor":"importer"`) {
+		t.Errorf("Expected maria and importer as actors, got %s", body)
+	}
+}
+
+func TestRoutes_Authorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+
+	viewerKey, viewerHash := auth.NewAPIKey()
+	reporterKey, reporterHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "dashboard", Hash: viewerHash, Roles: []string{"viewer"}},
+		auth.APIKey{Name: "reporter", Hash: reporterHash, Roles: []string{"reporter"}},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.policy, _ = authz.NewPolicy(authz.Rules{Roles: map[string]authz.Role{
+		"viewer":   authz.DefaultRules.Roles["viewer"],
+		"reporter": {Inherits: []string{"viewer"}, Permissions: []string{"categories:history"}},
+	}})
+
+	deps.categoryRepository.Save(&entities.Category{Name: "Electronics"})
+	router, _ := setupRouter(deps)
+
+	serve := func(method, path, apiKey string, headers ...string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, nil)
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	read := serve(http.MethodGet, "/v1/categories/1", viewerKey)
+	if read.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for a viewer read, got %d: %s", read.Code, read.Body.String())
+	}
+
+	forbidden := serve(http.MethodDelete, "/v1/categories/1", viewerKey, "If-Match", read.Header().Get("ETag"))
+	if forbidden.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403 for a viewer delete, got %d: %s", forbidden.Code, forbidden.Body.String())
+	}
+	if body := forbidden.Body.String(); !strings.Contains(body, `"code":"forbidden"`) || !strings.Contains(body, `"permission":"categories:delete"`) {
+		t.Errorf("Expected the missing permission in the body, got %s", body)
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/webhooks", viewerKey); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a viewer listing webhooks, got %d", recorder.Code)
+	}
+
+	// Papéis e herança vêm da política configurada
+	if recorder := serve(http.MethodGet, "/v1/categories/1/history", viewerKey); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a viewer reading history, got %d", recorder.Code)
+	}
+	// A categoria foi gravada direto no repositório, sem auditoria: 404
+	if recorder := serve(http.MethodGet, "/v1/categories/1/history", reporterKey); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected the reporter to pass authorization, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestRoutes_Tenancy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+
+	apiKey, hash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(auth.APIKey{Name: "acme-importer", Hash: hash, Roles: []string{"admin"}, Tenant: "acme"})
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.tenantResolver.BaseDomain = "shop.test"
+	router, _ := setupRouter(deps)
+
+	serve := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		for key, value := range headers {
+			if key == "Host" {
+				request.Host = value
+				continue
+			}
+			request.Header.Set(key, value)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	created := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, map[string]string{"Idempotency-Key": "same-key"})
+	if created.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", created.Code, created.Body.String())
+	}
+
+	// As credenciais estão presas ao acme: pedir outro tenant é 403
+	if recorder := serve(http.MethodGet, "/v1/categories", "", map[string]string{"X-Tenant-ID": "globex"}); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another tenant with bound credentials, got %d", recorder.Code)
+	}
+	if recorder := serve(http.MethodGet, "/v1/categories", "", map[string]string{"Host": "globex.shop.test"}); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another tenant's subdomain, got %d", recorder.Code)
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, nil); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a duplicate name in the tenant, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// Sem tenant nas credenciais, header e subdomínio escolhem o catálogo
+	deps.authenticator = auth.Disabled()
+	router, _ = setupRouter(deps)
+
+	if recorder := serve(http.MethodGet, "/v1/categories", "", map[string]string{"X-Tenant-ID": "Not A Tenant"}); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid tenant, got %d", recorder.Code)
+	}
+
+	for _, headers := range []map[string]string{{"X-Tenant-ID": "globex"}, {"Host": "globex.shop.test"}, nil} {
+		if recorder := serve(http.MethodGet, "/v1/categories", "", headers); strings.Contains(recorder.Body.String(), "Electronics") {
+			t.Errorf("Expected acme's categories hidden from %v, got %s", headers, recorder.Body.String())
+		}
+		if recorder := serve(http.MethodGet, "/v1/categories/1", "", headers); recorder.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for acme's category from %v, got %d", headers, recorder.Code)
+		}
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories/1", "", map[string]string{"Host": "acme.shop.test"}); recorder.Code != http.StatusOK {
+		t.Errorf("Expected 200 from acme's subdomain, got %d", recorder.Code)
+	}
+
+	// A mesma Idempotency-Key em outro tenant não repete a resposta do acme
+	replayed := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, map[string]string{"X-Tenant-ID": "globex", "Idempotency-Key": "same-key"})
+	if replayed.Code != http.StatusCreated || replayed.Header().Get("Idempotent-Replayed") != "" || replayed.Header().Get("Location") == created.Header().Get("Location") {
+		t.Errorf("Expected a new category for globex, got %d %v", replayed.Code, replayed.Header())
+	}
+}
+
+func TestRoutes_UnboundCredentialsStayInTheDefaultTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+
+	editorKey, editorHash := auth.NewAPIKey()
+	opsKey, opsHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "storefront", Hash: editorHash, Roles: []string{"editor"}},
+		auth.APIKey{Name: "ops", Hash: opsHash, Roles: []string{"editor"}, Tenant: auth.AnyTenant},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	router, _ := setupRouter(deps)
+
+	serve := func(method, path, body, apiKey, tenant string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		if tenant != "" {
+			request.Header.Set(tenancy.Header, tenant)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if recorder := serve(http.MethodPost, "/v1/categories", `{"name":"Electronics"}`, opsKey, "other"); recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for a cross-tenant key, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// Sem tenant nas credenciais, o editor só enxerga o tenant padrão
+	if recorder := serve(http.MethodGet, "/v1/categories", "", editorKey, "other"); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 reading another tenant, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := serve(http.MethodPost, "/v1/categories", `{"name":"Furniture"}`, editorKey, "other"); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 writing to another tenant, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder := serve(http.MethodGet, "/v1/categories", "", editorKey, ""); recorder.Code != http.StatusOK || strings.Contains(recorder.Body.String(), "Electronics") {
+		t.Errorf("Expected only the default tenant's categories, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestRoutes_RateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+
+	scriptKey, scriptHash := auth.NewAPIKey()
+	otherKey, otherHash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(
+		auth.APIKey{Name: "script", Hash: scriptHash, Roles: []string{"editor"}},
+		auth.APIKey{Name: "other", Hash: otherHash, Roles: []string{"editor"}},
+	)
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.Rules{
+		Default:    ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Operations: map[string]ratelimit.Limit{"createCategory": {Requests: 2, Per: time.Minute}, "helthz": {}},
+	})
+	router, _ := setupRouter(deps)
+
+	create := func(path, apiKey, name string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"`+name+`"}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	first := create("/v1/categories", scriptKey, "Electronics")
+	create("/v1/categories", scriptKey, "Home & Garden")
+	limited := create("/v1/categories", scriptKey, "Furniture")
+	unversioned := create("/categories", scriptKey, "Furniture")
+	other := create("/v1/categories", otherKey, "Furniture")
+
+	// Assert
+	if first.Code != http.StatusCreated || first.Header().Get("RateLimit-Limit") != "2" || first.Header().Get("RateLimit-Remaining") != "1" {
+		t.Errorf("Expected 201 with 1 request remaining, got %d %v", first.Code, first.Header())
+	}
+
+	if first.Header().Get("RateLimit-Policy") != "2;w=60;burst=2" {
+		t.Errorf("Expected RateLimit-Policy 2;w=60;burst=2, got %q", first.Header().Get("RateLimit-Policy"))
+	}
+
+	if limited.Code != http.StatusTooManyRequests || !strings.Contains(limited.Body.String(), "rate_limited") {
+		t.Fatalf("Expected 429 rate_limited after the burst, got %d: %s", limited.Code, limited.Body.String())
+	}
+
+	// Um token volta a cada 30s
+	if limited.Header().Get("Retry-After") != "30" || limited.Header().Get("RateLimit-Remaining") != "0" || limited.Header().Get("RateLimit-Reset") != "60" {
+		t.Errorf("Expected Retry-After 30 and the bucket empty for 60s, got %v", limited.Header())
+	}
+
+	// A rota sem versão divide a cota com a v1
+	if unversioned.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 on the unversioned alias, got %d", unversioned.Code)
+	}
+
+	if other.Code != http.StatusCreated {
+		t.Errorf("Expected another API key to have its own quota, got %d", other.Code)
+	}
+
+	list := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	request.Header.Set(auth.APIKeyHeader, scriptKey)
+	router.ServeHTTP(list, request)
+	if list.Code != http.StatusOK || list.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("Expected the list to use the default limit, got %d %v", list.Code, list.Header())
+	}
+
+	helthz := httptest.NewRecorder()
+	router.ServeHTTP(helthz, httptest.NewRequest(http.MethodGet, "/helthz", nil))
+	if helthz.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("Expected no rate limit on /helthz, got %v", helthz.Header())
+	}
+}
+
+func TestRoutes_RateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	apiKeys, _ := auth.NewAPIKeys()
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.Rules{
+		Default:    ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Operations: map[string]ratelimit.Limit{ratelimit.AuthenticateOperation: {Requests: 2, Per: time.Minute}},
+	})
+
+	guess := func(router *gin.Engine, remoteAddr, forwardedFor string) int {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		request.Header.Set(auth.APIKeyHeader, "not-a-key")
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// Act
+	router, _ := setupRouter(deps)
+	var spoofed []int
+	for _, forwardedFor := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		spoofed = append(spoofed, guess(router, "203.0.113.7:4000", forwardedFor))
+	}
+
+	deps.trustedProxies = []string{"10.0.0.0/8"}
+	proxiedRouter, _ := setupRouter(deps)
+	var proxied []int
+	for _, forwardedFor := range []string{"198.51.100.4", "198.51.100.5", "198.51.100.6"} {
+		proxied = append(proxied, guess(proxiedRouter, "10.0.0.2:4000", forwardedFor))
+	}
+
+	// Assert
+	// Sem proxies confiáveis o X-Forwarded-For não muda o IP do cliente
+	if !slices.Equal(spoofed, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}) {
+		t.Errorf("Expected the connection's IP limited despite X-Forwarded-For, got %v", spoofed)
+	}
+
+	if !slices.Equal(proxied, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized}) {
+		t.Errorf("Expected X-Forwarded-For from a trusted proxy to identify the clients, got %v", proxied)
+	}
+}
+
+func TestRoutes_RateLimitByIPBeforeAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := inMemoryDependencies()
+	apiKey, hash := auth.NewAPIKey()
+	apiKeys, _ := auth.NewAPIKeys(auth.APIKey{Name: "script", Hash: hash, Roles: []string{"viewer"}})
+	deps.authenticator = auth.NewAuthenticator(nil, apiKeys, nil)
+	deps.rateLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.Rules{
+		Default:    ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Operations: map[string]ratelimit.Limit{ratelimit.AuthenticateOperation: {Requests: 3, Per: time.Minute}},
+	})
+	router, _ := setupRouter(deps)
+
+	list := func(remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set(auth.APIKeyHeader, apiKey)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	var guesses []int
+	for range 3 {
+		guesses = append(guesses, list("203.0.113.7:4000", "not-a-key").Code)
+	}
+	limited := list("203.0.113.7:4000", apiKey)
+	otherIP := list("198.51.100.2:4000", apiKey)
+
+	// Assert
+	if !slices.Equal(guesses, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized}) {
+		t.Errorf("Expected 401 for the invalid keys, got %v", guesses)
+	}
+
+	if limited.Code != http.StatusTooManyRequests || limited.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 once the IP spent its quota on invalid keys, got %d", limited.Code)
+	}
+
+	if otherIP.Code != http.StatusOK || otherIP.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("Expected another IP to pass with the operation's limit, got %d %v", otherIP.Code, otherIP.Header())
+	}
+}
@@ -0,0 +1,202 @@
+// Package rpc serve a API gRPC de categorias (proto/category/v1) sobre os
+// mesmos use cases, credenciais e tenants da API HTTP.
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"gin-quickstart/cmd/api/rpc/categorypb"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/stream"
+	"gin-quickstart/internal/tenancy"
+	use_cases "gin-quickstart/internal/use-cases"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+// ResetEvent avisa que eventos se perderam, como no stream SSE.
+const ResetEvent = stream.ResetEvent
+
+type categoryServer struct {
+	categorypb.UnimplementedCategoryServiceServer
+	repository  repositories.ICategoryRepository
+	broadcaster *stream.Broadcaster
+}
+
+func NewCategoryServer(repository repositories.ICategoryRepository, broadcaster *stream.Broadcaster) *categoryServer {
+	return &categoryServer{
+		repository:  repository,
+		broadcaster: broadcaster,
+	}
+}
+
+func (server *categoryServer) CreateCategory(ctx context.Context, request *categorypb.CreateCategoryRequest) (*categorypb.Category, error) {
+	category, err := use_cases.NewCreateCategoryUseCase(server.repository).Execute(ctx, request.GetName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newCategory(category), nil
+}
+
+func (server *categoryServer) GetCategory(ctx context.Context, request *categorypb.GetCategoryRequest) (*categorypb.Category, error) {
+	category, err := use_cases.NewGetCategoryUseCase(server.repository).Execute(ctx, uint(request.GetId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newCategory(category), nil
+}
+
+// ListCategories pagina pelo ID (as categorias vêm ordenadas por ele): o
+// page token é o último ID da página anterior, então uma categoria criada
+// entre duas páginas não desloca as seguintes.
+func (server *categoryServer) ListCategories(ctx context.Context, request *categorypb.ListCategoriesRequest) (*categorypb.ListCategoriesResponse, error) {
+	pageSize := int(request.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = DefaultPageSize
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	}
+
+	after, err := decodePageToken(request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	// Uma categoria a mais diz se existe a próxima página
+	categories, total, err := use_cases.NewListCategoryPageUseCase(server.repository).Execute(ctx, after, pageSize+1)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &categorypb.ListCategoriesResponse{TotalSize: int32(total)}
+	if len(categories) > pageSize {
+		categories = categories[:pageSize]
+		response.NextPageToken = encodePageToken(uint64(categories[pageSize-1].ID))
+	}
+
+	for _, category := range categories {
+		response.Categories = append(response.Categories, newCategory(category))
+	}
+
+	return response, nil
+}
+
+func (server *categoryServer) UpdateCategory(ctx context.Context, request *categorypb.UpdateCategoryRequest) (*categorypb.Category, error) {
+	category, err := use_cases.NewUpdateCategoryUseCase(server.repository).Execute(ctx, uint(request.GetId()), request.GetName(), uint(request.GetVersion()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newCategory(category), nil
+}
+
+func (server *categoryServer) DeleteCategory(ctx context.Context, request *categorypb.DeleteCategoryRequest) (*emptypb.Empty, error) {
+	err := use_cases.NewDeleteCategoryUseCase(server.repository).Execute(ctx, uint(request.GetId()), uint(request.GetVersion()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// WatchCategories segue as regras do stream SSE: com last_event_id os
+// eventos perdidos ainda no buffer são reenviados, e um cliente lento é
+// desconectado (UNAVAILABLE) para reconectar de onde parou.
+func (server *categoryServer) WatchCategories(request *categorypb.WatchCategoriesRequest, watch categorypb.CategoryService_WatchCategoriesServer) error {
+	ctx := watch.Context()
+
+	// O stream não passa por um use case, então a permissão é checada aqui
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return statusError(err)
+	}
+
+	tenant := tenancy.From(ctx)
+
+	var client *stream.Client
+	missed, complete := []stream.Message(nil), true
+
+	if request.LastEventId != nil {
+		client, missed, complete = server.broadcaster.SubscribeAfter(tenant, request.GetLastEventId())
+	} else {
+		client = server.broadcaster.Subscribe(tenant)
+	}
+
+	defer server.broadcaster.Unsubscribe(client)
+
+	if !complete {
+		if err := watch.Send(&categorypb.CategoryEvent{Event: ResetEvent, Data: &structpb.Struct{}}); err != nil {
+			return err
+		}
+	}
+
+	for _, message := range missed {
+		if err := sendMessage(watch, message); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-client.Messages():
+			if !ok {
+				return status.Error(codes.Unavailable, "client fell behind the stream, reconnect with last_event_id")
+			}
+			if err := sendMessage(watch, message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendMessage(watch categorypb.CategoryService_WatchCategoriesServer, message stream.Message) error {
+	data := &structpb.Struct{}
+	if err := protojson.Unmarshal(message.Data, data); err != nil {
+		return err
+	}
+
+	return watch.Send(&categorypb.CategoryEvent{Id: message.ID, Event: message.Event, Data: data})
+}
+
+func newCategory(category *entities.Category) *categorypb.Category {
+	return &categorypb.Category{
+		Id:         uint64(category.ID),
+		Name:       category.Name,
+		Version:    uint64(category.Version),
+		CreateTime: timestamppb.New(category.CreatedAt),
+		UpdateTime: timestamppb.New(category.UpdatedAt),
+	}
+}
+
+func encodePageToken(lastID uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(lastID, 10)))
+}
+
+func decodePageToken(token string) (uint, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	lastID, err := strconv.ParseUint(string(decoded), 10, 64)
+	return uint(lastID), err
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"errors"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError traduz os erros de domínio e de repositório para status
+// gRPC, como o errorStatus da API HTTP. Erros de validação levam o campo em
+// um errdetails.BadRequest.
+func statusError(err error) error {
+	var validationError *entities.ValidationError
+	var forbiddenError *authz.ForbiddenError
+
+	switch {
+	case errors.As(err, &validationError):
+		st, _ := status.New(codes.InvalidArgument, validationError.Message).WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: validationError.Field, Description: validationError.Message}},
+		})
+		return st.Err()
+	case errors.As(err, &forbiddenError):
+		return status.Error(codes.PermissionDenied, forbiddenError.Error())
+	case errors.Is(err, authz.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, repositories.ErrCategoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repositories.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, repositories.ErrDuplicateName):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		log.Printf("[gRPC] internal error: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
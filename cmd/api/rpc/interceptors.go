@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+	"log"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey é o metadado com o request ID, o mesmo X-Request-ID da API
+// HTTP.
+const RequestIDKey = "x-request-id"
+
+// Interceptor prepara o context de cada chamada antes do handler, como os
+// middlewares do gin fazem com o context da requisição. Um erro encerra a
+// chamada com ele como status.
+type Interceptor func(ctx context.Context) (context.Context, error)
+
+// Unary e Stream aplicam os interceptors, em ordem, às chamadas unárias e
+// de streaming.
+func Unary(interceptors ...Interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := intercept(ctx, interceptors)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, request)
+	}
+}
+
+func Stream(interceptors ...Interceptor) grpc.StreamServerInterceptor {
+	return func(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := intercept(stream.Context(), interceptors)
+		if err != nil {
+			return err
+		}
+		return handler(server, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func intercept(ctx context.Context, interceptors []Interceptor) (context.Context, error) {
+	for _, interceptor := range interceptors {
+		var err error
+		if ctx, err = interceptor(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// contextStream troca o context do stream pelo dos interceptors.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextStream) Context() context.Context {
+	return stream.ctx
+}
+
+// RequestID repassa o x-request-id recebido (ou gera um novo), devolve o
+// mesmo valor no header da resposta e o leva para a auditoria.
+func RequestID() Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		requestID := ""
+		if values := metadata.ValueFromIncomingContext(ctx, RequestIDKey); len(values) > 0 {
+			requestID = values[0]
+		}
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID))
+		return audit.WithRequestID(ctx, requestID), nil
+	}
+}
+
+// Authenticate exige as mesmas credenciais da API HTTP, lidas dos
+// metadados authorization e x-api-key (UNAUTHENTICATED caso contrário).
+func Authenticate(authenticator auth.IAuthenticator) Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		principal, err := authenticator.Authenticate(requestFromMetadata(ctx))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		ctx = auth.WithPrincipal(ctx, principal)
+		return audit.WithActor(ctx, principal.Subject), nil
+	}
+}
+
+// Authorize coloca a política no context; a verificação fica nos use cases.
+func Authorize(policy *authz.Policy) Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		return authz.WithPolicy(ctx, policy), nil
+	}
+}
+
+// Tenant resolve o tenant pelas credenciais, pelo metadado x-tenant-id (ou
+// o header configurado) e pelo :authority, como o middlewares.Tenant.
+func Tenant(resolver tenancy.Resolver) Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		principal, _ := auth.PrincipalFrom(ctx)
+
+		tenant, err := resolver.Resolve(requestFromMetadata(ctx), principal)
+
+		var mismatchError *tenancy.MismatchError
+		switch {
+		case errors.As(err, &mismatchError):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return tenancy.WithTenant(ctx, tenant), nil
+	}
+}
+
+// requestFromMetadata monta uma requisição HTTP com os metadados como
+// headers, e com o estado TLS da conexão, para reaproveitar o authenticator
+// (inclusive com certificados de cliente) e o resolver de tenant.
+func requestFromMetadata(ctx context.Context) *http.Request {
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+
+	if caller, ok := peer.FromContext(ctx); ok {
+		if info, ok := caller.AuthInfo.(credentials.TLSInfo); ok {
+			request.TLS = &info.State
+		}
+	}
+
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range incoming {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	if authority := incoming.Get(":authority"); len(authority) > 0 {
+		request.Host = authority[0]
+	}
+
+	return request
+}
+
+func newRequestID() string {
+	buffer := make([]byte, 16)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+// UnaryLogger e StreamLogger escrevem uma linha por chamada no formato do
+// logger do gin, com o código de status no lugar do HTTP.
+func UnaryLogger() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		response, err := handler(ctx, request)
+		logCall(info.FullMethod, start, err)
+		return response, err
+	}
+}
+
+func StreamLogger() grpc.StreamServerInterceptor {
+	return func(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(server, stream)
+		logCall(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(method string, start time.Time, err error) {
+	log.Printf("[gRPC] %-16s | %13v | %s", status.Code(err), time.Since(start), method)
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+	"gin-quickstart/cmd/api/rpc/categorypb"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/ratelimit"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// operations liga cada método ao operationId da rota HTTP equivalente: as
+// duas APIs usam as mesmas regras e, com o mesmo ratelimit.ClientKey, a
+// mesma cota. Os demais métodos (ex: reflection) usam o próprio nome, com o
+// limite Default.
+var operations = map[string]string{
+	categorypb.CategoryService_CreateCategory_FullMethodName:  "createCategory",
+	categorypb.CategoryService_GetCategory_FullMethodName:     "getCategory",
+	categorypb.CategoryService_ListCategories_FullMethodName:  "listCategories",
+	categorypb.CategoryService_UpdateCategory_FullMethodName:  "updateCategory",
+	categorypb.CategoryService_DeleteCategory_FullMethodName:  "deleteCategory",
+	categorypb.CategoryService_WatchCategories_FullMethodName: "streamCategories",
+}
+
+// RateLimit consome um token do bucket do cliente na operação do método e
+// encerra a chamada com RESOURCE_EXHAUSTED quando ele está vazio. Os
+// metadados ratelimit-* e retry-after são os headers da API HTTP. Se o
+// store falhar a chamada passa, como no middlewares.RateLimit.
+func RateLimit(limiter *ratelimit.Limiter) Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		method, _ := grpc.Method(ctx)
+		operation, ok := operations[method]
+		if !ok {
+			operation = method
+		}
+
+		principal, _ := auth.PrincipalFrom(ctx)
+		return ctx, allow(ctx, limiter, operation, ratelimit.ClientKey(principal, peerIP(ctx)), true)
+	}
+}
+
+// RateLimitByIP é o limite por IP de ratelimit.AuthenticateOperation, para
+// rodar antes do Authenticate: credenciais inválidas também gastam cota.
+func RateLimitByIP(limiter *ratelimit.Limiter) Interceptor {
+	return func(ctx context.Context) (context.Context, error) {
+		return ctx, allow(ctx, limiter, ratelimit.AuthenticateOperation, "ip:"+peerIP(ctx), false)
+	}
+}
+
+// allow só manda os metadados ratelimit-* com report, para que o limite
+// por IP não os repita ao lado dos da operação.
+func allow(ctx context.Context, limiter *ratelimit.Limiter, operation, client string, report bool) error {
+	limit := limiter.Limit(operation)
+	if limit.Unlimited() {
+		return nil
+	}
+
+	result, err := limiter.Allow(operation, client)
+	if err != nil {
+		log.Printf("[gRPC] rate limit: %v", err)
+		return nil
+	}
+
+	header := metadata.MD{}
+	if report {
+		header.Set("ratelimit-limit", strconv.Itoa(result.Limit))
+		header.Set("ratelimit-remaining", strconv.Itoa(result.Remaining))
+		header.Set("ratelimit-reset", ceilSeconds(result.Reset))
+		header.Set("ratelimit-policy", limit.Policy())
+	}
+
+	if !result.Allowed {
+		header.Set("retry-after", ceilSeconds(result.RetryAfter))
+		grpc.SetHeader(ctx, header)
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded, retry after "+ceilSeconds(result.RetryAfter)+"s")
+	}
+
+	if len(header) > 0 {
+		grpc.SetHeader(ctx, header)
+	}
+	return nil
+}
+
+// peerIP é o IP da conexão; não há proxy confiável na frente do gRPC.
+func peerIP(ctx context.Context) string {
+	caller, ok := peer.FromContext(ctx)
+	if !ok || caller.Addr == nil {
+		return ""
+	}
+
+	address := caller.Addr.String()
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
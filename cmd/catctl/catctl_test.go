@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/repositories"
+)
+
+// catctl roda os comandos sobre o mesmo repositório em memória; auditLog
+// lê a auditoria gravada por ele.
+type catctl struct {
+	stores   *stores
+	auditLog audit.IStore
+}
+
+func newCatctl() *catctl {
+	repository := repositories.NewInMemotyCategoryRepository()
+	return &catctl{stores: &stores{repository: repository}, auditLog: repository}
+}
+
+func (catctl *catctl) run(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, err bytes.Buffer
+	cli := newCLI(strings.NewReader(stdin), &out, &err, func() (*stores, error) { return catctl.stores, nil })
+	code = cli.Run(args)
+	return code, out.String(), err.String()
+}
+
+func TestCatctl_CategoryLifecycle(t *testing.T) {
+	// Arrange
+	catctl := newCatctl()
+
+	// Act
+	createCode, created, _ := catctl.run("", "-o", "json", "-actor", "ops", "create", "Electronics")
+	renameCode, _, _ := catctl.run("", "rename", "-version", "1", "1", "Consumer Electronics")
+	staleCode, _, staleErr := catctl.run("", "rename", "-version", "1", "1", "Stale")
+	getCode, table, _ := catctl.run("", "get", "1")
+	deleteCode, _, _ := catctl.run("", "delete", "1")
+	missingCode, _, _ := catctl.run("", "get", "1")
+
+	// Assert
+	if createCode != exitOK {
+		t.Fatalf("Expected create to exit 0, got %d", createCode)
+	}
+
+	var category categoryOutput
+	if err := json.Unmarshal([]byte(created), &category); err != nil || category.ID != 1 || category.Name != "Electronics" || category.Version != 1 {
+		t.Errorf("Expected the created category as JSON, got %s", created)
+	}
+
+	if renameCode != exitOK || getCode != exitOK || deleteCode != exitOK {
+		t.Errorf("Expected rename, get and delete to exit 0, got %d, %d and %d", renameCode, getCode, deleteCode)
+	}
+
+	if staleCode != exitConflict || !strings.Contains(staleErr, "version") {
+		t.Errorf("Expected exit %d for a stale version, got %d: %s", exitConflict, staleCode, staleErr)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(table), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "Consumer Electronics") {
+		t.Errorf("Expected a table with the renamed category, got %q", table)
+	}
+
+	if missingCode != exitNotFound {
+		t.Errorf("Expected exit %d for a deleted category, got %d", exitNotFound, missingCode)
+	}
+
+	// Os comandos passam pela auditoria com o -actor como ator
+	entries, _ := catctl.auditLog.ListByCategory("default", 1)
+	if len(entries) != 3 || entries[0].Actor != "ops" {
+		t.Errorf("Expected 3 audit entries by ops, got %v", entries)
+	}
+}
+
+func TestCatctl_ExitCodes(t *testing.T) {
+	catctl := newCatctl()
+	catctl.run("", "create", "Electronics")
+
+	testCases := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"purge"}, exitUsage},
+		{"invalid id", []string{"get", "abc"}, exitUsage},
+		{"missing argument", []string{"rename", "1"}, exitUsage},
+		{"invalid output", []string{"-o", "yaml", "list"}, exitUsage},
+		{"invalid tenant", []string{"-tenant", "Not A Tenant", "list"}, exitUsage},
+		{"not found", []string{"delete", "42"}, exitNotFound},
+		{"duplicate name", []string{"create", "Electronics"}, exitConflict},
+		{"validation", []string{"create", "TV"}, exitInvalid},
+		{"help", []string{"-h"}, exitOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			code, _, stderr := catctl.run("", testCase.args...)
+
+			// Assert
+			if code != testCase.code {
+				t.Errorf("Expected exit %d, got %d: %s", testCase.code, code, stderr)
+			}
+		})
+	}
+
+	// Sem repositório configurado o erro é de infraestrutura
+	cli := newCLI(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{}, func() (*stores, error) { return nil, errors.New("connection refused") })
+	if code := cli.Run([]string{"list"}); code != exitError {
+		t.Errorf("Expected exit %d when the repository is unavailable, got %d", exitError, code)
+	}
+}
+
+func TestCatctl_TenantScopesCommands(t *testing.T) {
+	// Arrange
+	catctl := newCatctl()
+	catctl.run("", "-tenant", "acme", "create", "Electronics")
+
+	// Act
+	_, acme, _ := catctl.run("", "-tenant", "acme", "-o", "csv", "list")
+	_, other, _ := catctl.run("", "-o", "csv", "list")
+
+	// Assert
+	if !strings.HasPrefix(acme, "id,name,created_at,updated_at,version\n1,Electronics,") {
+		t.Errorf("Expected acme's category as CSV, got %q", acme)
+	}
+
+	if other != "id,name,created_at,updated_at,version\n" {
+		t.Errorf("Expected no categories in the default tenant, got %q", other)
+	}
+}
+
+func TestCatctl_ImportAndExport(t *testing.T) {
+	// Arrange
+	catctl := newCatctl()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "categories.jsonl")
+	os.WriteFile(file, []byte(`{"name":"Electronics"}`+"\n"+`{"name":"TV"}`+"\n"+`{"name":"Home & Garden"}`+"\n"), 0o600)
+
+	// Act
+	dryRunCode, dryRun, _ := catctl.run("", "-o", "json", "import", "-dry-run", file)
+	importCode, imported, _ := catctl.run("", "import", file)
+	stdinCode, _, _ := catctl.run("name\nOutdoors\n", "import", "-format", "csv", "-")
+	noFormatCode, _, _ := catctl.run("", "import", "-")
+	exportCode, exported, exportErr := catctl.run("", "export")
+
+	exportFile := filepath.Join(dir, "export.jsonl")
+	catctl.run("", "export", exportFile)
+	exportedFile, _ := os.ReadFile(exportFile)
+
+	// Assert
+	if dryRunCode != exitInvalid || !strings.Contains(dryRun, `"dry_run": true`) || !strings.Contains(dryRun, `"imported": 2`) {
+		t.Errorf("Expected a dry run report with 2 importable rows, got %d: %s", dryRunCode, dryRun)
+	}
+
+	if importCode != exitInvalid || !strings.Contains(imported, "imported 2 of 3 rows, 1 failed") || !strings.Contains(imported, "\n2     name") {
+		t.Errorf("Expected 2 rows imported and line 2 rejected, got %d: %s", importCode, imported)
+	}
+
+	if stdinCode != exitOK || noFormatCode != exitUsage {
+		t.Errorf("Expected stdin imports with -format only, got %d and %d", stdinCode, noFormatCode)
+	}
+
+	if exportCode != exitOK || strings.Count(exported, "\n") != 4 || !strings.HasPrefix(exported, "id,name,") {
+		t.Errorf("Expected a CSV export with header and 3 categories, got %q", exported)
+	}
+
+	if !strings.Contains(exportErr, "exported 3 categories") {
+		t.Errorf("Expected the count on stderr, got %q", exportErr)
+	}
+
+	if strings.Count(string(exportedFile), "\n") != 3 || !strings.Contains(string(exportedFile), `"name":"Outdoors"`) {
+		t.Errorf("Expected a JSONL export by the file extension, got %q", exportedFile)
+	}
+}
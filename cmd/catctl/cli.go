@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+	"io"
+	"os"
+)
+
+const usage = `Usage: catctl [-tenant TENANT] [-actor NAME] [-o table|json|csv] <command> [arguments]
+
+Commands:
+  list                                      list the categories
+  get <id>                                  show a category
+  create <name>                             create a category
+  rename [-version N] <id> <name>           rename a category (default: current version)
+  delete [-version N] <id>                  delete a category (default: current version)
+  import [-format csv|jsonl] [-dry-run] <file|->
+                                            import categories; the format defaults to the file extension
+  export [-format csv|jsonl] [file|-]       export categories (default: CSV to stdout)
+
+Exit codes:
+  0 success, 1 unexpected error, 2 invalid usage, 3 category not found,
+  4 conflict (stale version or duplicate name), 5 invalid data (including
+  rejected import rows)
+`
+
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitConflict = 4
+	exitInvalid  = 5
+)
+
+// usageError é um comando mal formado: sai com exitUsage e mostra o uso.
+type usageError struct {
+	message string
+}
+
+func (err *usageError) Error() string {
+	return err.message
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// environment é o que cada comando recebe: o context com o operador e o
+// tenant, os repositórios e a saída.
+type environment struct {
+	ctx     context.Context
+	stores  *stores
+	printer printer
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+type command func(env *environment, args []string) error
+
+var commands = map[string]command{
+	"list":   listCommand,
+	"get":    getCommand,
+	"create": createCommand,
+	"rename": renameCommand,
+	"delete": deleteCommand,
+	"import": importCommand,
+	"export": exportCommand,
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	open   func() (*stores, error)
+}
+
+// newCLI recebe em open como chegar aos repositórios, para os testes
+// rodarem os comandos em memória.
+func newCLI(stdin io.Reader, stdout, stderr io.Writer, open func() (*stores, error)) *cli {
+	return &cli{stdin: stdin, stdout: stdout, stderr: stderr, open: open}
+}
+
+// Run executa o comando e devolve o código de saída.
+func (cli *cli) Run(args []string) int {
+	flags := flag.NewFlagSet("catctl", flag.ContinueOnError)
+	flags.SetOutput(cli.stderr)
+	flags.Usage = func() { fmt.Fprint(cli.stderr, usage) }
+
+	tenant := flags.String("tenant", tenancy.Default, "")
+	actor := flags.String("actor", defaultActor(), "")
+	output := flags.String("o", "table", "")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	run, ok := commands[flags.Arg(0)]
+	if !ok {
+		return cli.fail(usagef("unknown command %q", flags.Arg(0)))
+	}
+
+	if err := tenancy.Validate(*tenant); err != nil {
+		return cli.fail(usagef("invalid -tenant %q: %v", *tenant, err))
+	}
+
+	printer, err := newPrinter(*output, cli.stdout)
+	if err != nil {
+		return cli.fail(err)
+	}
+
+	stores, err := cli.open()
+	if err != nil {
+		return cli.fail(err)
+	}
+
+	// O operador age como admin; na auditoria ele aparece como -actor
+	principal := &auth.Principal{Subject: *actor, Roles: []string{"admin"}, Method: auth.MethodCLI}
+	ctx := auth.WithPrincipal(context.Background(), principal)
+	ctx = audit.WithActor(ctx, *actor)
+	ctx = tenancy.WithTenant(ctx, *tenant)
+
+	return cli.fail(run(&environment{
+		ctx:     ctx,
+		stores:  stores,
+		printer: printer,
+		stdin:   cli.stdin,
+		stdout:  cli.stdout,
+		stderr:  cli.stderr,
+	}, flags.Args()[1:]))
+}
+
+// fail mostra o erro e o traduz no código de saída.
+func (cli *cli) fail(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(cli.stderr, "catctl: %v\n", err)
+
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprint(cli.stderr, "\n"+usage)
+	}
+	return code
+}
+
+func exitCode(err error) int {
+	var usageError *usageError
+	var validationError *entities.ValidationError
+	var importError *importFailedError
+
+	switch {
+	case errors.As(err, &usageError):
+		return exitUsage
+	case errors.Is(err, repositories.ErrCategoryNotFound):
+		return exitNotFound
+	case errors.Is(err, repositories.ErrVersionConflict),
+		errors.Is(err, repositories.ErrDuplicateName):
+		return exitConflict
+	case errors.As(err, &validationError), errors.As(err, &importError):
+		return exitInvalid
+	default:
+		return exitError
+	}
+}
+
+// defaultActor identifica o operador na auditoria pelo usuário do shell.
+func defaultActor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return "catctl:" + user
+	}
+	return "catctl"
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gin-quickstart/internal/categoryio"
+	use_cases "gin-quickstart/internal/use-cases"
+	"io"
+	"os"
+	"strconv"
+)
+
+// importFailedError indica um import com linhas recusadas; as importadas
+// continuam gravadas.
+type importFailedError struct {
+	failed int
+}
+
+func (err *importFailedError) Error() string {
+	return fmt.Sprintf("%d rows were rejected", err.failed)
+}
+
+func listCommand(env *environment, args []string) error {
+	if len(args) != 0 {
+		return usagef("list takes no arguments")
+	}
+
+	categories, err := use_cases.NewListCategoriesUseCase(env.stores.repository).Execute(env.ctx)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.categories(categories)
+}
+
+func getCommand(env *environment, args []string) error {
+	if len(args) != 1 {
+		return usagef("get takes a category id")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	category, err := use_cases.NewGetCategoryUseCase(env.stores.repository).Execute(env.ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.category(category)
+}
+
+func createCommand(env *environment, args []string) error {
+	if len(args) != 1 {
+		return usagef("create takes a category name")
+	}
+
+	category, err := use_cases.NewCreateCategoryUseCase(env.stores.repository).Execute(env.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return env.printer.category(category)
+}
+
+func renameCommand(env *environment, args []string) error {
+	flags, version := versionFlags("rename")
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() != 2 {
+		return usagef("rename takes a category id and the new name")
+	}
+
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	category, err := use_cases.NewUpdateCategoryUseCase(env.stores.repository).Execute(env.ctx, id, flags.Arg(1), *version)
+	if err != nil {
+		return err
+	}
+
+	return env.printer.category(category)
+}
+
+func deleteCommand(env *environment, args []string) error {
+	flags, version := versionFlags("delete")
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() != 1 {
+		return usagef("delete takes a category id")
+	}
+
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return use_cases.NewDeleteCategoryUseCase(env.stores.repository).Execute(env.ctx, id, *version)
+}
+
+func importCommand(env *environment, args []string) error {
+	flags := quietFlags("import")
+	format := flags.String("format", "", "")
+	dryRun := flags.Bool("dry-run", false, "")
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() != 1 {
+		return usagef("import takes a file, or - for stdin")
+	}
+
+	path := flags.Arg(0)
+	fileFormat, err := resolveFormat(*format, path, "")
+	if err != nil {
+		return err
+	}
+
+	input := env.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	reader, err := categoryio.NewReader(fileFormat, input)
+	if err != nil {
+		return err
+	}
+
+	report, err := use_cases.NewImportCategoriesUseCase(env.stores.repository).Execute(env.ctx, reader, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if err := env.printer.report(report); err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return &importFailedError{failed: report.Failed}
+	}
+	return nil
+}
+
+// exportCommand escreve o arquivo no formato de import/export, não no
+// formato de saída (-o), para poder ser importado de volta.
+func exportCommand(env *environment, args []string) error {
+	flags := quietFlags("export")
+	format := flags.String("format", "", "")
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() > 1 {
+		return usagef("export takes at most a file")
+	}
+
+	path := flags.Arg(0)
+	fileFormat, err := resolveFormat(*format, path, categoryio.CSV)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = env.stdout
+	if path != "" && path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	writer, err := categoryio.NewWriter(fileFormat, output)
+	if err != nil {
+		return err
+	}
+
+	count, err := use_cases.NewExportCategoriesUseCase(env.stores.repository).Execute(env.ctx, writer)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.stderr, "exported %d categories\n", count)
+	return nil
+}
+
+func quietFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	// O erro sai uma vez só, pelo fail, junto com o uso
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+func versionFlags(name string) (*flag.FlagSet, *uint) {
+	flags := quietFlags(name)
+	return flags, flags.Uint("version", 0, "")
+}
+
+// resolveFormat usa o -format ou a extensão do arquivo; sem nenhum dos
+// dois, fallback (vazio exige um deles).
+func resolveFormat(format, path string, fallback categoryio.Format) (categoryio.Format, error) {
+	if format != "" {
+		parsed, err := categoryio.ParseFormat(format)
+		if err != nil {
+			return "", usagef("%v", err)
+		}
+		return parsed, nil
+	}
+
+	if path != "" && path != "-" {
+		parsed, err := categoryio.FormatFromFilename(path)
+		if err != nil {
+			return "", usagef("%v: use -format", err)
+		}
+		return parsed, nil
+	}
+
+	if fallback == "" {
+		return "", usagef("reading from stdin requires -format csv or -format jsonl")
+	}
+	return fallback, nil
+}
+
+func parseID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, usagef("invalid category id %q", value)
+	}
+	return uint(id), nil
+}
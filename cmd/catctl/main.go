@@ -0,0 +1,42 @@
+// Command catctl administra as categorias direto no repositório configurado
+// (REPOSITORY_DRIVER=postgres e as variáveis DB_*), sem passar pela API
+// HTTP, para correções de dados num shell dentro do container. Os comandos
+// chamam os mesmos use cases da API: a validação, a auditoria e os eventos
+// (gravados na outbox e entregues pela API) são os mesmos.
+package main
+
+import (
+	"errors"
+	"gin-quickstart/internal/config"
+	"gin-quickstart/internal/repositories"
+	"os"
+
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	os.Exit(newCLI(os.Stdin, os.Stdout, os.Stderr, openStores).Run(os.Args[1:]))
+}
+
+// stores guarda o repositório usado pelos comandos, que também grava a
+// auditoria.
+type stores struct {
+	repository repositories.ICategoryRepository
+}
+
+func openStores() (*stores, error) {
+	if config.RepositoryDriver() != "postgres" {
+		return nil, errors.New("catctl requires REPOSITORY_DRIVER=postgres: the memory repository only exists inside the API process")
+	}
+
+	cfg := config.NewConfig()
+	if err := cfg.ConnectDB(); err != nil {
+		return nil, err
+	}
+	// O log de SQL do gorm sai no stdout, misturado à saída dos comandos
+	cfg.DB.Logger = logger.Discard
+
+	return &stores{
+		repository: repositories.NewPostgresCategoryRepository(cfg.DB),
+	}, nil
+}
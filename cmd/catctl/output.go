@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gin-quickstart/internal/entities"
+	use_cases "gin-quickstart/internal/use-cases"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// printer escreve o resultado dos comandos no formato do -o.
+type printer interface {
+	categories(categories []*entities.Category) error
+	category(category *entities.Category) error
+	report(report *use_cases.ImportReport) error
+}
+
+func newPrinter(format string, output io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return tablePrinter{output}, nil
+	case "json":
+		return jsonPrinter{output}, nil
+	case "csv":
+		return csvPrinter{output}, nil
+	default:
+		return nil, usagef("invalid -o %q: use table, json or csv", format)
+	}
+}
+
+// categoryOutput tem os mesmos campos das respostas da API.
+type categoryOutput struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint      `json:"version"`
+}
+
+func newCategoryOutput(category *entities.Category) categoryOutput {
+	return categoryOutput{
+		ID:        category.ID,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+		Version:   category.Version,
+	}
+}
+
+func (category categoryOutput) fields() []string {
+	return []string{
+		strconv.FormatUint(uint64(category.ID), 10),
+		category.Name,
+		category.CreatedAt.UTC().Format(time.RFC3339),
+		category.UpdatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatUint(uint64(category.Version), 10),
+	}
+}
+
+var categoryColumns = []string{"id", "name", "created_at", "updated_at", "version"}
+
+type tablePrinter struct {
+	output io.Writer
+}
+
+func (printer tablePrinter) categories(categories []*entities.Category) error {
+	table := tabwriter.NewWriter(printer.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tCREATED AT\tUPDATED AT\tVERSION")
+	for _, category := range categories {
+		fields := newCategoryOutput(category).fields()
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", fields[0], fields[1], fields[2], fields[3], fields[4])
+	}
+	return table.Flush()
+}
+
+func (printer tablePrinter) category(category *entities.Category) error {
+	return printer.categories([]*entities.Category{category})
+}
+
+func (printer tablePrinter) report(report *use_cases.ImportReport) error {
+	verb := "imported"
+	if report.DryRun {
+		verb = "would import"
+	}
+	fmt.Fprintf(printer.output, "%s %d of %d rows, %d failed\n", verb, report.Imported, report.Total, report.Failed)
+
+	if len(report.Errors) == 0 {
+		return nil
+	}
+
+	table := tabwriter.NewWriter(printer.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "LINE\tERROR")
+	for _, importError := range report.Errors {
+		fmt.Fprintf(table, "%d\t%s\n", importError.Line, importError.Message)
+	}
+	return table.Flush()
+}
+
+type jsonPrinter struct {
+	output io.Writer
+}
+
+func (printer jsonPrinter) encode(value any) error {
+	encoder := json.NewEncoder(printer.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func (printer jsonPrinter) categories(categories []*entities.Category) error {
+	outputs := make([]categoryOutput, 0, len(categories))
+	for _, category := range categories {
+		outputs = append(outputs, newCategoryOutput(category))
+	}
+	return printer.encode(outputs)
+}
+
+func (printer jsonPrinter) category(category *entities.Category) error {
+	return printer.encode(newCategoryOutput(category))
+}
+
+type importErrorOutput struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (printer jsonPrinter) report(report *use_cases.ImportReport) error {
+	errors := make([]importErrorOutput, 0, len(report.Errors))
+	for _, importError := range report.Errors {
+		errors = append(errors, importErrorOutput{Line: importError.Line, Message: importError.Message})
+	}
+
+	return printer.encode(struct {
+		DryRun   bool                `json:"dry_run"`
+		Total    int                 `json:"total"`
+		Imported int                 `json:"imported"`
+		Failed   int                 `json:"failed"`
+		Errors   []importErrorOutput `json:"errors"`
+	}{report.DryRun, report.Total, report.Imported, report.Failed, errors})
+}
+
+type csvPrinter struct {
+	output io.Writer
+}
+
+func (printer csvPrinter) categories(categories []*entities.Category) error {
+	writer := csv.NewWriter(printer.output)
+	writer.Write(categoryColumns)
+	for _, category := range categories {
+		writer.Write(newCategoryOutput(category).fields())
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (printer csvPrinter) category(category *entities.Category) error {
+	return printer.categories([]*entities.Category{category})
+}
+
+// report em CSV lista só as linhas recusadas, para corrigir e importar de
+// novo; o resumo fica no código de saída.
+func (printer csvPrinter) report(report *use_cases.ImportReport) error {
+	writer := csv.NewWriter(printer.output)
+	writer.Write([]string{"line", "error"})
+	for _, importError := range report.Errors {
+		writer.Write([]string{strconv.Itoa(importError.Line), importError.Message})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"gin-quickstart/internal/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CategoryAudit é a linha da tabela category_audit. Os snapshots são
+// guardados como jsonb com os mesmos campos da categoria.
+type CategoryAudit struct {
+	ID         uint   `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null;default:default;index"`
+	CategoryID uint   `gorm:"not null;index"`
+	Action     string `gorm:"not null"`
+	Actor      string `gorm:"not null"`
+	RequestID  string
+	Before     *entities.Category `gorm:"type:jsonb;serializer:json"`
+	After      *entities.Category `gorm:"type:jsonb;serializer:json"`
+	CreatedAt  time.Time          `gorm:"not null"`
+}
+
+func (CategoryAudit) TableName() string {
+	return "category_audit"
+}
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *postgresStore {
+	return &postgresStore{
+		db: db,
+	}
+}
+
+func (store *postgresStore) Append(entries ...*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]*CategoryAudit, len(entries))
+	for i, entry := range entries {
+		rows[i] = &CategoryAudit{
+			TenantID:   entry.TenantID,
+			CategoryID: entry.CategoryID,
+			Action:     string(entry.Action),
+			Actor:      entry.Actor,
+			RequestID:  entry.RequestID,
+			Before:     entry.Before,
+			After:      entry.After,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	if err := store.db.Create(rows).Error; err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		entries[i].ID = row.ID
+	}
+
+	return nil
+}
+
+func (store *postgresStore) ListByCategory(tenant string, categoryID uint) ([]*Entry, error) {
+	var rows []*CategoryAudit
+
+	err := store.db.Where("tenant_id = ? AND category_id = ?", tenant, categoryID).Order("id").Find(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = row.entry()
+	}
+
+	return entries, nil
+}
+
+func (store *postgresStore) ListByCategories(tenant string, categoryIDs []uint) (map[uint][]*Entry, error) {
+	entries := make(map[uint][]*Entry)
+
+	if len(categoryIDs) == 0 {
+		return entries, nil
+	}
+
+	var rows []*CategoryAudit
+
+	err := store.db.Where("tenant_id = ? AND category_id IN ?", tenant, categoryIDs).Order("id").Find(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		entries[row.CategoryID] = append(entries[row.CategoryID], row.entry())
+	}
+
+	return entries, nil
+}
+
+func (row *CategoryAudit) entry() *Entry {
+	return &Entry{
+		ID:         row.ID,
+		TenantID:   row.TenantID,
+		CategoryID: row.CategoryID,
+		Action:     Action(row.Action),
+		Actor:      row.Actor,
+		RequestID:  row.RequestID,
+		Before:     row.Before,
+		After:      row.After,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"context"
+	"gin-quickstart/internal/entities"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// Entry é um registro imutável de uma alteração de categoria. Before é nil
+// em create e restore; After é nil em delete.
+type Entry struct {
+	ID         uint
+	TenantID   string
+	CategoryID uint
+	Action     Action
+	Actor      string
+	RequestID  string
+	Before     *entities.Category
+	After      *entities.Category
+	CreatedAt  time.Time
+}
+
+// IStore é o log de auditoria: só aceita novas entradas (append-only).
+// ListByCategory devolve as entradas da categoria do tenant em ordem
+// cronológica; ListByCategories faz o mesmo para várias categorias numa
+// só consulta, agrupando por ID (categorias sem entradas ficam de fora).
+type IStore interface {
+	Append(entries ...*Entry) error
+	ListByCategory(tenant string, categoryID uint) ([]*Entry, error)
+	ListByCategories(tenant string, categoryIDs []uint) (map[uint][]*Entry, error)
+}
+
+// AnonymousActor é usado quando a requisição não identifica quem a fez.
+const AnonymousActor = "anonymous"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor guarda no context quem está fazendo a alteração.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// WithRequestID guarda no context o ID da requisição que originou a alteração.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// NewEntry monta a entrada com o ator e o request ID do context e o tenant
+// da categoria. before e after são copiados, assim alterações posteriores
+// nas categorias não mudam o que foi auditado.
+func NewEntry(ctx context.Context, action Action, before, after *entities.Category) *Entry {
+	entry := &Entry{
+		Action:    action,
+		Actor:     ActorFrom(ctx),
+		RequestID: RequestIDFrom(ctx),
+		Before:    snapshot(before),
+		After:     snapshot(after),
+		CreatedAt: time.Now(),
+	}
+
+	if after != nil {
+		entry.CategoryID, entry.TenantID = after.ID, after.TenantID
+	} else if before != nil {
+		entry.CategoryID, entry.TenantID = before.ID, before.TenantID
+	}
+
+	return entry
+}
+
+func snapshot(category *entities.Category) *entities.Category {
+	if category == nil {
+		return nil
+	}
+	copied := *category
+	return &copied
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// APIKey é uma conta de serviço. Só o SHA-256 da chave é guardado: as
+// chaves são aleatórias e longas, então um hash lento não acrescenta nada.
+type APIKey struct {
+	Name   string   `json:"name"`
+	Hash   string   `json:"hash"`
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant,omitempty"`
+}
+
+type APIKeys struct {
+	byHash map[string]APIKey
+}
+
+func NewAPIKeys(keys ...APIKey) (*APIKeys, error) {
+	byHash := make(map[string]APIKey, len(keys))
+
+	for _, key := range keys {
+		if key.Name == "" || len(key.Hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("api key %q must have a name and a hex SHA-256 hash", key.Name)
+		}
+		byHash[key.Hash] = key
+	}
+
+	return &APIKeys{byHash: byHash}, nil
+}
+
+// LoadAPIKeys lê um arquivo JSON com uma lista de APIKey.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []APIKey
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, fmt.Errorf("invalid api keys file %s: %w", path, err)
+	}
+
+	return NewAPIKeys(keys...)
+}
+
+func (keys *APIKeys) Authenticate(key string) (*Principal, error) {
+	apiKey, ok := keys.byHash[HashAPIKey(key)]
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return &Principal{
+		Subject: apiKey.Name,
+		Roles:   append([]string(nil), apiKey.Roles...),
+		Tenant:  apiKey.Tenant,
+		Method:  MethodAPIKey,
+	}, nil
+}
+
+// HashAPIKey é o hash guardado no arquivo de API keys.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewAPIKey gera uma chave nova e o hash a ser configurado.
+func NewAPIKey() (key, hash string) {
+	buffer := make([]byte, 32)
+	_, _ = rand.Read(buffer)
+	key = "gq_" + hex.EncodeToString(buffer)
+	return key, HashAPIKey(key)
+}
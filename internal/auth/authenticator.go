@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials: send Authorization: Bearer <token> or X-API-Key")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+	// ErrUnknownClientCertificate é de um certificado válido para as CAs
+	// de cliente, mas sem conta de serviço mapeada
+	ErrUnknownClientCertificate = errors.New("client certificate is not mapped to a service account")
+)
+
+type IAuthenticator interface {
+	Authenticate(request *http.Request) (*Principal, error)
+}
+
+// Authenticator aceita um JWT no header Authorization, uma API key em
+// X-API-Key ou, sem nenhum dos dois, o certificado de cliente do mTLS.
+// jwt, apiKeys ou clientCertificates podem ser nil para desligar aquele
+// método.
+type Authenticator struct {
+	jwt                *JWTVerifier
+	apiKeys            *APIKeys
+	clientCertificates *ClientCertificates
+}
+
+func NewAuthenticator(jwt *JWTVerifier, apiKeys *APIKeys, clientCertificates *ClientCertificates) *Authenticator {
+	return &Authenticator{
+		jwt:                jwt,
+		apiKeys:            apiKeys,
+		clientCertificates: clientCertificates,
+	}
+}
+
+func (authenticator *Authenticator) Authenticate(request *http.Request) (*Principal, error) {
+	if header := request.Header.Get("Authorization"); header != "" {
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || authenticator.jwt == nil {
+			return nil, ErrInvalidToken
+		}
+		return authenticator.jwt.Verify(strings.TrimSpace(token))
+	}
+
+	if key := request.Header.Get(APIKeyHeader); key != "" {
+		if authenticator.apiKeys == nil {
+			return nil, ErrInvalidAPIKey
+		}
+		return authenticator.apiKeys.Authenticate(key)
+	}
+
+	// O handshake já verificou a cadeia: o certificado só chega aqui se
+	// foi emitido por uma das CAs de cliente
+	if request.TLS != nil && len(request.TLS.PeerCertificates) > 0 && authenticator.clientCertificates != nil {
+		return authenticator.clientCertificates.Authenticate(request.TLS.PeerCertificates[0])
+	}
+
+	return nil, ErrMissingCredentials
+}
+
+// Anonymous é o principal de todas as requisições com a autenticação
+// desligada: para desenvolvimento local, com acesso total.
+var Anonymous = Principal{Subject: "anonymous", Roles: []string{"admin"}, Tenant: AnyTenant, Method: MethodNone}
+
+type disabledAuthenticator struct{}
+
+// Disabled não exige credenciais e identifica toda requisição como
+// Anonymous. Nunca deve ser usado em produção.
+func Disabled() IAuthenticator {
+	return disabledAuthenticator{}
+}
+
+func (disabledAuthenticator) Authenticate(request *http.Request) (*Principal, error) {
+	principal := Anonymous
+	principal.Roles = append([]string(nil), Anonymous.Roles...)
+	return &principal, nil
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/tlsconfig/tlsconfigtest"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret-test-secret-test-secret")
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	return token
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	verifier, _ := NewJWTVerifier(JWTConfig{Secret: testSecret, Issuer: "issuer", Audience: "api"})
+	valid := jwt.MapClaims{"sub": "maria", "roles": []string{"editor"}, "iss": "issuer", "aud": "api", "exp": time.Now().Add(time.Hour).Unix()}
+
+	with := func(key string, value any) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	otherSecret, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, valid).SignedString([]byte("another-secret"))
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	testCases := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", signHS256(t, valid), true},
+		{"expired", signHS256(t, with("exp", time.Now().Add(-time.Hour).Unix())), false},
+		{"without exp", signHS256(t, with("exp", nil)), false},
+		{"without sub", signHS256(t, with("sub", nil)), false},
+		{"other issuer", signHS256(t, with("iss", "someone-else")), false},
+		{"other audience", signHS256(t, with("aud", "other-api")), false},
+		{"other secret", otherSecret, false},
+		{"alg none", unsigned, false},
+		{"garbage", "not-a-token", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			principal, err := verifier.Verify(testCase.token)
+
+			// Assert
+			if !testCase.valid {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if principal.Subject != "maria" || !principal.HasRole("editor") || principal.Method != MethodJWT {
+				t.Errorf("Unexpected principal %+v", principal)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_RS256WithJWKS(t *testing.T) {
+	// Arrange
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "key-1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	os.WriteFile(path, jwks, 0o600)
+
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatalf("Error loading JWKS: %v", err)
+	}
+	verifier, _ := NewJWTVerifier(JWTConfig{PublicKeys: keys})
+
+	sign := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "joao", "exp": time.Now().Add(time.Hour).Unix()})
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString(key)
+		return signed
+	}
+
+	// Act
+	principal, err := verifier.Verify(sign("key-1"))
+	_, unknownKidErr := verifier.Verify(sign("key-2"))
+	_, hsErr := verifier.Verify(signHS256(t, jwt.MapClaims{"sub": "joao", "exp": time.Now().Add(time.Hour).Unix()}))
+
+	// Assert
+	if err != nil || principal.Subject != "joao" {
+		t.Fatalf("Expected joao, got %+v (%v)", principal, err)
+	}
+	if !errors.Is(unknownKidErr, ErrInvalidToken) {
+		t.Errorf("Expected unknown kid to be rejected, got %v", unknownKidErr)
+	}
+	if !errors.Is(hsErr, ErrInvalidToken) {
+		t.Errorf("Expected HS256 to be rejected without a secret, got %v", hsErr)
+	}
+}
+
+func TestAuthenticator_APIKeys(t *testing.T) {
+	// Arrange
+	key, hash := NewAPIKey()
+	apiKeys, err := NewAPIKeys(APIKey{Name: "billing-service", Hash: hash, Roles: []string{"viewer"}})
+	if err != nil {
+		t.Fatalf("Error building API keys: %v", err)
+	}
+	authenticator := NewAuthenticator(nil, apiKeys, nil)
+
+	request := func(header, value string) *Principal {
+		r := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		principal, _ := authenticator.Authenticate(r)
+		return principal
+	}
+
+	// Act
+	principal := request(APIKeyHeader, key)
+	wrongKey := request(APIKeyHeader, key+"x")
+	bearer := request("Authorization", "Bearer "+key)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	_, missingErr := authenticator.Authenticate(r)
+
+	// Assert
+	if principal == nil || principal.Subject != "billing-service" || !principal.HasRole("viewer") || principal.Method != MethodAPIKey {
+		t.Fatalf("Expected billing-service, got %+v", principal)
+	}
+	if wrongKey != nil || bearer != nil {
+		t.Error("Expected wrong keys and bearer tokens without JWT config to be rejected")
+	}
+	if !errors.Is(missingErr, ErrMissingCredentials) {
+		t.Errorf("Expected ErrMissingCredentials, got %v", missingErr)
+	}
+}
+
+func TestAuthenticator_ClientCertificates(t *testing.T) {
+	// Arrange
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	clientCertificates, err := NewClientCertificates(
+		ClientCertificate{Subject: "CN=billing,O=Acme", Name: "billing-service", Roles: []string{"viewer"}, Tenant: "acme"},
+		ClientCertificate{Subject: "reports", Name: "reports-service", Roles: []string{"reporter"}},
+	)
+	if err != nil {
+		t.Fatalf("Error building client certificates: %v", err)
+	}
+	authenticator := NewAuthenticator(nil, nil, clientCertificates)
+
+	request := func(subject pkix.Name, headers ...string) (*Principal, error) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{ca.Client(t, subject).Leaf}}
+		for i := 0; i+1 < len(headers); i += 2 {
+			r.Header.Set(headers[i], headers[i+1])
+		}
+		return authenticator.Authenticate(r)
+	}
+
+	// Act
+	byDN, _ := request(pkix.Name{CommonName: "billing", Organization: []string{"Acme"}})
+	byCN, _ := request(pkix.Name{CommonName: "reports", Organization: []string{"Anyone"}})
+	_, otherOrgErr := request(pkix.Name{CommonName: "billing", Organization: []string{"Globex"}})
+	_, headerErr := request(pkix.Name{CommonName: "reports"}, APIKeyHeader, "gq_unknown")
+
+	// Assert
+	if byDN == nil || byDN.Subject != "billing-service" || byDN.Tenant != "acme" || byDN.Method != MethodClientCertificate {
+		t.Fatalf("Expected billing-service by the full subject, got %+v", byDN)
+	}
+	if byCN == nil || byCN.Subject != "reports-service" || !byCN.HasRole("reporter") {
+		t.Errorf("Expected reports-service by the common name, got %+v", byCN)
+	}
+	if !errors.Is(otherOrgErr, ErrUnknownClientCertificate) {
+		t.Errorf("Expected ErrUnknownClientCertificate for another organization, got %v", otherOrgErr)
+	}
+	// Credenciais nos headers têm precedência sobre o certificado
+	if !errors.Is(headerErr, ErrInvalidAPIKey) {
+		t.Errorf("Expected the API key checked before the certificate, got %v", headerErr)
+	}
+}
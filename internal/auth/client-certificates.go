@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ClientCertificate é uma conta de serviço autenticada por mTLS. Subject é
+// o DN completo do certificado (CN=billing,O=Acme) ou só o CN; o TLS já
+// verificou a cadeia contra as CAs de cliente, aqui só se mapeia quem é.
+type ClientCertificate struct {
+	Subject string   `json:"subject"`
+	Name    string   `json:"name"`
+	Roles   []string `json:"roles"`
+	Tenant  string   `json:"tenant,omitempty"`
+}
+
+type ClientCertificates struct {
+	bySubject map[string]ClientCertificate
+}
+
+func NewClientCertificates(certificates ...ClientCertificate) (*ClientCertificates, error) {
+	bySubject := make(map[string]ClientCertificate, len(certificates))
+
+	for _, certificate := range certificates {
+		if certificate.Subject == "" || certificate.Name == "" {
+			return nil, fmt.Errorf("client certificate %q must have a subject and a name", certificate.Name)
+		}
+		bySubject[certificate.Subject] = certificate
+	}
+
+	return &ClientCertificates{bySubject: bySubject}, nil
+}
+
+// LoadClientCertificates lê um arquivo JSON com uma lista de
+// ClientCertificate.
+func LoadClientCertificates(path string) (*ClientCertificates, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []ClientCertificate
+	if err := json.Unmarshal(content, &certificates); err != nil {
+		return nil, fmt.Errorf("invalid client certificates file %s: %w", path, err)
+	}
+
+	return NewClientCertificates(certificates...)
+}
+
+// Authenticate procura o DN completo e depois o CN do certificado.
+func (certificates *ClientCertificates) Authenticate(certificate *x509.Certificate) (*Principal, error) {
+	mapped, ok := certificates.bySubject[certificate.Subject.String()]
+	if !ok {
+		mapped, ok = certificates.bySubject[certificate.Subject.CommonName]
+	}
+	if !ok {
+		return nil, ErrUnknownClientCertificate
+	}
+
+	return &Principal{
+		Subject: mapped.Name,
+		Roles:   append([]string(nil), mapped.Roles...),
+		Tenant:  mapped.Tenant,
+		Method:  MethodClientCertificate,
+	}, nil
+}
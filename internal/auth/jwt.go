@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway tolera diferenças de relógio entre o emissor e a API
+const leeway = 30 * time.Second
+
+// JWTConfig configura a validação. Secret habilita HS256; PublicKeys
+// (indexadas pelo kid, ou "" para uma chave única) habilitam RS256. Issuer
+// e Audience, quando informados, precisam bater com as claims.
+type JWTConfig struct {
+	Secret     []byte
+	PublicKeys map[string]*rsa.PublicKey
+	Issuer     string
+	Audience   string
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+}
+
+type JWTVerifier struct {
+	config JWTConfig
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	methods := make([]string, 0, 2)
+	if len(config.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(config.PublicKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("jwt needs a secret or at least one public key")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTVerifier{config: config, parser: jwt.NewParser(options...)}, nil
+}
+
+// Verify valida assinatura, exp, nbf e, se configurados, iss e aud. O sub
+// é obrigatório; as claims roles e tenant viram Principal.Roles e
+// Principal.Tenant.
+func (verifier *JWTVerifier) Verify(token string) (*Principal, error) {
+	var parsed claims
+
+	_, err := verifier.parser.ParseWithClaims(token, &parsed, verifier.key)
+
+	if err != nil || parsed.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &Principal{
+		Subject: parsed.Subject,
+		Roles:   parsed.Roles,
+		Tenant:  parsed.Tenant,
+		Method:  MethodJWT,
+	}, nil
+}
+
+func (verifier *JWTVerifier) key(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return verifier.config.Secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	if key, ok := verifier.config.PublicKeys[kid]; ok {
+		return key, nil
+	}
+
+	// Sem kid no token, vale a chave única configurada
+	if kid == "" && len(verifier.config.PublicKeys) == 1 {
+		for _, key := range verifier.config.PublicKeys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// LoadRSAPublicKey lê uma chave pública RSA em PEM (PKIX ou PKCS#1).
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", path, err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key in %s is not RSA", path)
+	}
+
+	return rsaKey, nil
+}
+
+// jwk é o subconjunto de uma JSON Web Key usado por chaves RSA.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadJWKS lê as chaves RSA de um arquivo JWKS local, indexadas pelo kid.
+// Chaves de outros tipos ou marcadas para criptografia são ignoradas.
+func LoadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS file %s: %w", path, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		n, errN := base64.RawURLEncoding.DecodeString(key.N)
+		e, errE := base64.RawURLEncoding.DecodeString(key.E)
+		if errN != nil || errE != nil || len(e) == 0 {
+			return nil, fmt.Errorf("invalid RSA key %q in %s", key.Kid, path)
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA signing keys in %s", path)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,58 @@
+// Package auth identifica quem faz cada requisição: usuários por JWT
+// (HS256 ou RS256) e contas de serviço por API key ou certificado de
+// cliente (mTLS).
+package auth
+
+import (
+	"context"
+)
+
+type Method string
+
+const (
+	MethodJWT    Method = "jwt"
+	MethodAPIKey Method = "api_key"
+	// MethodClientCertificate é o do certificado de cliente (mTLS)
+	MethodClientCertificate Method = "client_certificate"
+	// MethodCLI é o do operador no catctl, que já tem acesso ao banco
+	MethodCLI Method = "cli"
+	// MethodNone é o da autenticação desligada (AUTH_DISABLED)
+	MethodNone Method = "none"
+)
+
+// AnyTenant no Tenant libera o principal para escolher qualquer tenant.
+const AnyTenant = "*"
+
+// Principal é o autenticado. Subject é o sub do JWT ou o nome da conta de
+// serviço da API key ou do certificado, e é o ator registrado na auditoria. Tenant, quando
+// preenchido, prende o principal a um único tenant (ou a todos, com
+// AnyTenant); vazio, ao tenant padrão.
+type Principal struct {
+	Subject string
+	Roles   []string
+	Tenant  string
+	Method  Method
+}
+
+func (principal *Principal) HasRole(role string) bool {
+	for _, candidate := range principal.Roles {
+		if candidate == role {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal guarda o principal no context repassado aos use cases.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*Principal)
+	return principal, ok && principal != nil
+}
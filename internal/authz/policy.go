@@ -0,0 +1,229 @@
+// Package authz decide o que cada principal pode fazer. Os use cases chamam
+// Require antes de qualquer leitura ou escrita, então as regras valem para
+// qualquer porta de entrada (HTTP, gRPC, CLI), não só para as rotas.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/auth"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+type Permission string
+
+const (
+	CategoriesRead    Permission = "categories:read"
+	CategoriesCreate  Permission = "categories:create"
+	CategoriesUpdate  Permission = "categories:update"
+	CategoriesDelete  Permission = "categories:delete"
+	CategoriesRestore Permission = "categories:restore"
+	CategoriesHistory Permission = "categories:history"
+	WebhooksRead      Permission = "webhooks:read"
+	WebhooksManage    Permission = "webhooks:manage"
+)
+
+// Permissions são todas as permissões conhecidas; arquivos de política com
+// outras são recusados, para que um erro de digitação não passe calado.
+var Permissions = []Permission{
+	CategoriesRead, CategoriesCreate, CategoriesUpdate, CategoriesDelete,
+	CategoriesRestore, CategoriesHistory, WebhooksRead, WebhooksManage,
+}
+
+var ErrUnauthenticated = errors.New("authentication required")
+
+// ForbiddenError nomeia a permissão que faltou ao principal.
+type ForbiddenError struct {
+	Permission Permission
+}
+
+func (err *ForbiddenError) Error() string {
+	return fmt.Sprintf("missing permission %s", err.Permission)
+}
+
+// Role é a regra declarativa de um papel: as permissões próprias (aceitam
+// "*" e curingas como "categories:*") mais as dos papéis em Inherits.
+type Role struct {
+	Inherits    []string `yaml:"inherits" json:"inherits"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+}
+
+// Rules é o formato do arquivo de política:
+//
+//	roles:
+//	  viewer:
+//	    permissions: [categories:read]
+//	  editor:
+//	    inherits: [viewer]
+//	    permissions: [categories:create, categories:update]
+type Rules struct {
+	Roles map[string]Role `yaml:"roles" json:"roles"`
+}
+
+// DefaultRules: viewer só lê, editor cria e altera, admin pode tudo
+// (inclusive remover, restaurar e gerenciar webhooks).
+var DefaultRules = Rules{
+	Roles: map[string]Role{
+		"viewer": {Permissions: []string{string(CategoriesRead)}},
+		"editor": {
+			Inherits:    []string{"viewer"},
+			Permissions: []string{string(CategoriesCreate), string(CategoriesUpdate), string(CategoriesHistory)},
+		},
+		"admin": {Permissions: []string{"*"}},
+	},
+}
+
+// Policy é o conjunto de permissões já resolvido de cada papel.
+type Policy struct {
+	roles map[string]map[Permission]bool
+}
+
+func NewPolicy(rules Rules) (*Policy, error) {
+	policy := &Policy{roles: make(map[string]map[Permission]bool, len(rules.Roles))}
+
+	for name := range rules.Roles {
+		permissions, err := resolve(rules, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		policy.roles[name] = permissions
+	}
+
+	return policy, nil
+}
+
+// DefaultPolicy é a política de DefaultRules.
+func DefaultPolicy() *Policy {
+	policy, err := NewPolicy(DefaultRules)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// LoadPolicy lê as regras de um arquivo YAML (ou JSON).
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules Rules
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+
+	if len(rules.Roles) == 0 {
+		return nil, fmt.Errorf("policy file %s has no roles", path)
+	}
+
+	return NewPolicy(rules)
+}
+
+func (policy *Policy) Allows(principal *auth.Principal, permission Permission) bool {
+	for _, role := range principal.Roles {
+		if policy.roles[role][permission] {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermissions devolve as permissões resolvidas do papel, ordenadas.
+func (policy *Policy) RolePermissions(role string) []Permission {
+	permissions := make([]Permission, 0, len(policy.roles[role]))
+	for permission := range policy.roles[role] {
+		permissions = append(permissions, permission)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+// resolve expande os curingas e a herança do papel. visiting detecta ciclos.
+func resolve(rules Rules, name string, visiting []string) (map[Permission]bool, error) {
+	if slices.Contains(visiting, name) {
+		return nil, fmt.Errorf("role inheritance cycle: %s -> %s", strings.Join(visiting, " -> "), name)
+	}
+
+	role, ok := rules.Roles[name]
+	if !ok {
+		return nil, fmt.Errorf("role %q is inherited but not defined", name)
+	}
+
+	permissions := make(map[Permission]bool)
+
+	for _, parent := range role.Inherits {
+		inherited, err := resolve(rules, parent, append(visiting, name))
+		if err != nil {
+			return nil, err
+		}
+		for permission := range inherited {
+			permissions[permission] = true
+		}
+	}
+
+	for _, pattern := range role.Permissions {
+		matched := false
+		for _, permission := range Permissions {
+			if matches(pattern, permission) {
+				permissions[permission] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("role %q has unknown permission %q", name, pattern)
+		}
+	}
+
+	return permissions, nil
+}
+
+func matches(pattern string, permission Permission) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(string(permission), prefix)
+	}
+
+	return pattern == string(permission)
+}
+
+type contextKey int
+
+const policyKey contextKey = iota
+
+// WithPolicy troca, no context, a política usada por Require.
+func WithPolicy(ctx context.Context, policy *Policy) context.Context {
+	return context.WithValue(ctx, policyKey, policy)
+}
+
+var defaultPolicy = DefaultPolicy()
+
+// Require verifica se o principal do context tem a permissão, pela política
+// do context (ou DefaultPolicy). Sem principal retorna ErrUnauthenticated;
+// sem a permissão, *ForbiddenError.
+func Require(ctx context.Context, permission Permission) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	policy, ok := ctx.Value(policyKey).(*Policy)
+	if !ok || policy == nil {
+		policy = defaultPolicy
+	}
+
+	if !policy.Allows(principal, permission) {
+		return &ForbiddenError{Permission: permission}
+	}
+
+	return nil
+}
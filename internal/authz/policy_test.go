@@ -0,0 +1,143 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"gin-quickstart/internal/auth"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewPolicy_ResolvesInheritanceAndWildcards(t *testing.T) {
+	// Arrange
+	policy, err := NewPolicy(Rules{Roles: map[string]Role{
+		"viewer":  {Permissions: []string{"categories:read"}},
+		"curator": {Inherits: []string{"viewer"}, Permissions: []string{"categories:*"}},
+		"ops":     {Inherits: []string{"curator"}, Permissions: []string{"webhooks:read"}},
+	}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	testCases := []struct {
+		role       string
+		permission Permission
+		allowed    bool
+	}{
+		{"viewer", CategoriesRead, true},
+		{"viewer", CategoriesCreate, false},
+		{"curator", CategoriesDelete, true},
+		{"curator", WebhooksRead, false},
+		{"ops", CategoriesRestore, true},
+		{"ops", WebhooksRead, true},
+		{"ops", WebhooksManage, false},
+		{"undefined", CategoriesRead, false},
+	}
+
+	for _, testCase := range testCases {
+		principal := &auth.Principal{Subject: "maria", Roles: []string{testCase.role}}
+		if allowed := policy.Allows(principal, testCase.permission); allowed != testCase.allowed {
+			t.Errorf("Expected %s allowed=%v for %s, got %v", testCase.permission, testCase.allowed, testCase.role, allowed)
+		}
+	}
+}
+
+func TestNewPolicy_RejectsInvalidRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		roles map[string]Role
+		want  string
+	}{
+		{"cycle", map[string]Role{
+			"a": {Inherits: []string{"b"}},
+			"b": {Inherits: []string{"a"}},
+		}, "cycle"},
+		{"undefined parent", map[string]Role{
+			"a": {Inherits: []string{"ghost"}},
+		}, "not defined"},
+		{"unknown permission", map[string]Role{
+			"a": {Permissions: []string{"categories:purge"}},
+		}, "unknown permission"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			_, err := NewPolicy(Rules{Roles: testCase.roles})
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), testCase.want) {
+				t.Fatalf("Expected an error containing %q, got %v", testCase.want, err)
+			}
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	if got := policy.RolePermissions("viewer"); !slices.Equal(got, []Permission{CategoriesRead}) {
+		t.Errorf("Expected viewer to only read, got %v", got)
+	}
+
+	editor := policy.RolePermissions("editor")
+	if !slices.Contains(editor, CategoriesRead) || !slices.Contains(editor, CategoriesUpdate) || slices.Contains(editor, CategoriesDelete) {
+		t.Errorf("Expected editor to read and update but not delete, got %v", editor)
+	}
+
+	if got := policy.RolePermissions("admin"); len(got) != len(Permissions) {
+		t.Errorf("Expected admin to have every permission, got %v", got)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	os.WriteFile(path, []byte("roles:\n  auditor:\n    permissions: [categories:read, categories:history]\n"), 0o600)
+
+	// Act
+	policy, err := LoadPolicy(path)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := policy.RolePermissions("auditor"); !slices.Equal(got, []Permission{CategoriesHistory, CategoriesRead}) {
+		t.Errorf("Expected auditor permissions, got %v", got)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	os.WriteFile(empty, []byte("roles: {}\n"), 0o600)
+	if _, err := LoadPolicy(empty); err == nil {
+		t.Error("Expected an error for a policy without roles")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	viewer := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "maria", Roles: []string{"viewer"}})
+
+	if err := Require(context.Background(), CategoriesRead); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated without a principal, got %v", err)
+	}
+
+	if err := Require(viewer, CategoriesRead); err != nil {
+		t.Errorf("Expected the default policy to let a viewer read, got %v", err)
+	}
+
+	var forbiddenError *ForbiddenError
+	if err := Require(viewer, CategoriesDelete); !errors.As(err, &forbiddenError) || forbiddenError.Permission != CategoriesDelete {
+		t.Errorf("Expected ForbiddenError for categories:delete, got %v", err)
+	}
+
+	// A política do context substitui a padrão
+	policy, _ := NewPolicy(Rules{Roles: map[string]Role{"viewer": {Permissions: []string{"categories:*"}}}})
+	if err := Require(WithPolicy(viewer, policy), CategoriesDelete); err != nil {
+		t.Errorf("Expected the context policy to allow the delete, got %v", err)
+	}
+}
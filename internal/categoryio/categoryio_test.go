@@ -0,0 +1,133 @@
+package categoryio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/entities"
+)
+
+func readAll(t *testing.T, reader Reader) ([]Row, []*RowError) {
+	t.Helper()
+
+	var rows []Row
+	var rowErrors []*RowError
+
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return rows, rowErrors
+		}
+
+		var rowError *RowError
+		if errors.As(err, &rowError) {
+			rowErrors = append(rowErrors, rowError)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader_ReadsNameColumnWithLineNumbers(t *testing.T) {
+	// Arrange
+	input := "id,name\n1,Electronics\n2,\"Books, Comics\"\n3,\"broken\"quote\n4,Home & Garden\n"
+	reader, err := NewReader(CSV, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Error creating reader: %v", err)
+	}
+
+	// Act
+	rows, rowErrors := readAll(t, reader)
+
+	// Assert
+	expected := []Row{{2, "Electronics"}, {3, "Books, Comics"}, {5, "Home & Garden"}}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d: %v", len(expected), len(rows), rows)
+	}
+	for i := range expected {
+		if rows[i] != expected[i] {
+			t.Errorf("Expected row %v, got %v", expected[i], rows[i])
+		}
+	}
+
+	if len(rowErrors) != 1 || rowErrors[0].Line != 4 {
+		t.Errorf("Expected a single error on line 4, got %v", rowErrors)
+	}
+}
+
+func TestCSVReader_RequiresNameHeader(t *testing.T) {
+	_, err := NewReader(CSV, strings.NewReader("id,title\n1,Electronics\n"))
+
+	var rowError *RowError
+	if !errors.As(err, &rowError) || rowError.Line != 1 {
+		t.Fatalf("Expected header error on line 1, got %v", err)
+	}
+}
+
+func TestJSONLReader_ReportsMalformedLines(t *testing.T) {
+	// Arrange
+	input := "{\"name\":\"Electronics\"}\n\n{\"name\":\n{\"title\":\"Books\"}\n{\"name\":\"Home & Garden\"}\n"
+	reader, _ := NewReader(JSONL, strings.NewReader(input))
+
+	// Act
+	rows, rowErrors := readAll(t, reader)
+
+	// Assert
+	if len(rows) != 2 || rows[0] != (Row{1, "Electronics"}) || rows[1] != (Row{5, "Home & Garden"}) {
+		t.Errorf("Unexpected rows %v", rows)
+	}
+
+	if len(rowErrors) != 2 || rowErrors[0].Line != 3 || rowErrors[1].Line != 4 {
+		t.Errorf("Expected errors on lines 3 and 4, got %v", rowErrors)
+	}
+}
+
+// O que é exportado precisa poder ser importado de volta
+func TestWriters_RoundTrip(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	categories := []*entities.Category{
+		{ID: 1, Name: "Electronics", CreatedAt: now, UpdatedAt: now, Version: 1},
+		{ID: 2, Name: "Books, \"Comics\"", CreatedAt: now, UpdatedAt: now, Version: 3},
+	}
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			writer, err := NewWriter(format, &buffer)
+			if err != nil {
+				t.Fatalf("Error creating writer: %v", err)
+			}
+			for _, category := range categories {
+				if err := writer.Write(category); err != nil {
+					t.Fatalf("Error writing: %v", err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("Error flushing: %v", err)
+			}
+
+			reader, err := NewReader(format, &buffer)
+			if err != nil {
+				t.Fatalf("Error creating reader: %v", err)
+			}
+
+			rows, rowErrors := readAll(t, reader)
+			if len(rowErrors) != 0 {
+				t.Fatalf("Unexpected row errors %v", rowErrors)
+			}
+
+			if len(rows) != 2 || rows[0].Name != categories[0].Name || rows[1].Name != categories[1].Name {
+				t.Errorf("Unexpected rows %v", rows)
+			}
+		})
+	}
+}
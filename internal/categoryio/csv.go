@@ -0,0 +1,103 @@
+package categoryio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/entities"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"id", "name", "created_at", "updated_at", "version"}
+
+type csvReader struct {
+	reader    *csv.Reader
+	nameIndex int
+}
+
+// newCSVReader exige uma linha de cabeçalho com a coluna "name"; as demais
+// colunas (ex: as de um arquivo exportado) são ignoradas.
+func newCSVReader(reader io.Reader) (*csvReader, error) {
+	csvReader := &csvReader{reader: csv.NewReader(reader)}
+	csvReader.reader.FieldsPerRecord = -1
+	csvReader.reader.TrimLeadingSpace = true
+
+	header, err := csvReader.reader.Read()
+	if err == io.EOF {
+		return nil, &RowError{Line: 1, Err: errors.New("missing header row")}
+	}
+	if err != nil {
+		return nil, &RowError{Line: 1, Err: err}
+	}
+
+	csvReader.nameIndex = -1
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")), "name") {
+			csvReader.nameIndex = i
+		}
+	}
+
+	if csvReader.nameIndex == -1 {
+		return nil, &RowError{Line: 1, Err: errors.New(`header must have a "name" column`)}
+	}
+
+	return csvReader, nil
+}
+
+func (reader *csvReader) Next() (Row, error) {
+	fields, err := reader.reader.Read()
+
+	if err == io.EOF {
+		return Row{}, io.EOF
+	}
+
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return Row{}, &RowError{Line: parseError.StartLine, Err: parseError.Err}
+	}
+	if err != nil {
+		return Row{}, err
+	}
+
+	line, _ := reader.reader.FieldPos(0)
+
+	if reader.nameIndex >= len(fields) {
+		return Row{}, &RowError{Line: line, Err: fmt.Errorf("expected %d columns, got %d", reader.nameIndex+1, len(fields))}
+	}
+
+	return Row{Line: line, Name: fields[reader.nameIndex]}, nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(writer io.Writer) (*csvWriter, error) {
+	csvWriter := &csvWriter{writer: csv.NewWriter(writer)}
+
+	if err := csvWriter.writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	return csvWriter, nil
+}
+
+func (writer *csvWriter) Write(category *entities.Category) error {
+	record := newRecord(category)
+
+	return writer.writer.Write([]string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.Name,
+		record.CreatedAt.Format(time.RFC3339Nano),
+		record.UpdatedAt.Format(time.RFC3339Nano),
+		strconv.FormatUint(uint64(record.Version), 10),
+	})
+}
+
+func (writer *csvWriter) Flush() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
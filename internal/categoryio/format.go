@@ -0,0 +1,119 @@
+// Package categoryio lê e escreve categorias em CSV e JSONL, uma linha por
+// vez, para importações e exportações que não cabem em memória.
+package categoryio
+
+import (
+	"fmt"
+	"gin-quickstart/internal/entities"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+var Formats = []Format{CSV, JSONL}
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case CSV:
+		return CSV, nil
+	case JSONL, "ndjson":
+		return JSONL, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, use csv or jsonl", value)
+	}
+}
+
+// FormatFromFilename deduz o formato pela extensão do arquivo.
+func FormatFromFilename(filename string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+func (format Format) ContentType() string {
+	if format == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Row é uma linha lida de um arquivo de importação. Line é o número da linha
+// no arquivo (1 = primeira linha), usado no relatório de erros.
+type Row struct {
+	Line int
+	Name string
+}
+
+// RowError é um erro de leitura de uma linha específica. A leitura pode
+// continuar nas linhas seguintes.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (err *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+}
+
+func (err *RowError) Unwrap() error {
+	return err.Err
+}
+
+// Reader devolve uma linha por chamada e io.EOF no fim do arquivo. Linhas
+// malformadas retornam *RowError.
+type Reader interface {
+	Next() (Row, error)
+}
+
+// Writer escreve uma categoria por chamada; Flush deve ser chamado no fim.
+type Writer interface {
+	Write(category *entities.Category) error
+	Flush() error
+}
+
+func NewReader(format Format, reader io.Reader) (Reader, error) {
+	switch format {
+	case CSV:
+		return newCSVReader(reader)
+	case JSONL:
+		return newJSONLReader(reader), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func NewWriter(format Format, writer io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(writer)
+	case JSONL:
+		return newJSONLWriter(writer), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// record é o formato de cada linha exportada, igual ao da API.
+type record struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint      `json:"version"`
+}
+
+func newRecord(category *entities.Category) record {
+	return record{
+		ID:        category.ID,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+		Version:   category.Version,
+	}
+}
@@ -0,0 +1,79 @@
+package categoryio
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"gin-quickstart/internal/entities"
+	"io"
+	"strings"
+)
+
+// Linhas JSONL maiores que isso são rejeitadas
+const maxJSONLLineSize = 1 << 20
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLReader(reader io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	return &jsonlReader{scanner: scanner}
+}
+
+func (reader *jsonlReader) Next() (Row, error) {
+	for reader.scanner.Scan() {
+		reader.line++
+
+		text := strings.TrimSpace(reader.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var input struct {
+			Name *string `json:"name"`
+		}
+
+		if err := json.Unmarshal([]byte(text), &input); err != nil {
+			return Row{}, &RowError{Line: reader.line, Err: err}
+		}
+
+		if input.Name == nil {
+			return Row{}, &RowError{Line: reader.line, Err: errors.New(`missing "name" field`)}
+		}
+
+		return Row{Line: reader.line, Name: *input.Name}, nil
+	}
+
+	if err := reader.scanner.Err(); err != nil {
+		// Depois de um erro o scanner não avança mais, então a leitura termina aqui
+		return Row{}, err
+	}
+
+	return Row{}, io.EOF
+}
+
+type jsonlWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(writer io.Writer) *jsonlWriter {
+	buffered := bufio.NewWriter(writer)
+
+	return &jsonlWriter{
+		writer:  buffered,
+		encoder: json.NewEncoder(buffered),
+	}
+}
+
+func (writer *jsonlWriter) Write(category *entities.Category) error {
+	return writer.encoder.Encode(newRecord(category))
+}
+
+func (writer *jsonlWriter) Flush() error {
+	return writer.writer.Flush()
+}
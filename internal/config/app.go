@@ -0,0 +1,151 @@
+package config
+
+import (
+	"gin-quickstart/internal/outbox"
+	"log"
+	"strconv"
+	"time"
+)
+
+// RepositoryDriver escolhe onde os dados são guardados: "memory" ou "postgres".
+func RepositoryDriver() string {
+	return getEnv("REPOSITORY_DRIVER", "memory")
+}
+
+// IdempotencyTTL é por quanto tempo uma Idempotency-Key e a resposta
+// original ficam guardadas.
+func IdempotencyTTL() time.Duration {
+	return getDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+}
+
+// IdempotencyLease é por quanto tempo uma requisição com Idempotency-Key
+// segura a chave: depois disso, se ela não tiver terminado, um reenvio pode
+// assumi-la. Deve passar da requisição mais lenta.
+func IdempotencyLease() time.Duration {
+	return getDuration("IDEMPOTENCY_LEASE", time.Minute)
+}
+
+// OutboxPollInterval é de quanto em quanto tempo o relay procura eventos
+// pendentes na outbox.
+func OutboxPollInterval() time.Duration {
+	return getDuration("OUTBOX_POLL_INTERVAL", 500*time.Millisecond)
+}
+
+// OutboxRetention é por quanto tempo as mensagens entregues ficam na
+// outbox. Com o Postgres as outras réplicas as leem logo depois do commit,
+// pelo NOTIFY.
+func OutboxRetention() time.Duration {
+	return getDuration("OUTBOX_RETENTION", outbox.DefaultRetention)
+}
+
+// WebhookPollInterval é de quanto em quanto tempo o dispatcher de webhooks
+// procura entregas vencidas.
+func WebhookPollInterval() time.Duration {
+	return getDuration("WEBHOOK_POLL_INTERVAL", time.Second)
+}
+
+// WebhookAllowPrivateTargets libera webhooks para loopback, link-local e
+// redes privadas. Só para desenvolvimento: em produção permitiria que um
+// tenant alcançasse a rede interna.
+func WebhookAllowPrivateTargets() bool {
+	return getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false") == "true"
+}
+
+// StreamHeartbeatInterval é de quanto em quanto tempo o stream SSE manda um
+// comentário para manter a conexão viva em proxies com timeout de inatividade.
+func StreamHeartbeatInterval() time.Duration {
+	return getDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second)
+}
+
+// TenantHeader é o header com o tenant da requisição.
+func TenantHeader() string {
+	return getEnv("TENANT_HEADER", "X-Tenant-ID")
+}
+
+// TenantBaseDomain habilita o tenant pelo subdomínio: com "example.com",
+// loja1.example.com é o tenant loja1. Vazio desliga.
+func TenantBaseDomain() string {
+	return getEnv("TENANT_BASE_DOMAIN", "")
+}
+
+// GRPCAddress é onde a API gRPC escuta, ao lado da HTTP (:8000).
+func GRPCAddress() string {
+	return getEnv("GRPC_ADDR", ":9090")
+}
+
+// RateLimitsFile é o arquivo YAML com os limites de requisições por
+// operação. Vazio usa os limites padrão.
+func RateLimitsFile() string {
+	return getEnv("RATE_LIMITS_FILE", "")
+}
+
+// RateLimitPruneInterval é de quanto em quanto tempo os buckets de rate
+// limit ociosos são descartados.
+func RateLimitPruneInterval() time.Duration {
+	return getDuration("RATE_LIMIT_PRUNE_INTERVAL", time.Minute)
+}
+
+// GraphQLMaxDepth e GraphQLMaxComplexity recusam consultas GraphQL muito
+// aninhadas ou caras antes de executá-las; 0 desliga o limite.
+func GraphQLMaxDepth() int {
+	return getInt("GRAPHQL_MAX_DEPTH", 6)
+}
+
+func GraphQLMaxComplexity() int {
+	return getInt("GRAPHQL_MAX_COMPLEXITY", 1000)
+}
+
+// CategoryCacheSize é quantas leituras de categorias (listas por tenant e
+// categorias por ID) ficam em cache, cada uma por até CategoryCacheTTL;
+// 0 desliga o cache. Com várias réplicas o TTL é o atraso máximo para uma
+// réplica ver a escrita feita por outra.
+func CategoryCacheSize() int {
+	return getInt("CATEGORY_CACHE_SIZE", 1000)
+}
+
+func CategoryCacheTTL() time.Duration {
+	return getDuration("CATEGORY_CACHE_TTL", 30*time.Second)
+}
+
+// CategoryListenerRetryInterval é quanto o CategoryListener espera para
+// reconectar ao Postgres depois de perder a conexão do LISTEN.
+func CategoryListenerRetryInterval() time.Duration {
+	return getDuration("CATEGORY_LISTENER_RETRY_INTERVAL", 5*time.Second)
+}
+
+// DebugVarsEnabled expõe os contadores do processo (inclusive os do cache)
+// em GET /debug/vars, no formato do expvar.
+func DebugVarsEnabled() bool {
+	return getEnv("DEBUG_VARS_ENABLED", "false") == "true"
+}
+
+func getInt(key string, defaultValue int) int {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid integer %s=%q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return number
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid duration %s=%q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
+
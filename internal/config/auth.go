@@ -0,0 +1,52 @@
+package config
+
+// AuthDisabled desliga a autenticação: toda requisição vira o principal
+// anônimo com acesso total. Só para desenvolvimento local.
+func AuthDisabled() bool {
+	return getEnv("AUTH_DISABLED", "false") == "true"
+}
+
+// JWTSecret habilita tokens HS256 assinados com este segredo.
+func JWTSecret() string {
+	return getEnv("AUTH_JWT_SECRET", "")
+}
+
+// JWTPublicKeyFile habilita tokens RS256 verificados com a chave pública
+// RSA (PEM) deste arquivo.
+func JWTPublicKeyFile() string {
+	return getEnv("AUTH_JWT_PUBLIC_KEY_FILE", "")
+}
+
+// JWKSFile habilita tokens RS256 verificados com as chaves de um arquivo
+// JWKS local, escolhidas pelo kid do token.
+func JWKSFile() string {
+	return getEnv("AUTH_JWKS_FILE", "")
+}
+
+// JWTIssuer e JWTAudience, quando definidos, são exigidos nas claims iss e
+// aud dos tokens.
+func JWTIssuer() string {
+	return getEnv("AUTH_JWT_ISSUER", "")
+}
+
+func JWTAudience() string {
+	return getEnv("AUTH_JWT_AUDIENCE", "")
+}
+
+// APIKeysFile é o arquivo JSON com as API keys (hash SHA-256) das contas
+// de serviço.
+func APIKeysFile() string {
+	return getEnv("AUTH_API_KEYS_FILE", "")
+}
+
+// ClientCertificatesFile é o arquivo JSON que mapeia os subjects dos
+// certificados de cliente (mTLS) para contas de serviço.
+func ClientCertificatesFile() string {
+	return getEnv("AUTH_CLIENT_CERTIFICATES_FILE", "")
+}
+
+// AuthzPolicyFile é o arquivo YAML com os papéis e permissões. Vazio usa a
+// política padrão (viewer, editor e admin).
+func AuthzPolicyFile() string {
+	return getEnv("AUTHZ_POLICY_FILE", "")
+}
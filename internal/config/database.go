@@ -2,6 +2,14 @@ package config
 
 import (
 	"fmt"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/idempotency"
+	"gin-quickstart/internal/outbox"
+	"gin-quickstart/internal/ratelimit"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/webhooks"
+	"log"
 	"os"
 
 	"gorm.io/driver/postgres"
@@ -17,7 +25,8 @@ func NewConfig() *Config {
 	return &Config{}
 }
 
-func (c *Config) ConnectDB() error {
+// DatabaseDSN é a string de conexão montada a partir das variáveis DB_*.
+func DatabaseDSN() string {
 	// Configurações do banco via variáveis de ambiente
 	host := getEnv("DB_HOST", "localhost")
 	port := getEnv("DB_PORT", "5432")
@@ -28,15 +37,22 @@ func (c *Config) ConnectDB() error {
 	timezone := getEnv("DB_TIMEZONE", "UTC")
 
 	// String de conexão PostgreSQL
-	dsn := fmt.Sprintf(
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
 		host, user, password, dbname, port, sslmode, timezone,
 	)
+}
+
+func (c *Config) ConnectDB() error {
+	dsn := DatabaseDSN()
 
 	// Configuração do GORM
 	var err error
 	c.DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Violações de índice único viram gorm.ErrDuplicatedKey (nomes de
+		// categoria duplicados no mesmo tenant)
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -56,10 +72,18 @@ func (c *Config) ConnectDB() error {
 	return nil
 }
 
+// MigrateDB renomeia as categorias com nomes repetidos antes de criar o
+// índice único de nome (veja repositories.DeduplicateCategoryNames).
 func (c *Config) MigrateDB() error {
-	// Aqui você adicionará suas entidades para migração
-	// Exemplo: return c.DB.AutoMigrate(&entities.Category{})
-	return nil
+	renamed, err := repositories.DeduplicateCategoryNames(c.DB)
+	if err != nil {
+		return fmt.Errorf("failed to deduplicate category names: %w", err)
+	}
+	if renamed > 0 {
+		log.Printf("renamed %d categories with duplicated names to \"name (id)\"", renamed)
+	}
+
+	return c.DB.AutoMigrate(&entities.Category{}, &idempotency.IdempotencyKey{}, &audit.CategoryAudit{}, &outbox.Message{}, &webhooks.Subscription{}, &webhooks.Delivery{}, &ratelimit.Bucket{})
 }
 
 func getEnv(key, defaultValue string) string {
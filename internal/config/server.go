@@ -0,0 +1,70 @@
+package config
+
+import (
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+// HTTPAddress é onde a API HTTP escuta; com HTTP/3, a mesma porta em UDP.
+func HTTPAddress() string {
+	return getEnv("HTTP_ADDR", ":8000")
+}
+
+// TrustedProxies são os IPs ou CIDRs (separados por vírgula) dos proxies
+// reversos na frente da API. Só vindo deles o X-Forwarded-For vale como IP
+// do cliente; sem nenhum (o padrão) vale o endereço da conexão. Entradas
+// inválidas são ignoradas.
+func TrustedProxies() []string {
+	var proxies []string
+
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			log.Printf("invalid trusted proxy %q, ignoring it", proxy)
+			continue
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies
+}
+
+// TLSCertFile e TLSKeyFile são o certificado e a chave (PEM) da API HTTP,
+// relidos quando mudam. Sem eles a API é servida em HTTP puro.
+func TLSCertFile() string {
+	return getEnv("TLS_CERT_FILE", "")
+}
+
+func TLSKeyFile() string {
+	return getEnv("TLS_KEY_FILE", "")
+}
+
+// TLSClientAuth é o modo do mTLS: none, optional (autentica quem mandar
+// certificado) ou require. Os certificados são verificados contra as CAs
+// de TLSClientCAFile.
+func TLSClientAuth() string {
+	return getEnv("TLS_CLIENT_AUTH", "none")
+}
+
+func TLSClientCAFile() string {
+	return getEnv("TLS_CLIENT_CA_FILE", "")
+}
+
+// TLSReloadInterval é de quanto em quanto tempo os arquivos do TLS são
+// conferidos e relidos se mudaram.
+func TLSReloadInterval() time.Duration {
+	return getDuration("TLS_RELOAD_INTERVAL", 10*time.Second)
+}
+
+// HTTP3Enabled serve a API também em HTTP/3 (QUIC), anunciado pelo header
+// Alt-Svc das respostas HTTP/1.1 e HTTP/2. Exige TLS.
+func HTTP3Enabled() bool {
+	return getEnv("HTTP3_ENABLED", "false") == "true"
+}
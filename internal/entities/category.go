@@ -7,9 +7,24 @@ import (
 
 type Category struct {
 	ID uint `json:"id"`
-	Name string `json:"name"`
+	// TenantID é o catálogo dono da categoria, preenchido pelo repositório.
+	// O nome é único por tenant entre as categorias não removidas
+	TenantID string `json:"tenant_id" gorm:"not null;default:default;uniqueIndex:idx_categories_tenant_name,where:deleted_at IS NULL"`
+	Name string `json:"name" gorm:"uniqueIndex:idx_categories_tenant_name,where:deleted_at IS NULL"`
+	// ParentID é a categoria pai; nil nas categorias da raiz
+	ParentID *uint `json:"parent_id,omitempty" gorm:"index"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version é incrementada a cada escrita e usada no controle de concorrência otimista
+	Version uint `json:"version"`
+	// DeletedAt marca a categoria como removida; enquanto não for nil ela
+	// some das consultas, mas ainda pode ser restaurada
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Tenant implementa tenancy.Owned.
+func (c *Category) Tenant() string {
+	return c.TenantID
 }
 
 func NewCategory(name string) (*Category, error) {
@@ -30,8 +45,44 @@ func NewCategory(name string) (*Category, error) {
 
 func (c *Category) IsValid() error {
 	if(len(c.Name) < 5) {
-		return fmt.Errorf("name must be greater than 5, got %d", len(c.Name))
+		return &ValidationError{
+			Field: "name",
+			Message: fmt.Sprintf("name must be greater than 5, got %d", len(c.Name)),
+		}
+	}
+
+	return nil
+}
+
+func (c *Category) Rename(name string) error {
+	renamed := *c
+	renamed.Name = name
+
+	err := renamed.IsValid()
+
+	if err != nil {
+		return err
 	}
 
+	c.Name = name
+	c.UpdatedAt = time.Now()
+
 	return nil
-}
\ No newline at end of file
+}
+
+// MoveTo troca a categoria pai; nil leva a categoria para a raiz. A
+// existência do pai e os ciclos mais longos ficam com o use case, que
+// enxerga as outras categorias.
+func (c *Category) MoveTo(parentID *uint) error {
+	if parentID != nil && *parentID == c.ID {
+		return &ValidationError{
+			Field: "parent_id",
+			Message: "a category cannot be its own parent",
+		}
+	}
+
+	c.ParentID = parentID
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package entities
+
+// ValidationError indica que a entidade violou uma regra de negócio.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (err *ValidationError) Error() string {
+	return err.Message
+}
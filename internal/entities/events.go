@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"gin-quickstart/internal/events"
+	"time"
+)
+
+// Eventos de domínio da categoria. São emitidos pelos use cases depois que
+// a alteração foi gravada; EventName é o nome usado para assinar no bus.
+
+// EventTenant leva o tenant da categoria em todos os eventos, para que o
+// stream e os webhooks só entreguem a quem é do mesmo tenant.
+type EventTenant struct {
+	TenantID string `json:"tenant_id"`
+}
+
+func (tenant EventTenant) Tenant() string { return tenant.TenantID }
+
+type CategoryCreated struct {
+	EventTenant
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Version    uint      `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (CategoryCreated) EventName() string { return "category.created" }
+
+type CategoryRenamed struct {
+	EventTenant
+	ID         uint      `json:"id"`
+	OldName    string    `json:"old_name"`
+	NewName    string    `json:"new_name"`
+	Version    uint      `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (CategoryRenamed) EventName() string { return "category.renamed" }
+
+type CategoryDeleted struct {
+	EventTenant
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Version    uint      `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (CategoryDeleted) EventName() string { return "category.deleted" }
+
+type CategoryRestored struct {
+	EventTenant
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Version    uint      `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (CategoryRestored) EventName() string { return "category.restored" }
+
+// CategoryMoved leva os pais antigo e novo; nil é a raiz.
+type CategoryMoved struct {
+	EventTenant
+	ID          uint      `json:"id"`
+	OldParentID *uint     `json:"old_parent_id"`
+	NewParentID *uint     `json:"new_parent_id"`
+	Version     uint      `json:"version"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+func (CategoryMoved) EventName() string { return "category.moved" }
+
+// CategoryEvents são os protótipos para o events.Registry.
+var CategoryEvents = []events.Event{CategoryCreated{}, CategoryRenamed{}, CategoryMoved{}, CategoryDeleted{}, CategoryRestored{}}
+
+func NewCategoryCreated(category *Category) CategoryCreated {
+	return CategoryCreated{EventTenant: EventTenant{category.TenantID}, ID: category.ID, Name: category.Name, Version: category.Version, OccurredAt: time.Now()}
+}
+
+func NewCategoryRenamed(oldName string, category *Category) CategoryRenamed {
+	return CategoryRenamed{EventTenant: EventTenant{category.TenantID}, ID: category.ID, OldName: oldName, NewName: category.Name, Version: category.Version, OccurredAt: time.Now()}
+}
+
+func NewCategoryMoved(oldParentID *uint, category *Category) CategoryMoved {
+	return CategoryMoved{EventTenant: EventTenant{category.TenantID}, ID: category.ID, OldParentID: oldParentID, NewParentID: category.ParentID, Version: category.Version, OccurredAt: time.Now()}
+}
+
+// NewCategoryDeleted recebe a categoria como estava antes da remoção.
+func NewCategoryDeleted(category *Category) CategoryDeleted {
+	return CategoryDeleted{EventTenant: EventTenant{category.TenantID}, ID: category.ID, Name: category.Name, Version: category.Version + 1, OccurredAt: time.Now()}
+}
+
+func NewCategoryRestored(category *Category) CategoryRestored {
+	return CategoryRestored{EventTenant: EventTenant{category.TenantID}, ID: category.ID, Name: category.Name, Version: category.Version, OccurredAt: time.Now()}
+}
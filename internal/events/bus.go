@@ -0,0 +1,237 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Event é qualquer evento de domínio (ex: entities.CategoryCreated).
+type Event interface {
+	EventName() string
+}
+
+// Handler reage a um evento. Um erro (ou panic) de um handler não afeta os
+// demais nem quem publicou: ele só é repassado ao ErrorHandler do bus.
+type Handler func(ctx context.Context, event Event) error
+
+type ErrorHandler func(event Event, err error)
+
+// IPublisher publica eventos sem esperar resposta dos assinantes.
+type IPublisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+// IDispatcher entrega um evento e informa se algum assinante falhou, para
+// que quem entrega (ex: o relay da outbox) possa tentar de novo.
+type IDispatcher interface {
+	Dispatch(ctx context.Context, event Event) error
+}
+
+// AllEvents assina todos os eventos, independente do nome.
+const AllEvents = "*"
+
+var ErrBusClosed = errors.New("event bus is closed")
+
+type SubscribeOption func(subscription *subscription)
+
+// Async entrega os eventos numa goroutine própria do assinante, na ordem em
+// que foram publicados. Com a fila (queueSize) cheia o Publish espera.
+func Async(queueSize int) SubscribeOption {
+	return func(subscription *subscription) {
+		subscription.queue = make(chan delivery, queueSize)
+	}
+}
+
+type delivery struct {
+	ctx   context.Context
+	event Event
+}
+
+type subscription struct {
+	eventName string
+	handler   Handler
+	queue     chan delivery
+	mutex     sync.RWMutex
+	closed    bool
+}
+
+// Bus é um publish/subscribe em memória. Por padrão os handlers rodam
+// dentro do Publish (síncronos); com Async cada assinante tem sua fila.
+type Bus struct {
+	mutex         sync.RWMutex
+	subscriptions []*subscription
+	onError       ErrorHandler
+	workers       sync.WaitGroup
+	closed        bool
+}
+
+// NewBus cria o bus. Sem onError os erros dos handlers são só logados.
+func NewBus(onError ErrorHandler) *Bus {
+	if onError == nil {
+		onError = func(event Event, err error) {
+			log.Printf("event handler for %s failed: %v", event.EventName(), err)
+		}
+	}
+
+	return &Bus{
+		onError: onError,
+	}
+}
+
+// Subscribe registra o handler para eventName (ou AllEvents) e devolve a
+// função que cancela a assinatura.
+func (bus *Bus) Subscribe(eventName string, handler Handler, options ...SubscribeOption) (unsubscribe func()) {
+	subscription := &subscription{eventName: eventName, handler: handler}
+	for _, option := range options {
+		option(subscription)
+	}
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	if bus.closed {
+		return func() {}
+	}
+
+	bus.subscriptions = append(bus.subscriptions, subscription)
+
+	if subscription.queue != nil {
+		bus.workers.Add(1)
+		go bus.work(subscription)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { bus.unsubscribe(subscription) })
+	}
+}
+
+// Publish entrega os eventos a todos os assinantes. Handlers assíncronos
+// recebem um context que não é cancelado junto com o da requisição.
+func (bus *Bus) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		if err := bus.deliver(ctx, event, bus.onError); err != nil {
+			bus.onError(event, err)
+		}
+	}
+}
+
+// Dispatch é o Publish de um evento que devolve os erros dos assinantes
+// síncronos em vez de passá-los ao ErrorHandler. Os assinantes continuam
+// isolados: todos recebem o evento mesmo que algum falhe.
+func (bus *Bus) Dispatch(ctx context.Context, event Event) error {
+	var errs []error
+
+	err := bus.deliver(ctx, event, func(event Event, err error) {
+		errs = append(errs, err)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+func (bus *Bus) deliver(ctx context.Context, event Event, onError ErrorHandler) error {
+	bus.mutex.RLock()
+	subscriptions := make([]*subscription, len(bus.subscriptions))
+	copy(subscriptions, bus.subscriptions)
+	closed := bus.closed
+	bus.mutex.RUnlock()
+
+	if closed {
+		return ErrBusClosed
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.eventName != AllEvents && subscription.eventName != event.EventName() {
+			continue
+		}
+
+		if subscription.queue == nil {
+			bus.dispatch(ctx, subscription, event, onError)
+			continue
+		}
+
+		subscription.enqueue(delivery{ctx: context.WithoutCancel(ctx), event: event})
+	}
+
+	return nil
+}
+
+// Close para de aceitar eventos e espera os handlers assíncronos
+// esvaziarem suas filas.
+func (bus *Bus) Close() {
+	bus.mutex.Lock()
+	subscriptions := bus.subscriptions
+	bus.subscriptions = nil
+	bus.closed = true
+	bus.mutex.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.close()
+	}
+
+	bus.workers.Wait()
+}
+
+func (bus *Bus) unsubscribe(target *subscription) {
+	bus.mutex.Lock()
+	for i, subscription := range bus.subscriptions {
+		if subscription == target {
+			bus.subscriptions = append(bus.subscriptions[:i:i], bus.subscriptions[i+1:]...)
+			break
+		}
+	}
+	bus.mutex.Unlock()
+
+	target.close()
+}
+
+func (bus *Bus) work(subscription *subscription) {
+	defer bus.workers.Done()
+
+	for delivery := range subscription.queue {
+		bus.dispatch(delivery.ctx, subscription, delivery.event, bus.onError)
+	}
+}
+
+// dispatch isola o handler: erro ou panic viram chamada ao onError.
+func (bus *Bus) dispatch(ctx context.Context, subscription *subscription, event Event, onError ErrorHandler) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			onError(event, fmt.Errorf("panic: %v", recovered))
+		}
+	}()
+
+	if err := subscription.handler(ctx, event); err != nil {
+		onError(event, err)
+	}
+}
+
+func (subscription *subscription) enqueue(delivery delivery) {
+	subscription.mutex.RLock()
+	defer subscription.mutex.RUnlock()
+
+	if !subscription.closed {
+		subscription.queue <- delivery
+	}
+}
+
+func (subscription *subscription) close() {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	if subscription.closed {
+		return
+	}
+
+	subscription.closed = true
+	if subscription.queue != nil {
+		close(subscription.queue)
+	}
+}
@@ -0,0 +1,200 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type testEvent struct {
+	name  string
+	value int
+}
+
+func (event testEvent) EventName() string { return event.name }
+
+type errorCollector struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (collector *errorCollector) handle(event Event, err error) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.errors = append(collector.errors, err)
+}
+
+func (collector *errorCollector) count() int {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	return len(collector.errors)
+}
+
+func TestBus_SyncDeliversOnlyMatchingEvents(t *testing.T) {
+	// Arrange
+	bus := NewBus(nil)
+	var created, all []string
+
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		created = append(created, event.EventName())
+		return nil
+	})
+	bus.Subscribe(AllEvents, func(ctx context.Context, event Event) error {
+		all = append(all, event.EventName())
+		return nil
+	})
+
+	// Act
+	bus.Publish(context.Background(), testEvent{name: "created"}, testEvent{name: "deleted"})
+
+	// Assert
+	if len(created) != 1 {
+		t.Errorf("Expected 1 created event, got %v", created)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 events for AllEvents, got %v", all)
+	}
+}
+
+func TestBus_IsolatesFailingSubscribers(t *testing.T) {
+	// Arrange
+	collector := &errorCollector{}
+	bus := NewBus(collector.handle)
+	delivered := 0
+
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		return errors.New("subscriber error")
+	})
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		panic("subscriber panic")
+	})
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		delivered++
+		return nil
+	})
+
+	// Act
+	bus.Publish(context.Background(), testEvent{name: "created"})
+
+	// Assert
+	if delivered != 1 {
+		t.Errorf("Expected healthy subscriber to receive the event, got %d deliveries", delivered)
+	}
+	if collector.count() != 2 {
+		t.Errorf("Expected 2 reported errors, got %d", collector.count())
+	}
+}
+
+func TestBus_AsyncKeepsOrderAndDrainsOnClose(t *testing.T) {
+	// Arrange
+	collector := &errorCollector{}
+	bus := NewBus(collector.handle)
+	var received []int
+
+	bus.Subscribe("tick", func(ctx context.Context, event Event) error {
+		received = append(received, event.(testEvent).value)
+		if event.(testEvent).value == 3 {
+			return errors.New("async error")
+		}
+		return nil
+	}, Async(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Act
+	for i := 1; i <= 5; i++ {
+		bus.Publish(ctx, testEvent{name: "tick", value: i})
+	}
+	cancel()
+	bus.Close()
+
+	// Assert
+	if len(received) != 5 {
+		t.Fatalf("Expected 5 async deliveries, got %v", received)
+	}
+	for i, value := range received {
+		if value != i+1 {
+			t.Fatalf("Expected events in publish order, got %v", received)
+		}
+	}
+	if collector.count() != 1 {
+		t.Errorf("Expected async error to be reported, got %d", collector.count())
+	}
+
+	bus.Publish(context.Background(), testEvent{name: "tick"})
+	if collector.count() != 2 {
+		t.Errorf("Expected ErrBusClosed after Close, got %d errors", collector.count())
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	// Arrange
+	bus := NewBus(nil)
+	delivered := 0
+
+	unsubscribe := bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		delivered++
+		return nil
+	})
+
+	// Act
+	bus.Publish(context.Background(), testEvent{name: "created"})
+	unsubscribe()
+	unsubscribe()
+	bus.Publish(context.Background(), testEvent{name: "created"})
+
+	// Assert
+	if delivered != 1 {
+		t.Errorf("Expected 1 delivery before unsubscribing, got %d", delivered)
+	}
+}
+
+func TestBus_DispatchReturnsSubscriberErrors(t *testing.T) {
+	// Arrange
+	collector := &errorCollector{}
+	bus := NewBus(collector.handle)
+	delivered := 0
+
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		return errors.New("subscriber error")
+	})
+	bus.Subscribe("created", func(ctx context.Context, event Event) error {
+		delivered++
+		return nil
+	})
+
+	// Act
+	err := bus.Dispatch(context.Background(), testEvent{name: "created"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected the subscriber error to be returned")
+	}
+	if delivered != 1 {
+		t.Errorf("Expected healthy subscriber to receive the event, got %d deliveries", delivered)
+	}
+	if collector.count() != 0 {
+		t.Errorf("Expected Dispatch errors not to reach the ErrorHandler, got %d", collector.count())
+	}
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	// Arrange
+	registry := NewRegistry(testEvent{name: "created"})
+
+	// Act
+	_, unknownErr := registry.Decode("deleted", []byte(`{}`))
+	event, err := registry.Decode("created", []byte(`{}`))
+
+	// Assert
+	if unknownErr == nil {
+		t.Error("Expected error for unknown event")
+	}
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := event.(testEvent); !ok {
+		t.Errorf("Expected testEvent, got %T", event)
+	}
+}
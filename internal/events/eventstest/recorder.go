@@ -0,0 +1,81 @@
+// Package eventstest tem helpers para testar quem publica eventos.
+package eventstest
+
+import (
+	"context"
+	"gin-quickstart/internal/events"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Recorder é um events.IPublisher (e IDispatcher) que só guarda o que foi
+// publicado.
+type Recorder struct {
+	mutex  sync.Mutex
+	events []events.Event
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (recorder *Recorder) Publish(ctx context.Context, published ...events.Event) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.events = append(recorder.events, published...)
+}
+
+// Dispatch permite usar o Recorder como destino do relay da outbox.
+func (recorder *Recorder) Dispatch(ctx context.Context, event events.Event) error {
+	recorder.Publish(ctx, event)
+	return nil
+}
+
+func (recorder *Recorder) Events() []events.Event {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append([]events.Event(nil), recorder.events...)
+}
+
+// Names devolve os nomes dos eventos publicados, em ordem.
+func (recorder *Recorder) Names() []string {
+	var names []string
+	for _, event := range recorder.Events() {
+		names = append(names, event.EventName())
+	}
+	return names
+}
+
+func (recorder *Recorder) Reset() {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.events = nil
+}
+
+// AssertPublished falha o teste se os eventos publicados não forem
+// exatamente names, nessa ordem.
+func (recorder *Recorder) AssertPublished(t testing.TB, names ...string) {
+	t.Helper()
+
+	if got := recorder.Names(); !reflect.DeepEqual(got, names) && (len(got) != 0 || len(names) != 0) {
+		t.Fatalf("Expected events %v, got %v", names, got)
+	}
+}
+
+// Last devolve o último evento publicado do tipo T.
+func Last[T events.Event](recorder *Recorder) (T, bool) {
+	published := recorder.Events()
+
+	for i := len(published) - 1; i >= 0; i-- {
+		if event, ok := published[i].(T); ok {
+			return event, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Registry conhece os tipos concretos dos eventos pelo nome, para que
+// eventos serializados (ex: na outbox) voltem a ser os mesmos structs.
+type Registry struct {
+	types map[string]reflect.Type
+}
+
+func NewRegistry(prototypes ...Event) *Registry {
+	registry := &Registry{types: map[string]reflect.Type{}}
+	for _, prototype := range prototypes {
+		registry.types[prototype.EventName()] = reflect.TypeOf(prototype)
+	}
+	return registry
+}
+
+// Decode reconstrói o evento name a partir do JSON em payload.
+func (registry *Registry) Decode(name string, payload []byte) (Event, error) {
+	eventType, ok := registry.types[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+
+	value := reflect.New(eventType)
+	if err := json.Unmarshal(payload, value.Interface()); err != nil {
+		return nil, fmt.Errorf("decoding event %q: %w", name, err)
+	}
+
+	return value.Elem().Interface().(Event), nil
+}
@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+type inMemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+func NewInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{
+		records: make(map[string]*Record),
+		now:     time.Now,
+	}
+}
+
+func (store *inMemoryStore) Reserve(key, fingerprint string, ttl, lease time.Duration) (*Record, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := store.now()
+
+	if existing, ok := store.records[key]; ok && !existing.Expired(now) {
+		takeOver := !existing.Completed && !existing.Locked(now) && existing.Fingerprint == fingerprint
+		if takeOver {
+			existing.LockedUntil = now.Add(lease)
+		}
+
+		copied := *existing
+		return &copied, takeOver, nil
+	}
+
+	record := &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(lease),
+		ExpiresAt:   now.Add(ttl),
+	}
+	store.records[key] = record
+	store.purgeExpired(now)
+
+	copied := *record
+	return &copied, true, nil
+}
+
+func (store *inMemoryStore) Complete(key string, statusCode int, header http.Header, body []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	record, ok := store.records[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	record.Completed = true
+	record.StatusCode = statusCode
+	record.Header = header.Clone()
+	record.Body = append([]byte(nil), body...)
+	return nil
+}
+
+func (store *inMemoryStore) Release(key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.records, key)
+	return nil
+}
+
+func (store *inMemoryStore) purgeExpired(now time.Time) {
+	for key, record := range store.records {
+		if record.Expired(now) {
+			delete(store.records, key)
+		}
+	}
+}
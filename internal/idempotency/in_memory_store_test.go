@@ -0,0 +1,116 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestInMemoryStore_ReserveAndComplete(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+
+	// Act
+	_, created, err := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+	if err != nil || !created {
+		t.Fatalf("Expected key to be reserved, got created=%v err=%v", created, err)
+	}
+
+	header := http.Header{"Location": []string{"/v1/categories/1"}}
+	if err := store.Complete("key-1", http.StatusCreated, header, []byte(`{"data":{}}`)); err != nil {
+		t.Fatalf("Error completing key: %v", err)
+	}
+
+	record, created, err := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created {
+		t.Fatal("Expected existing record, got a new reservation")
+	}
+
+	if !record.Completed || record.StatusCode != http.StatusCreated {
+		t.Errorf("Expected completed 201 record, got %+v", record)
+	}
+
+	if record.Header.Get("Location") != "/v1/categories/1" || string(record.Body) != `{"data":{}}` {
+		t.Errorf("Unexpected stored response %+v", record)
+	}
+}
+
+func TestInMemoryStore_ExpiredKeyCanBeReused(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Reserve("key-1", "first", time.Minute, time.Minute)
+
+	// Act
+	now = now.Add(2 * time.Minute)
+	record, created, err := store.Reserve("key-1", "second", time.Minute, time.Minute)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !created || record.Fingerprint != "second" {
+		t.Errorf("Expected a fresh reservation, got created=%v record=%+v", created, record)
+	}
+}
+
+func TestInMemoryStore_RetryTakesOverAfterTheLease(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+
+	// Act
+	_, lockedCreated, _ := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+	now = now.Add(2 * time.Minute)
+	_, otherCreated, _ := store.Reserve("key-1", "other", time.Hour, time.Minute)
+	record, created, err := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+	_, againCreated, _ := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if lockedCreated || otherCreated {
+		t.Error("Expected the key to stay reserved while locked or for another request")
+	}
+
+	if !created || !record.LockedUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected the retry to take over with a new lease, got created=%v record=%+v", created, record)
+	}
+
+	if againCreated {
+		t.Error("Expected only one retry to take over the key")
+	}
+}
+
+func TestInMemoryStore_Release(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+	store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+
+	// Act
+	store.Release("key-1")
+	_, created, _ := store.Reserve("key-1", "fingerprint", time.Hour, time.Minute)
+
+	// Assert
+	if !created {
+		t.Error("Expected released key to be reservable again")
+	}
+
+	if err := store.Complete("missing", http.StatusOK, nil, nil); err != ErrKeyNotFound {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
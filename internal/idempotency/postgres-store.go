@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKey é a linha da tabela idempotency_keys.
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string `gorm:"not null"`
+	Completed   bool   `gorm:"not null;default:false"`
+	StatusCode  int
+	Header      []byte `gorm:"type:jsonb"`
+	Body        []byte
+	LockedUntil time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *postgresStore {
+	return &postgresStore{
+		db: db,
+	}
+}
+
+func (store *postgresStore) Reserve(key, fingerprint string, ttl, lease time.Duration) (*Record, bool, error) {
+	now := time.Now()
+
+	// Libera a chave se o registro anterior expirou
+	err := store.db.Where("key = ? AND expires_at <= ?", key, now).Delete(&IdempotencyKey{}).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	row := IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(lease),
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	// O INSERT ... ON CONFLICT DO NOTHING garante que só uma réplica reserva a chave
+	result := store.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	if result.RowsAffected == 1 {
+		record, err := toRecord(&row)
+		return record, true, err
+	}
+
+	// Assume a reserva pendente cuja trava venceu; o UPDATE condicional
+	// garante que só um dos reenvios a assume
+	result = store.db.Model(&IdempotencyKey{}).
+		Where("key = ? AND fingerprint = ? AND NOT completed AND locked_until <= ? AND expires_at > ?", key, fingerprint, now, now).
+		Update("locked_until", now.Add(lease))
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	takenOver := result.RowsAffected == 1
+
+	var existing IdempotencyKey
+	err = store.db.Where("key = ?", key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Expirou e foi removida entre o INSERT e o SELECT: tenta de novo
+		return store.Reserve(key, fingerprint, ttl, lease)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	record, err := toRecord(&existing)
+	return record, takenOver, err
+}
+
+func (store *postgresStore) Complete(key string, statusCode int, header http.Header, body []byte) error {
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	result := store.db.Model(&IdempotencyKey{}).Where("key = ?", key).Updates(map[string]any{
+		"completed":   true,
+		"status_code": statusCode,
+		"header":      encodedHeader,
+		"body":        body,
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+func (store *postgresStore) Release(key string) error {
+	return store.db.Where("key = ?", key).Delete(&IdempotencyKey{}).Error
+}
+
+func toRecord(row *IdempotencyKey) (*Record, error) {
+	record := &Record{
+		Key:         row.Key,
+		Fingerprint: row.Fingerprint,
+		Completed:   row.Completed,
+		StatusCode:  row.StatusCode,
+		Body:        row.Body,
+		LockedUntil: row.LockedUntil,
+		ExpiresAt:   row.ExpiresAt,
+	}
+
+	if len(row.Header) > 0 {
+		if err := json.Unmarshal(row.Header, &record.Header); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,46 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("idempotency key not found")
+
+// Record guarda a resposta original de uma requisição feita com
+// Idempotency-Key. Enquanto Completed for false a requisição original
+// ainda está em andamento, mas só até LockedUntil: depois disso ela é dada
+// como perdida (ex: a réplica caiu) e um reenvio pode assumir a chave.
+type Record struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+func (record *Record) Expired(now time.Time) bool {
+	return !now.Before(record.ExpiresAt)
+}
+
+// Locked diz se a requisição original ainda está em andamento.
+func (record *Record) Locked(now time.Time) bool {
+	return !record.Completed && now.Before(record.LockedUntil)
+}
+
+// IStore persiste os registros de idempotência.
+//
+// Reserve cria um registro pendente para a chave, travado por lease. Se já
+// existir um registro válido (não expirado) ele é devolvido com
+// created=false e nada é gravado, a não ser que esteja pendente com a
+// trava vencida e o mesmo fingerprint: aí a reserva passa para quem chamou,
+// travada por mais um lease, e volta com created=true.
+type IStore interface {
+	Reserve(key, fingerprint string, ttl, lease time.Duration) (record *Record, created bool, err error)
+	Complete(key string, statusCode int, header http.Header, body []byte) error
+	Release(key string) error
+}
@@ -0,0 +1,258 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme descreve uma forma de autenticação: type "http" com scheme
+// "bearer", type "apiKey" com In e Name do header, ou type "mutualTLS".
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type PathItem struct {
+	Get    *OperationObject `json:"get,omitempty"`
+	Post   *OperationObject `json:"post,omitempty"`
+	Put    *OperationObject `json:"put,omitempty"`
+	Patch  *OperationObject `json:"patch,omitempty"`
+	Delete *OperationObject `json:"delete,omitempty"`
+}
+
+type OperationObject struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []ParameterObject          `json:"parameters,omitempty"`
+	RequestBody *RequestBodyObject         `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBodyObject struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation descreve uma rota registrada no router. Request e os Body das
+// respostas recebem valores dos DTOs (ex: createCategoryInput{}) e viram
+// schemas via reflection.
+//
+// RequestContentTypes e ResponseContentTypes trocam o application/json
+// padrão de toda a operação (ex: multipart/form-data num upload, ou os
+// formatos aceitos na negociação de conteúdo); Response.ContentTypes troca
+// o de uma resposta só, ex: text/csv numa exportação.
+//
+// Security lista os security schemes aceitos (basta um deles); vazio deixa
+// a operação pública.
+type Operation struct {
+	ID                   string
+	Summary              string
+	Tags                 []string
+	Deprecated           bool
+	Parameters           []Parameter
+	Request              any
+	RequestContentTypes  []string
+	Responses            map[int]Response
+	ResponseContentTypes []string
+	Security             []string
+}
+
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Example     any
+}
+
+type Response struct {
+	Description  string
+	Body         any
+	ContentTypes []string
+}
+
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+}
+
+// AddSecurityScheme registra um security scheme referenciável por
+// Operation.Security.
+func (document *Document) AddSecurityScheme(name string, scheme SecurityScheme) {
+	if document.Components.SecuritySchemes == nil {
+		document.Components.SecuritySchemes = map[string]*SecurityScheme{}
+	}
+	document.Components.SecuritySchemes[name] = &scheme
+}
+
+// AddOperation registra a operação no documento. O path segue a sintaxe do
+// gin (/categories/:id) e é convertido para a sintaxe do OpenAPI.
+func (document *Document) AddOperation(method, path string, operation Operation) {
+	path = ConvertPath(path)
+
+	item, ok := document.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		document.Paths[path] = item
+	}
+
+	object := &OperationObject{
+		OperationID: operation.ID,
+		Summary:     operation.Summary,
+		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
+		Responses:   map[string]*ResponseObject{},
+	}
+
+	for _, parameter := range operation.Parameters {
+		object.Parameters = append(object.Parameters, ParameterObject{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Description: parameter.Description,
+			Required:    parameter.Required || parameter.In == "path",
+			Schema:      document.SchemaOf(parameter.Example),
+		})
+	}
+
+	if operation.Request != nil {
+		schema := document.SchemaOf(operation.Request)
+		object.RequestBody = &RequestBodyObject{Required: true, Content: map[string]MediaType{}}
+		for _, contentType := range defaultContentTypes(operation.RequestContentTypes) {
+			object.RequestBody.Content[contentType] = MediaType{Schema: schema}
+		}
+	}
+
+	for status, response := range operation.Responses {
+		responseObject := &ResponseObject{Description: response.Description}
+		if responseObject.Description == "" {
+			responseObject.Description = http.StatusText(status)
+		}
+		if response.Body != nil {
+			contentTypes := response.ContentTypes
+			if len(contentTypes) == 0 {
+				contentTypes = defaultContentTypes(operation.ResponseContentTypes)
+			}
+
+			schema := document.SchemaOf(response.Body)
+			responseObject.Content = map[string]MediaType{}
+			for _, contentType := range contentTypes {
+				responseObject.Content[contentType] = MediaType{Schema: schema}
+			}
+		}
+		object.Responses[strconv.Itoa(status)] = responseObject
+	}
+
+	for _, scheme := range operation.Security {
+		object.Security = append(object.Security, map[string][]string{scheme: {}})
+	}
+
+	switch method {
+	case http.MethodGet:
+		item.Get = object
+	case http.MethodPost:
+		item.Post = object
+	case http.MethodPut:
+		item.Put = object
+	case http.MethodPatch:
+		item.Patch = object
+	case http.MethodDelete:
+		item.Delete = object
+	}
+}
+
+func defaultContentTypes(contentTypes []string) []string {
+	if len(contentTypes) == 0 {
+		return []string{"application/json"}
+	}
+	return contentTypes
+}
+
+// Operations devolve "METHOD path" de todas as operações documentadas,
+// ordenadas, para comparação com as rotas do gin.
+func (document *Document) Operations() []string {
+	var operations []string
+
+	for path, item := range document.Paths {
+		for method, object := range map[string]*OperationObject{
+			http.MethodGet:    item.Get,
+			http.MethodPost:   item.Post,
+			http.MethodPut:    item.Put,
+			http.MethodPatch:  item.Patch,
+			http.MethodDelete: item.Delete,
+		} {
+			if object != nil {
+				operations = append(operations, method+" "+path)
+			}
+		}
+	}
+
+	sort.Strings(operations)
+	return operations
+}
+
+// ConvertPath troca os parâmetros do gin (:id, *path) por {id}, {path}.
+func ConvertPath(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
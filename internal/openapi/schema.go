@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+)
+
+// SchemaOf gera o schema de um valor. Structs nomeadas são registradas em
+// components.schemas e referenciadas via $ref.
+func (document *Document) SchemaOf(value any) *Schema {
+	if value == nil {
+		return &Schema{Type: "string"}
+	}
+
+	return document.schemaOfType(reflect.TypeOf(value))
+}
+
+func (document *Document) schemaOfType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if t == fileHeaderType {
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: document.schemaOfType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: document.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return document.structSchema(t)
+		}
+
+		name := schemaName(t)
+		if _, ok := document.Components.Schemas[name]; !ok {
+			// Reserva o nome antes de descer nos campos para suportar tipos recursivos.
+			document.Components.Schemas[name] = &Schema{}
+			*document.Components.Schemas[name] = *document.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+func (document *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := document.structSchema(indirect(field.Type))
+			for key, value := range embedded.Properties {
+				schema.Properties[key] = value
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := document.schemaOfType(field.Type)
+		if applyBinding(property, field) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// applyBinding traduz as regras do validator usadas nas tags `binding`
+// para as palavras-chave equivalentes do JSON Schema. Retorna true quando
+// o campo é obrigatório.
+func applyBinding(schema *Schema, field reflect.StructField) bool {
+	tag := field.Tag.Get("binding")
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	required := false
+	isString := indirect(field.Type).Kind() == reflect.String
+	isCollection := schema.Type == "array" || schema.Type == "object"
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "required":
+			required = true
+		case "min", "gte":
+			setLowerBound(schema, value, isString, isCollection)
+		case "max", "lte":
+			setUpperBound(schema, value, isString, isCollection)
+		case "len":
+			setLowerBound(schema, value, isString, isCollection)
+			setUpperBound(schema, value, isString, isCollection)
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		}
+	}
+
+	return required
+}
+
+func setLowerBound(schema *Schema, value string, isString, isCollection bool) {
+	switch {
+	case isString:
+		if n, err := strconv.Atoi(value); err == nil {
+			schema.MinLength = &n
+		}
+	case isCollection:
+		if n, err := strconv.Atoi(value); err == nil {
+			schema.MinItems = &n
+		}
+	default:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			schema.Minimum = &n
+		}
+	}
+}
+
+func setUpperBound(schema *Schema, value string, isString, isCollection bool) {
+	switch {
+	case isString:
+		if n, err := strconv.Atoi(value); err == nil {
+			schema.MaxLength = &n
+		}
+	case isCollection:
+		if n, err := strconv.Atoi(value); err == nil {
+			schema.MaxItems = &n
+		}
+	default:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			schema.Maximum = &n
+		}
+	}
+}
+
+// jsonName usa a tag json e, na falta dela, a tag form (DTOs de multipart).
+func jsonName(field reflect.StructField) (name string, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		tag = field.Tag.Get("form")
+	}
+	if tag == "-" {
+		return "", true
+	}
+
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// schemaName usa o nome do tipo com a primeira letra maiúscula, assim DTOs
+// não exportados (createCategoryInput) aparecem como CreateCategoryInput.
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+
+	// Tipos genéricos vêm como Envelope[pkg/path.Type]; mantemos só o nome curto.
+	if open := strings.Index(name, "["); open != -1 {
+		inner := strings.TrimLeft(name[open+1:len(name)-1], "*")
+		list := strings.HasPrefix(inner, "[]")
+		inner = inner[strings.LastIndexAny(inner, "/.]*")+1:]
+		if list {
+			inner += "List"
+		}
+		name = name[:open] + upperFirst(inner)
+	}
+
+	return upperFirst(name)
+}
+
+func upperFirst(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return name
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"encoding/json"
+	"gin-quickstart/internal/events"
+	"time"
+)
+
+// Message é um evento gravado na mesma transação da alteração que o
+// gerou, esperando o relay entregá-lo. É também a linha da tabela
+// outbox_messages.
+type Message struct {
+	ID            uint      `gorm:"primaryKey"`
+	EventName     string    `gorm:"not null"`
+	Payload       []byte    `gorm:"type:jsonb;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     string
+	DispatchedAt  *time.Time `gorm:"index"`
+	// AbandonedAt é preenchido quando o relay desiste depois de MaxAttempts
+	AbandonedAt *time.Time
+	CreatedAt   time.Time
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// NewMessages serializa os eventos em JSON, prontos para a entrega imediata.
+func NewMessages(published ...events.Event) ([]*Message, error) {
+	now := time.Now()
+	messages := make([]*Message, 0, len(published))
+
+	for _, event := range published {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, &Message{
+			EventName:     event.EventName(),
+			Payload:       payload,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	return messages, nil
+}
+
+// IStore é o lado de leitura da outbox, usado pelo relay. A escrita é feita
+// pelos repositórios (AddEvents), dentro da transação da alteração.
+//
+// ClaimPending devolve até limit mensagens pendentes cujo NextAttemptAt já
+// passou e as reserva por lease, para que outro relay não as pegue ao mesmo
+// tempo. MarkFailed reagenda a mensagem para nextAttemptAt ou, com
+// nextAttemptAt nil, a abandona.
+//
+// MarkDispatched não apaga a mensagem: com o Postgres as outras réplicas a
+// leem pelo ID depois do NOTIFY. PruneDispatched apaga as entregues antes
+// de before e devolve quantas foram apagadas; as abandonadas ficam, para
+// inspeção.
+type IStore interface {
+	ClaimPending(limit int, now time.Time, lease time.Duration) ([]*Message, error)
+	MarkDispatched(id uint, at time.Time) error
+	MarkFailed(id uint, lastError string, nextAttemptAt *time.Time) error
+	PruneDispatched(before time.Time) (int, error)
+}
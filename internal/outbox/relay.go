@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"context"
+	"gin-quickstart/internal/events"
+	"log"
+	"time"
+)
+
+const (
+	DefaultBatchSize = 100
+	MaxAttempts      = 10
+	// DefaultRetention é por quanto tempo uma mensagem entregue fica na
+	// outbox antes de ser apagada.
+	DefaultRetention = time.Hour
+	// lease é quanto tempo uma mensagem fica reservada para o relay que a pegou
+	lease = 30 * time.Second
+	// pruneInterval é de quanto em quanto tempo Run apaga as mensagens antigas
+	pruneInterval = time.Minute
+)
+
+// Backoff devolve a espera antes da tentativa seguinte à attempt-ésima
+// falha: 1s, 2s, 4s... até 10 minutos.
+func Backoff(attempt int) time.Duration {
+	const maxBackoff = 10 * time.Minute
+
+	delay := time.Second << (attempt - 1)
+	if attempt > 20 || delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+// Relay entrega as mensagens pendentes da outbox ao dispatcher (normalmente
+// o events.Bus). A entrega é pelo menos uma vez: se algum assinante falhar a
+// mensagem volta a ser entregue, a todos, depois do backoff.
+type Relay struct {
+	store      IStore
+	registry   *events.Registry
+	dispatcher events.IDispatcher
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewRelay(store IStore, registry *events.Registry, dispatcher events.IDispatcher) *Relay {
+	return &Relay{
+		store:      store,
+		registry:   registry,
+		dispatcher: dispatcher,
+		batchSize:  DefaultBatchSize,
+		now:        time.Now,
+	}
+}
+
+// Run chama DispatchPending a cada interval até ctx ser cancelado e, a
+// cada pruneInterval, apaga as mensagens entregues há mais de retention.
+func (relay *Relay) Run(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		if _, err := relay.DispatchPending(ctx); err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		if now := relay.now(); now.Sub(pruned) >= pruneInterval {
+			if _, err := relay.store.PruneDispatched(now.Add(-retention)); err != nil {
+				log.Printf("outbox relay: %v", err)
+			}
+			pruned = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending entrega um lote de mensagens pendentes e retorna quantas
+// foram entregues. Falhas de entrega reagendam a mensagem; só erros da
+// outbox em si são devolvidos.
+func (relay *Relay) DispatchPending(ctx context.Context) (int, error) {
+	messages, err := relay.store.ClaimPending(relay.batchSize, relay.now(), lease)
+
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+
+	for _, message := range messages {
+		err := relay.dispatch(ctx, message)
+
+		if err == nil {
+			if err := relay.store.MarkDispatched(message.ID, relay.now()); err != nil {
+				return dispatched, err
+			}
+			dispatched++
+			continue
+		}
+
+		var nextAttemptAt *time.Time
+		if attempts := message.Attempts + 1; attempts < MaxAttempts {
+			next := relay.now().Add(Backoff(attempts))
+			nextAttemptAt = &next
+		}
+
+		if err := relay.store.MarkFailed(message.ID, err.Error(), nextAttemptAt); err != nil {
+			return dispatched, err
+		}
+	}
+
+	return dispatched, nil
+}
+
+func (relay *Relay) dispatch(ctx context.Context, message *Message) error {
+	event, err := relay.registry.Decode(message.EventName, message.Payload)
+
+	if err != nil {
+		return err
+	}
+
+	return relay.dispatcher.Dispatch(ctx, event)
+}
@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/events"
+)
+
+type testEvent struct {
+	Value int `json:"value"`
+}
+
+func (testEvent) EventName() string { return "test.happened" }
+
+// fakeStore é uma outbox mínima em memória para testar o relay isolado.
+type fakeStore struct {
+	messages []*Message
+}
+
+func (store *fakeStore) ClaimPending(limit int, now time.Time, lease time.Duration) ([]*Message, error) {
+	var claimed []*Message
+	for _, message := range store.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if message.DispatchedAt != nil || message.AbandonedAt != nil || message.NextAttemptAt.After(now) {
+			continue
+		}
+		message.NextAttemptAt = now.Add(lease)
+		copied := *message
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (store *fakeStore) MarkDispatched(id uint, at time.Time) error {
+	message := store.messages[id-1]
+	message.Attempts++
+	message.DispatchedAt = &at
+	return nil
+}
+
+func (store *fakeStore) PruneDispatched(before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (store *fakeStore) MarkFailed(id uint, lastError string, nextAttemptAt *time.Time) error {
+	message := store.messages[id-1]
+	message.Attempts++
+	message.LastError = lastError
+	if nextAttemptAt == nil {
+		abandonedAt := time.Now()
+		message.AbandonedAt = &abandonedAt
+		return nil
+	}
+	message.NextAttemptAt = *nextAttemptAt
+	return nil
+}
+
+type flakyDispatcher struct {
+	failures  int
+	delivered []events.Event
+}
+
+func (dispatcher *flakyDispatcher) Dispatch(ctx context.Context, event events.Event) error {
+	if dispatcher.failures > 0 {
+		dispatcher.failures--
+		return errors.New("subscriber unavailable")
+	}
+	dispatcher.delivered = append(dispatcher.delivered, event)
+	return nil
+}
+
+func newTestRelay(t *testing.T, dispatcher events.IDispatcher, published ...events.Event) (*Relay, *fakeStore, *time.Time) {
+	t.Helper()
+
+	messages, err := NewMessages(published...)
+	if err != nil {
+		t.Fatalf("Error creating messages: %v", err)
+	}
+
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i, message := range messages {
+		message.ID = uint(i + 1)
+		message.NextAttemptAt = now
+	}
+
+	store := &fakeStore{messages: messages}
+	relay := NewRelay(store, events.NewRegistry(testEvent{}), dispatcher)
+	relay.now = func() time.Time { return now }
+
+	return relay, store, &now
+}
+
+func TestRelay_DispatchesDecodedEvents(t *testing.T) {
+	// Arrange
+	dispatcher := &flakyDispatcher{}
+	relay, store, _ := newTestRelay(t, dispatcher, testEvent{Value: 1}, testEvent{Value: 2})
+
+	// Act
+	dispatched, err := relay.DispatchPending(context.Background())
+
+	// Assert
+	if err != nil || dispatched != 2 {
+		t.Fatalf("Expected 2 dispatched messages, got %d (%v)", dispatched, err)
+	}
+	if event, ok := dispatcher.delivered[1].(testEvent); !ok || event.Value != 2 {
+		t.Errorf("Expected decoded testEvent{2}, got %#v", dispatcher.delivered[1])
+	}
+	if store.messages[0].DispatchedAt == nil {
+		t.Error("Expected message to be marked as dispatched")
+	}
+
+	if again, _ := relay.DispatchPending(context.Background()); again != 0 {
+		t.Errorf("Expected dispatched messages not to be delivered again, got %d", again)
+	}
+}
+
+func TestRelay_RetriesWithBackoff(t *testing.T) {
+	// Arrange
+	dispatcher := &flakyDispatcher{failures: 2}
+	relay, store, now := newTestRelay(t, dispatcher, testEvent{Value: 1})
+
+	// Act & Assert
+	relay.DispatchPending(context.Background())
+	if store.messages[0].Attempts != 1 || !store.messages[0].NextAttemptAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("Expected retry in 1s after first failure, got attempt %d at %v", store.messages[0].Attempts, store.messages[0].NextAttemptAt)
+	}
+
+	// Antes do backoff nada é entregue
+	if dispatched, _ := relay.DispatchPending(context.Background()); dispatched != 0 || store.messages[0].Attempts != 1 {
+		t.Fatalf("Expected no attempt before the backoff, got %d dispatched", dispatched)
+	}
+
+	*now = now.Add(time.Second)
+	relay.DispatchPending(context.Background())
+	if !store.messages[0].NextAttemptAt.Equal(now.Add(2 * time.Second)) {
+		t.Fatalf("Expected retry in 2s after second failure, got %v", store.messages[0].NextAttemptAt)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if dispatched, _ := relay.DispatchPending(context.Background()); dispatched != 1 {
+		t.Fatalf("Expected delivery on third attempt, got %d", dispatched)
+	}
+}
+
+func TestRelay_AbandonsAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	dispatcher := &flakyDispatcher{failures: MaxAttempts}
+	relay, store, now := newTestRelay(t, dispatcher, testEvent{Value: 1})
+
+	// Act
+	for i := 0; i < MaxAttempts; i++ {
+		relay.DispatchPending(context.Background())
+		*now = now.Add(time.Hour)
+	}
+
+	// Assert
+	if store.messages[0].AbandonedAt == nil || store.messages[0].Attempts != MaxAttempts {
+		t.Fatalf("Expected message abandoned after %d attempts, got %d", MaxAttempts, store.messages[0].Attempts)
+	}
+	if store.messages[0].LastError != "subscriber unavailable" {
+		t.Errorf("Expected last error to be kept, got %q", store.messages[0].LastError)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{10, 512 * time.Second},
+		{11, 10 * time.Minute},
+		{64, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.expected {
+			t.Errorf("Backoff(%d): expected %v, got %v", tt.attempt, tt.expected, got)
+		}
+	}
+}
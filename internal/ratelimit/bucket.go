@@ -0,0 +1,106 @@
+// Package ratelimit limita quantas requisições cada cliente faz por
+// operação, com token buckets guardados em memória ou no Postgres (uma
+// cota comum a todas as réplicas da API).
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Limit permite Requests requisições a cada Per, em rajadas de até Burst
+// (Requests, se zero). Um Limit sem Requests não limita nada.
+type Limit struct {
+	Requests int           `yaml:"requests" json:"requests"`
+	Per      time.Duration `yaml:"per" json:"per"`
+	Burst    int           `yaml:"burst" json:"burst"`
+}
+
+func (limit Limit) Unlimited() bool {
+	return limit.Requests <= 0
+}
+
+func (limit Limit) capacity() float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+	return float64(limit.Requests)
+}
+
+// rate é quantos tokens voltam por segundo.
+func (limit Limit) rate() float64 {
+	return float64(limit.Requests) / limit.Per.Seconds()
+}
+
+// refill é quanto tempo um bucket vazio leva para encher: depois disso sem
+// uso ele equivale a um bucket novo e pode ser descartado.
+func (limit Limit) refill() time.Duration {
+	return time.Duration(limit.capacity() / limit.rate() * float64(time.Second))
+}
+
+func (limit Limit) validate() error {
+	if limit.Unlimited() {
+		return nil
+	}
+	if limit.Per <= 0 {
+		return fmt.Errorf("limit of %d requests needs a positive per", limit.Requests)
+	}
+	if limit.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", limit.Burst)
+	}
+	return nil
+}
+
+// Policy é o valor do header RateLimit-Policy, ex: "30;w=60;burst=10".
+func (limit Limit) Policy() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, int(math.Ceil(limit.Per.Seconds())), int(limit.capacity()))
+}
+
+// Result é o estado do bucket depois de uma requisição. Reset é quanto
+// falta para o bucket encher de novo; RetryAfter, quando a requisição foi
+// recusada, é quanto falta para o próximo token.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// bucket é o que os stores guardam por chave.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{tokens: limit.capacity(), updatedAt: now}
+}
+
+// take repõe os tokens do tempo decorrido e consome um, se houver. Com
+// relógios de réplicas diferentes now pode ser anterior a updatedAt; nesse
+// caso nada é reposto.
+func take(state *bucket, limit Limit, now time.Time) Result {
+	if elapsed := now.Sub(state.updatedAt); elapsed > 0 {
+		state.tokens = math.Min(limit.capacity(), state.tokens+elapsed.Seconds()*limit.rate())
+		state.updatedAt = now
+	}
+
+	result := Result{Limit: int(limit.capacity())}
+
+	if state.tokens >= 1 {
+		state.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - state.tokens) / limit.rate())
+	}
+
+	result.Remaining = int(state.tokens)
+	result.Reset = seconds((limit.capacity() - state.tokens) / limit.rate())
+	return result
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Buckets ociosos são descartados a cada pruneEvery chamadas de Take, para
+// que clientes de passagem (ex: IPs) não acumulem memória.
+const pruneEvery = 1000
+
+type inMemoryStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	// idleAfter é o maior refill visto, usado pela limpeza automática
+	idleAfter time.Duration
+	takes     int
+}
+
+func NewInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (store *inMemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	state, ok := store.buckets[key]
+	if !ok {
+		created := newBucket(limit, now)
+		state = &created
+		store.buckets[key] = state
+	}
+
+	store.idleAfter = max(store.idleAfter, limit.refill())
+	if store.takes++; store.takes%pruneEvery == 0 {
+		store.prune(now.Add(-store.idleAfter))
+	}
+
+	return take(state, limit, now), nil
+}
+
+func (store *inMemoryStore) Prune(idleSince time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.prune(idleSince)
+	return nil
+}
+
+func (store *inMemoryStore) prune(idleSince time.Time) {
+	for key, state := range store.buckets {
+		if state.updatedAt.Before(idleSince) {
+			delete(store.buckets, key)
+		}
+	}
+}
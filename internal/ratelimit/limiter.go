@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"gin-quickstart/internal/auth"
+	"log"
+	"os"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Rules são os limites por operação (o operationId do spec OpenAPI), com
+// Default para as demais. O formato do arquivo é:
+//
+//	default: {requests: 600, per: 1m}
+//	operations:
+//	  createCategory: {requests: 30, per: 1m, burst: 10}
+//	  helthz: {requests: 0}   # sem limite
+type Rules struct {
+	Default    Limit            `yaml:"default" json:"default"`
+	Operations map[string]Limit `yaml:"operations" json:"operations"`
+}
+
+// AuthenticateOperation é a "operação" do limite por IP aplicado antes da
+// autenticação, para que credenciais inválidas também gastem cota.
+const AuthenticateOperation = "authenticate"
+
+// DefaultRules seguram scripts descontrolados sem atrapalhar o uso normal:
+// as escritas em massa têm cotas menores que as leituras, e o limite por IP
+// é folgado para não pegar vários clientes atrás do mesmo NAT.
+var DefaultRules = Rules{
+	Default: Limit{Requests: 600, Per: time.Minute},
+	Operations: map[string]Limit{
+		AuthenticateOperation: {Requests: 1200, Per: time.Minute},
+		"createCategory":      {Requests: 60, Per: time.Minute, Burst: 20},
+		"batchCategories":     {Requests: 10, Per: time.Minute},
+		"importCategories":    {Requests: 10, Per: time.Minute},
+		"helthz":              {},
+	},
+}
+
+func (rules Rules) validate() error {
+	if err := rules.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for operation, limit := range rules.Operations {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("%s: %w", operation, err)
+		}
+	}
+	return nil
+}
+
+// For devolve o limite da operação, ou o Default.
+func (rules Rules) For(operation string) Limit {
+	if limit, ok := rules.Operations[operation]; ok {
+		return limit
+	}
+	return rules.Default
+}
+
+// LoadRules lê as regras de um arquivo YAML (ou JSON).
+func LoadRules(path string) (Rules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+
+	var rules Rules
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid rate limit file %s: %w", path, err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return Rules{}, fmt.Errorf("invalid rate limit file %s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// Limiter aplica as Rules sobre um IStore. Cada cliente tem um bucket por
+// operação.
+type Limiter struct {
+	store IStore
+	rules Rules
+	now   func() time.Time
+}
+
+// NewLimiter espera Rules válidas, como as de DefaultRules e LoadRules.
+func NewLimiter(store IStore, rules Rules) *Limiter {
+	return &Limiter{store: store, rules: rules, now: time.Now}
+}
+
+// Limit é o limite aplicado à operação.
+func (limiter *Limiter) Limit(operation string) Limit {
+	return limiter.rules.For(operation)
+}
+
+// Allow consome um token do bucket do cliente na operação. Operações sem
+// limite sempre passam, sem tocar no store.
+func (limiter *Limiter) Allow(operation, client string) (Result, error) {
+	limit := limiter.rules.For(operation)
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	return limiter.store.Take(operation+"|"+client, limit, limiter.now())
+}
+
+// ClientKey identifica o cliente pela API key, pelo certificado ou pelo
+// usuário autenticados e, sem credenciais (ex: com a autenticação
+// desligada), pelo IP. A API HTTP e a gRPC usam a mesma chave, então um
+// cliente tem uma cota só nas duas.
+func ClientKey(principal *auth.Principal, ip string) string {
+	if principal != nil {
+		switch principal.Method {
+		case auth.MethodAPIKey:
+			return "api_key:" + principal.Subject
+		case auth.MethodClientCertificate:
+			return "client_certificate:" + principal.Subject
+		case auth.MethodJWT:
+			return "user:" + principal.Subject
+		}
+	}
+
+	return "ip:" + ip
+}
+
+// Run descarta os buckets ociosos a cada interval até ctx ser cancelado.
+func (limiter *Limiter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := limiter.store.Prune(limiter.now().Add(-limiter.idleAfter())); err != nil {
+				log.Printf("rate limit prune failed: %v", err)
+			}
+		}
+	}
+}
+
+// idleAfter é o maior tempo de refill entre as regras: um bucket sem uso
+// por mais que isso já está cheio.
+func (limiter *Limiter) idleAfter() time.Duration {
+	idle := time.Duration(0)
+	if !limiter.rules.Default.Unlimited() {
+		idle = limiter.rules.Default.refill()
+	}
+	for _, limit := range limiter.rules.Operations {
+		if !limit.Unlimited() {
+			idle = max(idle, limit.refill())
+		}
+	}
+	return idle
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTake_RefillsAtTheLimitRate(t *testing.T) {
+	// Arrange
+	limit := Limit{Requests: 60, Per: time.Minute, Burst: 2}
+	start := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	state := newBucket(limit, start)
+
+	// Act
+	first := take(&state, limit, start)
+	second := take(&state, limit, start)
+	rejected := take(&state, limit, start)
+	refilled := take(&state, limit, start.Add(1500*time.Millisecond))
+
+	// Assert
+	if !first.Allowed || first.Limit != 2 || first.Remaining != 1 {
+		t.Errorf("Expected the first request allowed with 1 remaining, got %+v", first)
+	}
+
+	if !second.Allowed || second.Remaining != 0 || second.Reset != 2*time.Second {
+		t.Errorf("Expected the second request to empty the bucket for 2s, got %+v", second)
+	}
+
+	if rejected.Allowed || rejected.RetryAfter != time.Second {
+		t.Errorf("Expected the third request rejected for 1s, got %+v", rejected)
+	}
+
+	// 1,5 token voltou: um é consumido e sobra meio
+	if !refilled.Allowed || refilled.Remaining != 0 || refilled.Reset != 1500*time.Millisecond {
+		t.Errorf("Expected a request allowed after the refill, got %+v", refilled)
+	}
+}
+
+func TestTake_IgnoresClockGoingBackwards(t *testing.T) {
+	// Arrange
+	limit := Limit{Requests: 1, Per: time.Minute}
+	start := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	state := newBucket(limit, start)
+	take(&state, limit, start)
+
+	// Act
+	result := take(&state, limit, start.Add(-time.Hour))
+
+	// Assert
+	if result.Allowed || !state.updatedAt.Equal(start) {
+		t.Errorf("Expected no refill from an earlier clock, got %+v (updated at %s)", result, state.updatedAt)
+	}
+}
+
+func TestLimiter_AllowKeepsABucketPerOperationAndClient(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+	limiter := NewLimiter(store, Rules{
+		Default:    Limit{Requests: 1, Per: time.Minute},
+		Operations: map[string]Limit{"helthz": {}},
+	})
+
+	// Act
+	first, _ := limiter.Allow("createCategory", "ip:192.0.2.1")
+	again, _ := limiter.Allow("createCategory", "ip:192.0.2.1")
+	otherClient, _ := limiter.Allow("createCategory", "ip:192.0.2.2")
+	otherOperation, _ := limiter.Allow("listCategory", "ip:192.0.2.1")
+	unlimited, _ := limiter.Allow("helthz", "ip:192.0.2.1")
+
+	// Assert
+	if !first.Allowed || again.Allowed {
+		t.Errorf("Expected only the first request allowed, got %+v and %+v", first, again)
+	}
+
+	if !otherClient.Allowed || !otherOperation.Allowed {
+		t.Errorf("Expected separate buckets per client and operation, got %+v and %+v", otherClient, otherOperation)
+	}
+
+	if !unlimited.Allowed || len(store.buckets) != 3 {
+		t.Errorf("Expected unlimited operations to skip the store, got %+v with %d buckets", unlimited, len(store.buckets))
+	}
+}
+
+func TestInMemoryStore_PruneDropsIdleBuckets(t *testing.T) {
+	// Arrange
+	store := NewInMemoryStore()
+	limit := Limit{Requests: 10, Per: time.Minute}
+	start := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	store.Take("idle", limit, start)
+	store.Take("active", limit, start.Add(time.Minute))
+
+	// Act
+	store.Prune(start.Add(30 * time.Second))
+
+	// Assert
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("Expected the idle bucket to be pruned")
+	}
+
+	if _, ok := store.buckets["active"]; !ok {
+		t.Error("Expected the active bucket to be kept")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "default: {requests: 600, per: 1m}\noperations:\n  createCategory: {requests: 30, per: 1m, burst: 10}\n  helthz: {requests: 0}\n", ""},
+		{"missing per", "default: {requests: 600}\n", "default: limit of 600 requests needs a positive per"},
+		{"negative burst", "default: {requests: 600, per: 1m}\noperations:\n  createCategory: {requests: 30, per: 1m, burst: -1}\n", "createCategory: burst must not be negative"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "rate-limits.yaml")
+			os.WriteFile(path, []byte(testCase.content), 0o600)
+
+			// Act
+			rules, err := LoadRules(path)
+
+			// Assert
+			if testCase.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", testCase.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if limit := rules.For("createCategory"); limit.Requests != 30 || limit.Per != time.Minute || limit.Burst != 10 {
+				t.Errorf("Expected createCategory 30/1m burst 10, got %+v", limit)
+			}
+
+			if !rules.For("helthz").Unlimited() || rules.For("listCategory").Requests != 600 {
+				t.Errorf("Expected helthz unlimited and the default elsewhere, got %+v", rules)
+			}
+		})
+	}
+}
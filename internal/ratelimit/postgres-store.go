@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bucket é a linha da tabela rate_limit_buckets.
+type Bucket struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index;autoUpdateTime:false"`
+}
+
+func (Bucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *postgresStore {
+	return &postgresStore{
+		db: db,
+	}
+}
+
+// Take cria o bucket cheio se ele não existir e o lê com SELECT ... FOR
+// UPDATE, então as réplicas consomem o mesmo bucket uma de cada vez.
+func (store *postgresStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	var result Result
+
+	err := store.db.Transaction(func(tx *gorm.DB) error {
+		initial := newBucket(limit, now)
+		row := Bucket{Key: key, Tokens: initial.tokens, UpdatedAt: initial.updatedAt}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		state := bucket{tokens: row.Tokens, updatedAt: row.UpdatedAt}
+		result = take(&state, limit, now)
+
+		return tx.Model(&Bucket{}).Where("key = ?", key).Updates(map[string]any{
+			"tokens":     state.tokens,
+			"updated_at": state.updatedAt,
+		}).Error
+	})
+
+	return result, err
+}
+
+func (store *postgresStore) Prune(idleSince time.Time) error {
+	return store.db.Where("updated_at < ?", idleSince).Delete(&Bucket{}).Error
+}
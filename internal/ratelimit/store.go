@@ -0,0 +1,11 @@
+package ratelimit
+
+import "time"
+
+// IStore guarda um bucket por chave. Take repõe e consome o bucket de forma
+// atômica (duas réplicas nunca gastam o mesmo token); Prune descarta os
+// buckets sem uso desde idleSince, que já estariam cheios.
+type IStore interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+	Prune(idleSince time.Time) error
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"cmp"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/tenancy"
+	"slices"
+)
+
+// cachingCategoryRepository guarda no cache o List e o FindByID/FindByIDs
+// de qualquer repositório. Toda escrita invalida as entradas que alterou,
+// mesmo quando falha (com o Postgres o erro não garante que nada mudou).
+// ListPage, Stream (exportações) e FindChildren vão sempre ao repositório.
+//
+// Dentro de Transaction as leituras também vão ao repositório, para
+// enxergar as escritas da própria transação, e as invalidações ficam
+// guardadas em pending até o fim dela.
+type cachingCategoryRepository struct {
+	backend ICategoryRepository
+	cache   *CategoryCache
+	tenant  string
+	pending *[]cacheKey
+}
+
+// NewCachingCategoryRepository envolve o repositório do tenancy.Default
+// devolvido pelos construtores; ForTenant vale para os dois.
+func NewCachingCategoryRepository(backend ICategoryRepository, cache *CategoryCache) *cachingCategoryRepository {
+	return &cachingCategoryRepository{backend: backend, cache: cache, tenant: tenancy.Default}
+}
+
+func (repository *cachingCategoryRepository) ForTenant(tenant string) ICategoryRepository {
+	return &cachingCategoryRepository{
+		backend: repository.backend.ForTenant(tenant),
+		cache:   repository.cache,
+		tenant:  tenant,
+		pending: repository.pending,
+	}
+}
+
+func (repository *cachingCategoryRepository) Save(category *entities.Category) error {
+	defer repository.invalidate()
+	return repository.backend.Save(category)
+}
+
+func (repository *cachingCategoryRepository) SaveMany(categories []*entities.Category) error {
+	defer repository.invalidate()
+	return repository.backend.SaveMany(categories)
+}
+
+func (repository *cachingCategoryRepository) List() ([]*entities.Category, error) {
+	if repository.pending != nil {
+		return repository.backend.List()
+	}
+
+	return repository.cache.load(cacheKey{tenant: repository.tenant}, func() ([]entities.Category, error) {
+		categories, err := repository.backend.List()
+		if err != nil {
+			return nil, err
+		}
+		return values(categories), nil
+	})
+}
+
+func (repository *cachingCategoryRepository) ListPage(afterID uint, limit int) ([]*entities.Category, int, error) {
+	return repository.backend.ListPage(afterID, limit)
+}
+
+func (repository *cachingCategoryRepository) Stream(fn func(category *entities.Category) error) error {
+	return repository.backend.Stream(fn)
+}
+
+func (repository *cachingCategoryRepository) FindChildren(parentIDs []uint) ([]*entities.Category, error) {
+	return repository.backend.FindChildren(parentIDs)
+}
+
+func (repository *cachingCategoryRepository) FindByID(id uint) (*entities.Category, error) {
+	if repository.pending != nil {
+		return repository.backend.FindByID(id)
+	}
+
+	categories, err := repository.cache.load(cacheKey{tenant: repository.tenant, id: id}, func() ([]entities.Category, error) {
+		category, err := repository.backend.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return values([]*entities.Category{category}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories[0], nil
+}
+
+func (repository *cachingCategoryRepository) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	if repository.pending != nil {
+		return repository.backend.FindByIDs(ids)
+	}
+
+	unique := slices.Compact(slices.Sorted(slices.Values(ids)))
+	categories, err := repository.cache.loadMany(repository.tenant, unique, repository.backend.FindByIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(categories, func(a, b *entities.Category) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return categories, nil
+}
+
+func (repository *cachingCategoryRepository) Update(category *entities.Category) error {
+	defer repository.invalidate(category.ID)
+	return repository.backend.Update(category)
+}
+
+func (repository *cachingCategoryRepository) Delete(id uint, expectedVersion uint) error {
+	defer repository.invalidate(id)
+	return repository.backend.Delete(id, expectedVersion)
+}
+
+func (repository *cachingCategoryRepository) Restore(id uint) (*entities.Category, error) {
+	defer repository.invalidate(id)
+	return repository.backend.Restore(id)
+}
+
+// Transaction invalida depois do commit: antes dele, uma leitura de fora
+// ainda veria (e guardaria) o valor antigo.
+func (repository *cachingCategoryRepository) Transaction(fn func(repository ICategoryRepository) error) error {
+	if repository.pending != nil {
+		return repository.backend.Transaction(func(transaction ICategoryRepository) error {
+			return fn(&cachingCategoryRepository{backend: transaction, cache: repository.cache, tenant: repository.tenant, pending: repository.pending})
+		})
+	}
+
+	var pending []cacheKey
+	defer func() {
+		for _, key := range pending {
+			repository.cache.Invalidate(key.tenant, key.id)
+		}
+	}()
+
+	return repository.backend.Transaction(func(transaction ICategoryRepository) error {
+		return fn(&cachingCategoryRepository{backend: transaction, cache: repository.cache, tenant: repository.tenant, pending: &pending})
+	})
+}
+
+func (repository *cachingCategoryRepository) LockHierarchy() error {
+	return repository.backend.LockHierarchy()
+}
+
+func (repository *cachingCategoryRepository) AddEvents(published ...events.Event) error {
+	return repository.backend.AddEvents(published...)
+}
+
+func (repository *cachingCategoryRepository) AddAudit(entries ...*audit.Entry) error {
+	return repository.backend.AddAudit(entries...)
+}
+
+// invalidate descarta o List do tenant e as categorias ids, ou deixa para o
+// fim da transação.
+func (repository *cachingCategoryRepository) invalidate(ids ...uint) {
+	if repository.pending != nil {
+		*repository.pending = append(*repository.pending, cacheKey{tenant: repository.tenant})
+		for _, id := range ids {
+			*repository.pending = append(*repository.pending, cacheKey{tenant: repository.tenant, id: id})
+		}
+		return
+	}
+
+	repository.cache.Invalidate(repository.tenant, ids...)
+}
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"errors"
+	"gin-quickstart/internal/entities"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRepository conta as leituras que chegam ao repositório de baixo;
+// com release, List espera por ele para simular um banco lento.
+type countingRepository struct {
+	ICategoryRepository
+	reads   *atomic.Int64
+	release chan struct{}
+}
+
+func newCountingRepository() *countingRepository {
+	return &countingRepository{ICategoryRepository: NewInMemotyCategoryRepository(), reads: &atomic.Int64{}}
+}
+
+func (repository *countingRepository) ForTenant(tenant string) ICategoryRepository {
+	return &countingRepository{ICategoryRepository: repository.ICategoryRepository.ForTenant(tenant), reads: repository.reads, release: repository.release}
+}
+
+func (repository *countingRepository) List() ([]*entities.Category, error) {
+	repository.reads.Add(1)
+	if repository.release != nil {
+		<-repository.release
+	}
+	return repository.ICategoryRepository.List()
+}
+
+func (repository *countingRepository) FindByID(id uint) (*entities.Category, error) {
+	repository.reads.Add(1)
+	return repository.ICategoryRepository.FindByID(id)
+}
+
+func (repository *countingRepository) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	repository.reads.Add(1)
+	return repository.ICategoryRepository.FindByIDs(ids)
+}
+
+func newCachedRepository(options CacheOptions) (*cachingCategoryRepository, *countingRepository) {
+	backend := newCountingRepository()
+	return NewCachingCategoryRepository(backend, NewCategoryCache(options)), backend
+}
+
+func TestCachingRepository_ServesReadsFromTheCacheUntilAWrite(t *testing.T) {
+	// Arrange
+	repository, backend := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	repository.Save(&entities.Category{Name: "Electronics"})
+
+	// Act
+	repository.List()
+	cached, _ := repository.List()
+	cached[0].Name = "Changed by the caller"
+	beforeSave := backend.reads.Load()
+
+	repository.Save(&entities.Category{Name: "Home & Garden"})
+	afterSave, _ := repository.List()
+
+	// Assert
+	if beforeSave != 1 {
+		t.Errorf("Expected 1 read from the backend before the write, got %d", beforeSave)
+	}
+
+	if len(afterSave) != 2 || afterSave[0].Name != "Electronics" {
+		t.Errorf("Expected the saved category and an untouched copy, got %v", afterSave)
+	}
+
+	if stats := repository.cache.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+func TestCachingRepository_WritesInvalidateTheCategory(t *testing.T) {
+	// Arrange
+	repository, _ := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	category := &entities.Category{Name: "Electronics"}
+	repository.Save(category)
+	repository.FindByID(category.ID)
+
+	// Act
+	category.Name = "Consumer Electronics"
+	repository.Update(category)
+	renamed, _ := repository.FindByID(category.ID)
+
+	repository.Delete(category.ID, category.Version)
+	_, deletedErr := repository.FindByID(category.ID)
+
+	repository.Restore(category.ID)
+	restored, restoredErr := repository.FindByID(category.ID)
+
+	// Assert
+	if renamed.Name != "Consumer Electronics" {
+		t.Errorf("Expected the renamed category, got %q", renamed.Name)
+	}
+
+	if !errors.Is(deletedErr, ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound after the delete, got %v", deletedErr)
+	}
+
+	if restoredErr != nil || restored.Version != 4 {
+		t.Errorf("Expected the restored category at version 4, got %v, %v", restored, restoredErr)
+	}
+}
+
+func TestCachingRepository_EvictsByTTLAndSize(t *testing.T) {
+	// Arrange
+	repository, backend := newCachedRepository(CacheOptions{Size: 2, TTL: time.Minute})
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	repository.cache.now = func() time.Time { return now }
+	repository.SaveMany([]*entities.Category{{Name: "Electronics"}, {Name: "Home & Garden"}, {Name: "Outdoors"}})
+
+	// Act
+	repository.FindByID(1)
+	repository.FindByID(2)
+	repository.FindByID(1)
+	repository.FindByID(3) // descarta a 2, a menos usada
+	beforeEviction := backend.reads.Load()
+	repository.FindByID(2)
+	afterEviction := backend.reads.Load()
+
+	now = now.Add(time.Minute)
+	repository.FindByID(2)
+	afterTTL := backend.reads.Load()
+
+	// Assert
+	if beforeEviction != 3 || afterEviction != 4 {
+		t.Errorf("Expected the least recently used category to be read again, got %d then %d reads", beforeEviction, afterEviction)
+	}
+
+	if afterTTL != 5 {
+		t.Errorf("Expected an expired category to be read again, got %d reads", afterTTL)
+	}
+
+	if stats := repository.cache.Stats(); stats.Entries != 2 || stats.Evictions != 3 {
+		t.Errorf("Expected 2 entries and 3 evictions, got %+v", stats)
+	}
+}
+
+func TestCachingRepository_SharesConcurrentMisses(t *testing.T) {
+	// Arrange
+	backend := newCountingRepository()
+	backend.release = make(chan struct{})
+	repository := NewCachingCategoryRepository(backend, NewCategoryCache(CacheOptions{Size: 10, TTL: time.Minute}))
+	backend.ICategoryRepository.Save(&entities.Category{Name: "Electronics"})
+
+	// Act
+	var wg sync.WaitGroup
+	results := make([][]*entities.Category, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = repository.List()
+		}()
+	}
+
+	// Espera todos pedirem antes de liberar a única busca
+	for repository.cache.Stats().Misses < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	close(backend.release)
+	wg.Wait()
+
+	// Assert
+	if reads := backend.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 read from the backend, got %d", reads)
+	}
+
+	copies := make(map[*entities.Category]bool)
+	for _, categories := range results {
+		if len(categories) == 1 {
+			copies[categories[0]] = true
+		}
+	}
+	if len(copies) != 10 {
+		t.Errorf("Expected each caller to get its own copy, got %d", len(copies))
+	}
+}
+
+func TestCachingRepository_TransactionInvalidatesAfterCommit(t *testing.T) {
+	// Arrange
+	repository, _ := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	repository.Save(&entities.Category{Name: "Electronics"})
+	repository.List()
+
+	// Act
+	var insideTransaction, outsideTransaction []*entities.Category
+	repository.Transaction(func(transaction ICategoryRepository) error {
+		transaction.Save(&entities.Category{Name: "Home & Garden"})
+		insideTransaction, _ = transaction.List()
+		outsideTransaction, _ = repository.List()
+		return nil
+	})
+	committed, _ := repository.List()
+
+	rollbackErr := repository.Transaction(func(transaction ICategoryRepository) error {
+		transaction.Save(&entities.Category{Name: "Outdoors"})
+		return errors.New("rollback")
+	})
+	rolledBack, _ := repository.List()
+
+	// Assert
+	if len(insideTransaction) != 2 || len(outsideTransaction) != 1 {
+		t.Errorf("Expected the transaction to see its write and the cache not to, got %d and %d", len(insideTransaction), len(outsideTransaction))
+	}
+
+	if len(committed) != 2 {
+		t.Errorf("Expected the committed category after the transaction, got %d", len(committed))
+	}
+
+	if rollbackErr == nil || len(rolledBack) != 2 {
+		t.Errorf("Expected the rolled back category to stay out, got %d", len(rolledBack))
+	}
+}
+
+func TestCachingRepository_FindByIDsReadsOnlyTheMissingCategories(t *testing.T) {
+	// Arrange
+	repository, backend := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	repository.SaveMany([]*entities.Category{{Name: "Electronics"}, {Name: "Home & Garden"}, {Name: "Outdoors"}})
+	repository.FindByID(3)
+	backend.reads.Store(0)
+
+	// Act
+	categories, _ := repository.FindByIDs([]uint{3, 1, 3, 42})
+	cached, _ := repository.FindByIDs([]uint{1, 3})
+
+	// Assert
+	if len(categories) != 2 || categories[0].ID != 1 || categories[1].ID != 3 {
+		t.Errorf("Expected categories 1 and 3 in ID order, got %v", categories)
+	}
+
+	if len(cached) != 2 || backend.reads.Load() != 1 {
+		t.Errorf("Expected only the first call to reach the backend, got %d reads", backend.reads.Load())
+	}
+}
+
+func TestCachingRepository_KeepsTenantsApart(t *testing.T) {
+	// Arrange
+	repository, _ := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	acme := repository.ForTenant("acme")
+	repository.List()
+
+	// Act
+	acme.Save(&entities.Category{Name: "Electronics"})
+	defaultTenant, _ := repository.List()
+	acmeTenant, _ := acme.List()
+
+	// Assert
+	if len(defaultTenant) != 0 || len(acmeTenant) != 1 {
+		t.Errorf("Expected acme's category only in acme, got %d and %d", len(defaultTenant), len(acmeTenant))
+	}
+}
+
+func TestCachingRepository_PurgeDropsEveryTenant(t *testing.T) {
+	// Arrange
+	repository, backend := newCachedRepository(CacheOptions{Size: 10, TTL: time.Minute})
+	acme := repository.ForTenant("acme")
+	repository.List()
+	acme.List()
+
+	// Act
+	repository.cache.Purge()
+	repository.List()
+	acme.List()
+
+	// Assert
+	if reads := backend.reads.Load(); reads != 4 {
+		t.Errorf("Expected both tenants to be read again, got %d reads", reads)
+	}
+}
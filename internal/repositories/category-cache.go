@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"container/list"
+	"gin-quickstart/internal/entities"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheOptions limitam o cache de categorias: no máximo Size entradas (as
+// menos usadas saem primeiro), cada uma válida por TTL.
+type CacheOptions struct {
+	Size int
+	TTL  time.Duration
+}
+
+// CacheStats são os contadores do cache desde a criação. Evictions conta as
+// entradas descartadas por tamanho ou por TTL, não as invalidadas por
+// escritas.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// cacheKey identifica o List (id 0) ou o FindByID de um tenant.
+type cacheKey struct {
+	tenant string
+	id     uint
+}
+
+type cacheEntry struct {
+	key        cacheKey
+	categories []entities.Category
+	expiresAt  time.Time
+}
+
+// flight é uma busca no repositório em andamento; quem pedir a mesma chave
+// enquanto ela não termina espera pelo mesmo resultado.
+type flight struct {
+	done       chan struct{}
+	categories []entities.Category
+	err        error
+}
+
+// CategoryCache guarda as leituras de NewCachingCategoryRepository. As
+// categorias são guardadas como cópias e devolvidas como cópias novas, como
+// no repositório em memória.
+type CategoryCache struct {
+	options CacheOptions
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[cacheKey]*list.Element
+	recency *list.List
+	flights map[cacheKey]*flight
+	// generations muda a cada invalidação do tenant, e purges a cada Purge:
+	// uma busca que começou antes delas pode ter lido o valor antigo e não é
+	// guardada
+	generations map[string]uint64
+	purges      uint64
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+func NewCategoryCache(options CacheOptions) *CategoryCache {
+	return &CategoryCache{
+		options:     options,
+		now:         time.Now,
+		entries:     make(map[cacheKey]*list.Element),
+		recency:     list.New(),
+		flights:     make(map[cacheKey]*flight),
+		generations: make(map[string]uint64),
+	}
+}
+
+func (cache *CategoryCache) Stats() CacheStats {
+	cache.mutex.Lock()
+	entries := len(cache.entries)
+	cache.mutex.Unlock()
+
+	return CacheStats{
+		Hits:      cache.hits.Load(),
+		Misses:    cache.misses.Load(),
+		Evictions: cache.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// Invalidate descarta o List do tenant e as categorias ids. As buscas em
+// andamento deixam de ser compartilhadas: quem chegar depois busca de novo.
+func (cache *CategoryCache) Invalidate(tenant string, ids ...uint) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generations[tenant]++
+	cache.discard(cacheKey{tenant: tenant})
+	for _, id := range ids {
+		cache.discard(cacheKey{tenant: tenant, id: id})
+	}
+}
+
+// Purge descarta todas as entradas, quando não dá para saber o que mudou
+// (ex: notificações perdidas numa reconexão ao banco).
+func (cache *CategoryCache) Purge() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.purges++
+	cache.entries = make(map[cacheKey]*list.Element)
+	cache.recency.Init()
+	cache.flights = make(map[cacheKey]*flight)
+}
+
+// load devolve a entrada da chave ou a busca com fetch, uma vez só para
+// todos os pedidos simultâneos.
+func (cache *CategoryCache) load(key cacheKey, fetch func() ([]entities.Category, error)) ([]*entities.Category, error) {
+	cache.mutex.Lock()
+
+	if categories, ok := cache.get(key); ok {
+		cache.mutex.Unlock()
+		cache.hits.Add(1)
+		return copies(categories), nil
+	}
+	cache.misses.Add(1)
+
+	if call, ok := cache.flights[key]; ok {
+		cache.mutex.Unlock()
+		<-call.done
+		return copies(call.categories), call.err
+	}
+
+	call := &flight{done: make(chan struct{})}
+	cache.flights[key] = call
+	generation := cache.generation(key.tenant)
+	cache.mutex.Unlock()
+
+	call.categories, call.err = fetch()
+
+	cache.mutex.Lock()
+	if cache.flights[key] == call {
+		delete(cache.flights, key)
+	}
+	if call.err == nil && cache.generation(key.tenant) == generation {
+		cache.put(key, call.categories)
+	}
+	cache.mutex.Unlock()
+	close(call.done)
+
+	return copies(call.categories), call.err
+}
+
+// loadMany é o load de várias categorias do tenant: as que não estão no
+// cache são buscadas juntas com fetch, sem compartilhar a busca.
+func (cache *CategoryCache) loadMany(tenant string, ids []uint, fetch func(ids []uint) ([]*entities.Category, error)) ([]*entities.Category, error) {
+	found := make([]*entities.Category, 0, len(ids))
+	var missing []uint
+
+	cache.mutex.Lock()
+	for _, id := range ids {
+		if categories, ok := cache.get(cacheKey{tenant: tenant, id: id}); ok {
+			found = append(found, copies(categories)...)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	generation := cache.generation(tenant)
+	cache.mutex.Unlock()
+
+	cache.hits.Add(uint64(len(ids) - len(missing)))
+	cache.misses.Add(uint64(len(missing)))
+
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	fetched, err := fetch(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mutex.Lock()
+	if cache.generation(tenant) == generation {
+		for _, category := range fetched {
+			cache.put(cacheKey{tenant: tenant, id: category.ID}, values([]*entities.Category{category}))
+		}
+	}
+	cache.mutex.Unlock()
+
+	return append(found, fetched...), nil
+}
+
+// generation, discard, get e put esperam o mutex travado. Os dois contadores
+// só crescem, então a soma muda quando qualquer um deles muda.
+func (cache *CategoryCache) generation(tenant string) uint64 {
+	return cache.generations[tenant] + cache.purges
+}
+
+func (cache *CategoryCache) discard(key cacheKey) {
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element)
+	}
+	delete(cache.flights, key)
+}
+
+func (cache *CategoryCache) get(key cacheKey) ([]entities.Category, bool) {
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !cache.now().Before(entry.expiresAt) {
+		cache.remove(element)
+		cache.evictions.Add(1)
+		return nil, false
+	}
+
+	cache.recency.MoveToFront(element)
+	return entry.categories, true
+}
+
+func (cache *CategoryCache) put(key cacheKey, categories []entities.Category) {
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element)
+	}
+
+	entry := &cacheEntry{key: key, categories: categories, expiresAt: cache.now().Add(cache.options.TTL)}
+	cache.entries[key] = cache.recency.PushFront(entry)
+
+	for len(cache.entries) > cache.options.Size {
+		cache.remove(cache.recency.Back())
+		cache.evictions.Add(1)
+	}
+}
+
+func (cache *CategoryCache) remove(element *list.Element) {
+	cache.recency.Remove(element)
+	delete(cache.entries, element.Value.(*cacheEntry).key)
+}
+
+func values(categories []*entities.Category) []entities.Category {
+	stored := make([]entities.Category, len(categories))
+	for i, category := range categories {
+		stored[i] = *category
+	}
+	return stored
+}
+
+func copies(categories []entities.Category) []*entities.Category {
+	if categories == nil {
+		return nil
+	}
+
+	copied := make([]*entities.Category, len(categories))
+	for i := range categories {
+		category := categories[i]
+		copied[i] = &category
+	}
+	return copied
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrCategoryNotFound = errors.New("category not found")
+
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionConflictError indica que a categoria foi alterada por outra escrita
+// depois de ter sido lida: a versão esperada não é mais a atual.
+type VersionConflictError struct {
+	ID              uint
+	ExpectedVersion uint
+}
+
+func (err *VersionConflictError) Error() string {
+	return fmt.Sprintf("category %d was modified concurrently (expected version %d)", err.ID, err.ExpectedVersion)
+}
+
+func (err *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+var ErrMessageNotFound = errors.New("outbox message not found")
+
+var ErrDuplicateName = errors.New("duplicate category name")
+
+// DuplicateNameError indica que outra categoria ativa do mesmo tenant já
+// usa o nome. Name fica vazio quando o banco não diz qual foi.
+type DuplicateNameError struct {
+	Name string
+}
+
+func (err *DuplicateNameError) Error() string {
+	if err.Name == "" {
+		return "a category with this name already exists"
+	}
+	return fmt.Sprintf("a category named %q already exists", err.Name)
+}
+
+func (err *DuplicateNameError) Is(target error) bool {
+	return target == ErrDuplicateName
+}
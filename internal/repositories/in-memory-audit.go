@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/audit"
+	"slices"
+)
+
+// inMemoryAudit guarda o log de auditoria junto com as categorias, como a
+// inMemoryOutbox, para que Transaction confirme ou descarte os dois juntos.
+type inMemoryAudit struct {
+	entries []*audit.Entry
+	nextID  uint
+}
+
+func (log inMemoryAudit) clone() inMemoryAudit {
+	return inMemoryAudit{entries: slices.Clone(log.entries), nextID: log.nextID}
+}
+
+func (repository *inMemoryCategoryRepository) AddAudit(entries ...*audit.Entry) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, entry := range entries {
+		entry.ID = repository.auditLog.nextID
+		repository.auditLog.nextID++
+
+		stored := *entry
+		repository.auditLog.entries = append(repository.auditLog.entries, &stored)
+	}
+
+	return nil
+}
+
+// Append é o AddAudit de audit.IStore.
+func (repository *inMemoryCategoryRepository) Append(entries ...*audit.Entry) error {
+	return repository.AddAudit(entries...)
+}
+
+func (repository *inMemoryCategoryRepository) ListByCategory(tenant string, categoryID uint) ([]*audit.Entry, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	entries := make([]*audit.Entry, 0)
+	for _, entry := range repository.auditLog.entries {
+		if entry.TenantID == tenant && entry.CategoryID == categoryID {
+			copied := *entry
+			entries = append(entries, &copied)
+		}
+	}
+
+	return entries, nil
+}
+
+func (repository *inMemoryCategoryRepository) ListByCategories(tenant string, categoryIDs []uint) (map[uint][]*audit.Entry, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	wanted := make(map[uint]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+
+	entries := make(map[uint][]*audit.Entry)
+	for _, entry := range repository.auditLog.entries {
+		if entry.TenantID == tenant && wanted[entry.CategoryID] {
+			copied := *entry
+			entries[entry.CategoryID] = append(entries[entry.CategoryID], &copied)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/outbox"
+	"slices"
+	"time"
+)
+
+// inMemoryOutbox guarda as mensagens junto com as categorias, protegida pelo
+// mesmo mutex, para que Transaction confirme ou descarte as duas juntas.
+// Como as categorias, as mensagens guardadas nunca são alteradas no lugar.
+type inMemoryOutbox struct {
+	messages []*outbox.Message
+	nextID   uint
+}
+
+func (box inMemoryOutbox) clone() inMemoryOutbox {
+	messages := make([]*outbox.Message, len(box.messages))
+	copy(messages, box.messages)
+	return inMemoryOutbox{messages: messages, nextID: box.nextID}
+}
+
+func (repository *inMemoryCategoryRepository) AddEvents(published ...events.Event) error {
+	messages, err := outbox.NewMessages(published...)
+	if err != nil {
+		return err
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, message := range messages {
+		message.ID = repository.outbox.nextID
+		repository.outbox.nextID++
+		repository.outbox.messages = append(repository.outbox.messages, message)
+	}
+
+	return nil
+}
+
+func (repository *inMemoryCategoryRepository) ClaimPending(limit int, now time.Time, lease time.Duration) ([]*outbox.Message, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	claimed := make([]*outbox.Message, 0)
+
+	for i, message := range repository.outbox.messages {
+		if len(claimed) == limit {
+			break
+		}
+
+		if message.DispatchedAt != nil || message.AbandonedAt != nil || message.NextAttemptAt.After(now) {
+			continue
+		}
+
+		stored := *message
+		stored.NextAttemptAt = now.Add(lease)
+		repository.outbox.messages[i] = &stored
+
+		copied := stored
+		claimed = append(claimed, &copied)
+	}
+
+	return claimed, nil
+}
+
+func (repository *inMemoryCategoryRepository) MarkDispatched(id uint, at time.Time) error {
+	return repository.updateMessage(id, func(message *outbox.Message) {
+		message.Attempts++
+		message.DispatchedAt = &at
+		message.LastError = ""
+	})
+}
+
+func (repository *inMemoryCategoryRepository) MarkFailed(id uint, lastError string, nextAttemptAt *time.Time) error {
+	return repository.updateMessage(id, func(message *outbox.Message) {
+		message.Attempts++
+		message.LastError = lastError
+
+		if nextAttemptAt == nil {
+			abandonedAt := time.Now()
+			message.AbandonedAt = &abandonedAt
+			return
+		}
+		message.NextAttemptAt = *nextAttemptAt
+	})
+}
+
+func (repository *inMemoryCategoryRepository) PruneDispatched(before time.Time) (int, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	count := len(repository.outbox.messages)
+	repository.outbox.messages = slices.DeleteFunc(repository.outbox.messages, func(message *outbox.Message) bool {
+		return message.DispatchedAt != nil && message.DispatchedAt.Before(before)
+	})
+
+	return count - len(repository.outbox.messages), nil
+}
+
+// OutboxMessages devolve uma cópia das mensagens guardadas (pendentes,
+// abandonadas e entregues ainda não apagadas), para inspeção.
+func (repository *inMemoryCategoryRepository) OutboxMessages() []*outbox.Message {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	messages := make([]*outbox.Message, len(repository.outbox.messages))
+	for i, message := range repository.outbox.messages {
+		copied := *message
+		messages[i] = &copied
+	}
+	return messages
+}
+
+func (repository *inMemoryCategoryRepository) updateMessage(id uint, update func(message *outbox.Message)) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for i, message := range repository.outbox.messages {
+		if message.ID == id {
+			stored := *message
+			update(&stored)
+			repository.outbox.messages[i] = &stored
+			return nil
+		}
+	}
+
+	return ErrMessageNotFound
+}
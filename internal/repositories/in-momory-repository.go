@@ -1,22 +1,330 @@
 package repositories
 
-import "gin-quickstart/internal/entities"
+import (
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/tenancy"
+	"slices"
+	"sync"
+	"time"
+)
 
+// inMemoryCatalog é o "banco" compartilhado pelos tenants: uma partição de
+// categorias por tenant, com IDs, outbox e auditoria comuns a todos (como a
+// sequence e as tabelas outbox_messages e category_audit no Postgres).
+type inMemoryCatalog struct {
+	mutex      sync.RWMutex
+	partitions map[string][]*entities.Category
+	nextID     uint
+	outbox     inMemoryOutbox
+	auditLog   inMemoryAudit
+}
+
+// As categorias são guardadas e devolvidas como cópias, assim alterações
+// feitas por quem chamou só chegam ao "banco" via Save/Update. Cada
+// repositório só enxerga a partição do seu tenant.
 type inMemoryCategoryRepository struct {
-	db []*entities.Category
+	*inMemoryCatalog
+	tenant string
 }
 
 func NewInMemotyCategoryRepository() *inMemoryCategoryRepository {
 	return &inMemoryCategoryRepository{
-		db: make([]*entities.Category, 0),
+		inMemoryCatalog: &inMemoryCatalog{
+			partitions: make(map[string][]*entities.Category),
+			nextID:     1,
+			outbox:     inMemoryOutbox{nextID: 1},
+			auditLog:   inMemoryAudit{nextID: 1},
+		},
+		tenant: tenancy.Default,
 	}
 }
 
+// ForTenant compartilha o catálogo (e o lock) com o repositório original.
+func (repository *inMemoryCategoryRepository) ForTenant(tenant string) ICategoryRepository {
+	return &inMemoryCategoryRepository{inMemoryCatalog: repository.inMemoryCatalog, tenant: tenant}
+}
+
 func (repository *inMemoryCategoryRepository) Save(category *entities.Category) error {
-	repository.db = append(repository.db, category)
+	return repository.SaveMany([]*entities.Category{category})
+}
+
+func (repository *inMemoryCategoryRepository) SaveMany(categories []*entities.Category) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	// Valida o lote inteiro antes de gravar, como o INSERT de várias linhas
+	names := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if names[category.Name] || repository.nameTaken(category.Name, 0) {
+			return &DuplicateNameError{Name: category.Name}
+		}
+		names[category.Name] = true
+	}
+
+	for _, category := range categories {
+		category.ID = repository.nextID
+		category.TenantID = repository.tenant
+		category.Version = 1
+		repository.nextID++
+
+		stored := *category
+		repository.partitions[repository.tenant] = append(repository.partitions[repository.tenant], &stored)
+	}
+
 	return nil
 }
 
 func (repository *inMemoryCategoryRepository) List() ([]*entities.Category, error) {
-	return repository.db, nil
-}
\ No newline at end of file
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	partition := repository.partitions[repository.tenant]
+	categories := make([]*entities.Category, 0, len(partition))
+	for _, category := range partition {
+		if category.DeletedAt != nil {
+			continue
+		}
+		copied := *category
+		categories = append(categories, &copied)
+	}
+
+	return categories, nil
+}
+
+func (repository *inMemoryCategoryRepository) ListPage(afterID uint, limit int) ([]*entities.Category, int, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	total := 0
+	categories := make([]*entities.Category, 0, limit)
+	for _, category := range repository.partitions[repository.tenant] {
+		if category.DeletedAt != nil {
+			continue
+		}
+		total++
+		if category.ID > afterID && len(categories) < limit {
+			copied := *category
+			categories = append(categories, &copied)
+		}
+	}
+
+	return categories, total, nil
+}
+
+func (repository *inMemoryCategoryRepository) Stream(fn func(category *entities.Category) error) error {
+	// Copia só os ponteiros: as categorias guardadas nunca são alteradas no
+	// lugar (Update troca o ponteiro), então é seguro lê-las sem o lock.
+	repository.mutex.RLock()
+	snapshot := make([]*entities.Category, len(repository.partitions[repository.tenant]))
+	copy(snapshot, repository.partitions[repository.tenant])
+	repository.mutex.RUnlock()
+
+	for _, category := range snapshot {
+		if category.DeletedAt != nil {
+			continue
+		}
+		copied := *category
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *inMemoryCategoryRepository) FindByID(id uint) (*entities.Category, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	index := repository.activeIndexOf(id)
+	if index == -1 {
+		return nil, ErrCategoryNotFound
+	}
+
+	copied := *repository.partitions[repository.tenant][index]
+	return &copied, nil
+}
+
+func (repository *inMemoryCategoryRepository) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	wanted := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	categories := make([]*entities.Category, 0, len(wanted))
+	for _, category := range repository.partitions[repository.tenant] {
+		if category.DeletedAt == nil && wanted[category.ID] {
+			copied := *category
+			categories = append(categories, &copied)
+		}
+	}
+
+	return categories, nil
+}
+
+func (repository *inMemoryCategoryRepository) FindChildren(parentIDs []uint) ([]*entities.Category, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	parents := make(map[uint]bool, len(parentIDs))
+	for _, id := range parentIDs {
+		parents[id] = true
+	}
+
+	categories := []*entities.Category{}
+	for _, category := range repository.partitions[repository.tenant] {
+		if category.DeletedAt == nil && category.ParentID != nil && parents[*category.ParentID] {
+			copied := *category
+			categories = append(categories, &copied)
+		}
+	}
+
+	return categories, nil
+}
+
+func (repository *inMemoryCategoryRepository) Update(category *entities.Category) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	index := repository.activeIndexOf(category.ID)
+	if index == -1 {
+		return ErrCategoryNotFound
+	}
+
+	partition := repository.partitions[repository.tenant]
+
+	// Compare-and-swap: o lock garante que ninguém escreve entre a comparação e a troca
+	if partition[index].Version != category.Version {
+		return &VersionConflictError{ID: category.ID, ExpectedVersion: category.Version}
+	}
+
+	if repository.nameTaken(category.Name, category.ID) {
+		return &DuplicateNameError{Name: category.Name}
+	}
+
+	category.TenantID = repository.tenant
+	category.Version++
+	stored := *category
+	partition[index] = &stored
+	return nil
+}
+
+func (repository *inMemoryCategoryRepository) Delete(id uint, expectedVersion uint) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	index := repository.activeIndexOf(id)
+	if index == -1 {
+		return ErrCategoryNotFound
+	}
+
+	partition := repository.partitions[repository.tenant]
+
+	if partition[index].Version != expectedVersion {
+		return &VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+	}
+
+	deletedAt := time.Now()
+	stored := *partition[index]
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	partition[index] = &stored
+	return nil
+}
+
+func (repository *inMemoryCategoryRepository) Restore(id uint) (*entities.Category, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	partition := repository.partitions[repository.tenant]
+
+	index := repository.indexOf(id)
+	if index == -1 || partition[index].DeletedAt == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	// Outra categoria pode ter ficado com o nome enquanto esta estava removida
+	if repository.nameTaken(partition[index].Name, id) {
+		return nil, &DuplicateNameError{Name: partition[index].Name}
+	}
+
+	stored := *partition[index]
+	stored.DeletedAt = nil
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	partition[index] = &stored
+
+	copied := stored
+	return &copied, nil
+}
+
+// Transaction roda fn sobre uma cópia do estado (inclusive da outbox e da
+// auditoria) e só
+// a publica se fn não retornar erro. O lock fica com a transação até o fim, então as demais
+// escritas esperam, como aconteceria com locks de linha no Postgres.
+func (repository *inMemoryCategoryRepository) Transaction(fn func(repository ICategoryRepository) error) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	transaction := &inMemoryCategoryRepository{
+		inMemoryCatalog: &inMemoryCatalog{
+			partitions: make(map[string][]*entities.Category, len(repository.partitions)),
+			nextID:     repository.nextID,
+			outbox:     repository.outbox.clone(),
+			auditLog:   repository.auditLog.clone(),
+		},
+		tenant: repository.tenant,
+	}
+	for tenant, partition := range repository.partitions {
+		transaction.partitions[tenant] = slices.Clone(partition)
+	}
+
+	if err := fn(transaction); err != nil {
+		return err
+	}
+
+	repository.partitions = transaction.partitions
+	repository.nextID = transaction.nextID
+	repository.outbox = transaction.outbox
+	repository.auditLog = transaction.auditLog
+	return nil
+}
+
+// LockHierarchy não precisa fazer nada: Transaction já segura o mutex até o
+// fim.
+func (repository *inMemoryCategoryRepository) LockHierarchy() error {
+	return nil
+}
+
+// nameTaken diz se outra categoria ativa do tenant (que não exceptID) já
+// usa o nome.
+func (repository *inMemoryCategoryRepository) nameTaken(name string, exceptID uint) bool {
+	for _, category := range repository.partitions[repository.tenant] {
+		if category.DeletedAt == nil && category.ID != exceptID && category.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// activeIndexOf é o indexOf que ignora categorias removidas.
+func (repository *inMemoryCategoryRepository) activeIndexOf(id uint) int {
+	index := repository.indexOf(id)
+	if index != -1 && repository.partitions[repository.tenant][index].DeletedAt != nil {
+		return -1
+	}
+	return index
+}
+
+// indexOf procura só na partição do tenant: IDs de outros tenants não existem.
+func (repository *inMemoryCategoryRepository) indexOf(id uint) int {
+	for i, category := range repository.partitions[repository.tenant] {
+		if category.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,8 +1,64 @@
 package repositories
 
-import "gin-quickstart/internal/entities"
+import (
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+)
 
+// Update e Delete são condicionais: só são aplicados se a versão guardada
+// ainda for a versão esperada (category.Version / expectedVersion), caso
+// contrário retornam *VersionConflictError. Toda escrita incrementa a versão.
+//
+// Delete é lógico: a categoria deixa de aparecer em List, Stream e FindByID
+// mas continua guardada, e Restore a traz de volta (ErrCategoryNotFound se
+// ela não estiver removida).
+//
+// FindByIDs busca várias categorias de uma vez, em ordem de ID; as que não
+// existem (ou estão removidas) ficam de fora, sem erro. FindChildren busca
+// as categorias ativas cujo pai é um de parentIDs, também em ordem de ID.
+//
+// ListPage é a paginação por keyset de List: devolve até limit categorias
+// com ID maior que afterID, em ordem de ID, e o total de categorias ativas.
+//
+// Stream chama fn para cada categoria, em ordem de ID, sem carregar todas
+// em memória; se fn retornar erro a iteração para e o erro é devolvido.
+//
+// Transaction executa fn com um repositório transacional: se fn retornar
+// erro nenhuma das escritas feitas através dele é aplicada. LockHierarchy,
+// chamado dentro dela, serializa até o fim da transação as mudanças de pai
+// do tenant, para que duas não criem um ciclo juntas; fora de Transaction
+// não tem efeito.
+//
+// ForTenant devolve o repositório restrito às categorias do tenant: todas
+// as consultas e escritas dele só enxergam esse tenant, e Save/SaveMany
+// gravam nele. O repositório criado pelos construtores é o do
+// tenancy.Default. Nomes são únicos por tenant entre as categorias ativas;
+// Save, SaveMany, Update e Restore retornam *DuplicateNameError.
+//
+// AddEvents grava os eventos de domínio na outbox do próprio repositório;
+// chamado dentro de Transaction, os eventos só existem se a alteração for
+// confirmada. Os repositórios também implementam outbox.IStore.
+//
+// AddAudit grava entradas no log de auditoria do próprio repositório, do
+// mesmo jeito: dentro de Transaction elas só existem se a alteração for
+// confirmada, e uma alteração confirmada sempre tem a sua entrada. Os
+// repositórios também implementam audit.IStore, que lê esse log.
 type ICategoryRepository interface {
 	Save(category *entities.Category) error
+	SaveMany(categories []*entities.Category) error
 	List() ([]*entities.Category, error)
-}
\ No newline at end of file
+	ListPage(afterID uint, limit int) ([]*entities.Category, int, error)
+	Stream(fn func(category *entities.Category) error) error
+	FindByID(id uint) (*entities.Category, error)
+	FindByIDs(ids []uint) ([]*entities.Category, error)
+	FindChildren(parentIDs []uint) ([]*entities.Category, error)
+	Update(category *entities.Category) error
+	Delete(id uint, expectedVersion uint) error
+	Restore(id uint) (*entities.Category, error)
+	Transaction(fn func(repository ICategoryRepository) error) error
+	LockHierarchy() error
+	ForTenant(tenant string) ICategoryRepository
+	AddEvents(published ...events.Event) error
+	AddAudit(entries ...*audit.Entry) error
+}
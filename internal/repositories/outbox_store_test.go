@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/outbox"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// outboxStore é o repositório visto pela outbox: AddEvents grava, o resto
+// é o outbox.IStore usado pelo relay.
+type outboxStore interface {
+	ICategoryRepository
+	outbox.IStore
+}
+
+// forEachOutboxStore roda test contra o repositório em memória e, com
+// TEST_DATABASE_DSN, contra o Postgres desse banco (a outbox dele é
+// esvaziada antes).
+func forEachOutboxStore(t *testing.T, test func(t *testing.T, store outboxStore)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewInMemotyCategoryRepository())
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		db := testDatabase(t)
+		if err := db.AutoMigrate(&outbox.Message{}); err != nil {
+			t.Fatalf("Failed to migrate: %v", err)
+		}
+		if err := db.Where("1 = 1").Delete(&outbox.Message{}).Error; err != nil {
+			t.Fatalf("Failed to clear the outbox: %v", err)
+		}
+
+		test(t, NewPostgresCategoryRepository(db))
+	})
+}
+
+// testDatabase conecta ao Postgres de TEST_DATABASE_DSN, ou pula o teste.
+func testDatabase(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	return db
+}
+
+func TestOutboxStore_KeepsDispatchedMessagesUntilPruned(t *testing.T) {
+	forEachOutboxStore(t, func(t *testing.T, store outboxStore) {
+		// Arrange
+		store.AddEvents(entities.CategoryCreated{ID: 1, Name: "Electronics"}, entities.CategoryCreated{ID: 2, Name: "Books"})
+		// Arredondado para cima: o Postgres guarda microssegundos
+		now := time.Now().Truncate(time.Microsecond).Add(time.Microsecond)
+		claimed, _ := store.ClaimPending(10, now, time.Minute)
+		if len(claimed) != 2 {
+			t.Fatalf("Expected 2 pending messages, got %d", len(claimed))
+		}
+
+		// Act
+		dispatchErr := store.MarkDispatched(claimed[0].ID, now)
+		store.MarkFailed(claimed[1].ID, "boom", nil)
+		keptCount, _ := store.PruneDispatched(now)
+		prunedCount, pruneErr := store.PruneDispatched(now.Add(time.Second))
+		againCount, _ := store.PruneDispatched(now.Add(time.Hour))
+		pending, _ := store.ClaimPending(10, now.Add(time.Hour), time.Minute)
+
+		// Assert
+		if dispatchErr != nil || pruneErr != nil {
+			t.Fatalf("Expected no errors, got %v and %v", dispatchErr, pruneErr)
+		}
+
+		if keptCount != 0 || prunedCount != 1 || againCount != 0 {
+			t.Errorf("Expected the dispatched message kept until the retention and then pruned once, got %d, %d and %d", keptCount, prunedCount, againCount)
+		}
+
+		if len(pending) != 0 {
+			t.Errorf("Expected neither the dispatched nor the abandoned message to be claimed again, got %d", len(pending))
+		}
+	})
+}
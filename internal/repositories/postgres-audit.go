@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/audit"
+)
+
+// O log de auditoria é a tabela category_audit do audit.NewPostgresStore;
+// dentro de Transaction repository.db é a transação, então a entrada é
+// gravada junto com a alteração.
+func (repository *postgresCategoryRepository) AddAudit(entries ...*audit.Entry) error {
+	return audit.NewPostgresStore(repository.db).Append(entries...)
+}
+
+// Append é o AddAudit de audit.IStore.
+func (repository *postgresCategoryRepository) Append(entries ...*audit.Entry) error {
+	return repository.AddAudit(entries...)
+}
+
+func (repository *postgresCategoryRepository) ListByCategory(tenant string, categoryID uint) ([]*audit.Entry, error) {
+	return audit.NewPostgresStore(repository.db).ListByCategory(tenant, categoryID)
+}
+
+func (repository *postgresCategoryRepository) ListByCategories(tenant string, categoryIDs []uint) (map[uint][]*audit.Entry, error) {
+	return audit.NewPostgresStore(repository.db).ListByCategories(tenant, categoryIDs)
+}
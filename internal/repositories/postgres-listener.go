@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/outbox"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// ListenerSink recebe os eventos do CategoryListener; é o stream SSE.
+type ListenerSink interface {
+	// BroadcastWithID entrega o evento com o ID da sua mensagem na outbox,
+	// o mesmo em todas as réplicas.
+	BroadcastWithID(ctx context.Context, id uint64, event events.Event) error
+	// Reset avisa que eventos podem ter se perdido enquanto o LISTEN estava
+	// fora.
+	Reset()
+}
+
+// CategoryListener escuta o CategoryChangesChannel numa conexão própria (o
+// pool do gorm não segura um LISTEN) e, a cada notificação, invalida o
+// cache desta réplica e entrega os eventos gravados ao sink. Assim as
+// escritas feitas em qualquer réplica, ou pelo catctl, chegam a todas.
+type CategoryListener struct {
+	dsn       string
+	registry  *events.Registry
+	cache     *CategoryCache
+	sink      ListenerSink
+	listening bool
+	messages  func(ids []uint) ([]*outbox.Message, error)
+}
+
+// NewCategoryListener aceita cache nil, quando o cache está desligado.
+func NewCategoryListener(dsn string, db *gorm.DB, registry *events.Registry, cache *CategoryCache, sink ListenerSink) *CategoryListener {
+	return &CategoryListener{
+		dsn:      dsn,
+		registry: registry,
+		cache:    cache,
+		sink:     sink,
+		messages: func(ids []uint) ([]*outbox.Message, error) {
+			var messages []*outbox.Message
+			err := db.Where("id IN ?", ids).Order("id").Find(&messages).Error
+			return messages, err
+		},
+	}
+}
+
+// Run escuta até ctx ser cancelado e, se a conexão cair, reconecta depois
+// de interval. O que foi notificado enquanto isso se perde: a cada LISTEN o
+// cache é esvaziado e, a partir do segundo, o sink recebe Reset para que os
+// clientes SSE recarreguem o estado.
+func (listener *CategoryListener) Run(ctx context.Context, interval time.Duration) {
+	for {
+		err := listener.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("category listener: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (listener *CategoryListener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, listener.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{CategoryChangesChannel}.Sanitize()); err != nil {
+		return err
+	}
+
+	listener.listened()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := listener.handle(ctx, notification.Payload); err != nil {
+			log.Printf("category listener: %v", err)
+		}
+	}
+}
+
+// listened roda depois de cada LISTEN bem-sucedido. Só Run chama listen,
+// então listening não precisa de lock.
+func (listener *CategoryListener) listened() {
+	if listener.cache != nil {
+		listener.cache.Purge()
+	}
+
+	if listener.listening {
+		listener.sink.Reset()
+	}
+	listener.listening = true
+}
+
+func (listener *CategoryListener) handle(ctx context.Context, payload string) error {
+	var change CategoryChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return fmt.Errorf("invalid notification %q: %w", payload, err)
+	}
+
+	if listener.cache != nil {
+		listener.cache.Invalidate(change.Tenant, change.IDs...)
+	}
+
+	if len(change.Messages) == 0 {
+		return nil
+	}
+
+	messages, err := listener.messages(change.Messages)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, message := range messages {
+		event, err := listener.registry.Decode(message.EventName, message.Payload)
+		if err == nil {
+			err = listener.sink.BroadcastWithID(ctx, uint64(message.ID), event)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("outbox message %d: %w", message.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"fmt"
+	"gin-quickstart/internal/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeduplicateCategoryNames prepara bancos anteriores ao índice único
+// idx_categories_tenant_name: entre as categorias ativas de um tenant com o
+// mesmo nome, a de menor ID fica com ele e as demais passam a se chamar
+// "nome (id)", com a versão incrementada. Se esse nome também já estiver em
+// uso no tenant, recebe mais um sufixo, "nome (id) (2)", "nome (id) (3)" e
+// assim por diante, até um livre. Tem que rodar antes do AutoMigrate, que
+// falharia ao criar o índice; depois dele não encontra mais nada e devolve 0.
+func DeduplicateCategoryNames(db *gorm.DB) (int64, error) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entities.Category{}) {
+		return 0, nil
+	}
+
+	// As colunas que ainda não existem (bancos de antes do multi-tenant, do
+	// controle de versão ou da remoção lógica) são criadas depois, pelo
+	// AutoMigrate: nesses bancos todas as categorias são do tenant padrão,
+	// ativas e sem versão
+	tenant, sameTenant, active, duplicateActive, bumpVersion := "''", "TRUE", "TRUE", "TRUE", ""
+	if migrator.HasColumn(&entities.Category{}, "TenantID") {
+		tenant, sameTenant = "categories.tenant_id", "duplicate.tenant_id = categories.tenant_id"
+	}
+	if migrator.HasColumn(&entities.Category{}, "DeletedAt") {
+		active, duplicateActive = "categories.deleted_at IS NULL", "duplicate.deleted_at IS NULL"
+	}
+	if migrator.HasColumn(&entities.Category{}, "Version") {
+		bumpVersion = ", version = categories.version + 1"
+	}
+
+	var renamed int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var duplicates []struct {
+			ID     uint
+			Tenant string
+			Name   string
+		}
+
+		err := tx.Raw(`
+			SELECT categories.id, ` + tenant + ` AS tenant, categories.name
+			FROM categories
+			WHERE ` + active + `
+				AND EXISTS (
+					SELECT 1 FROM categories AS duplicate
+					WHERE ` + duplicateActive + `
+						AND ` + sameTenant + `
+						AND duplicate.name = categories.name
+						AND duplicate.id < categories.id
+				)
+			ORDER BY categories.id`).Scan(&duplicates).Error
+		if err != nil {
+			return err
+		}
+
+		// Cada nome novo é conferido contra o banco, que já tem os
+		// renomeados antes dele
+		taken := func(tenantID, name string) (bool, error) {
+			var count int64
+			err := tx.Raw(`
+				SELECT COUNT(*) FROM categories
+				WHERE `+active+` AND `+tenant+` = ? AND categories.name = ?`, tenantID, name).Scan(&count).Error
+			return count > 0, err
+		}
+
+		now := time.Now()
+		for _, duplicate := range duplicates {
+			name := fmt.Sprintf("%s (%d)", duplicate.Name, duplicate.ID)
+			for suffix := 2; ; suffix++ {
+				inUse, err := taken(duplicate.Tenant, name)
+				if err != nil {
+					return err
+				}
+				if !inUse {
+					break
+				}
+				name = fmt.Sprintf("%s (%d) (%d)", duplicate.Name, duplicate.ID, suffix)
+			}
+
+			err := tx.Exec(`UPDATE categories SET name = ?, updated_at = ?`+bumpVersion+` WHERE categories.id = ?`, name, now, duplicate.ID).Error
+			if err != nil {
+				return err
+			}
+			renamed++
+		}
+
+		return nil
+	})
+
+	return renamed, err
+}
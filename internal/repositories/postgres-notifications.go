@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// CategoryChangesChannel é o canal do NOTIFY enviado a cada escrita de
+// categorias no Postgres, escutado pelo CategoryListener de cada réplica.
+const CategoryChangesChannel = "category_changes"
+
+// notificationBatchSize limita os IDs por notificação: o payload do NOTIFY
+// tem no máximo 8000 bytes.
+const notificationBatchSize = 500
+
+// CategoryChange é o payload das notificações: o tenant alterado, as
+// categorias alteradas (nenhuma quando só foram criadas) e as mensagens da
+// outbox com os eventos gravados.
+type CategoryChange struct {
+	Tenant   string `json:"tenant"`
+	IDs      []uint `json:"ids,omitempty"`
+	Messages []uint `json:"messages,omitempty"`
+}
+
+// write roda fn e o NOTIFY na mesma transação (ou savepoint, dentro de
+// Transaction): as réplicas só são avisadas depois do commit, e só se ele
+// acontecer.
+func (repository *postgresCategoryRepository) write(change CategoryChange, fn func(repository *postgresCategoryRepository) error) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		transaction := &postgresCategoryRepository{db: tx, tenant: repository.tenant}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+		return transaction.notify(change)
+	})
+}
+
+func (repository *postgresCategoryRepository) notify(change CategoryChange) error {
+	for {
+		batch := change
+		if len(batch.Messages) > notificationBatchSize {
+			batch.Messages = batch.Messages[:notificationBatchSize]
+		}
+
+		payload, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+
+		if err := repository.db.Exec("SELECT pg_notify(?, ?)", CategoryChangesChannel, string(payload)).Error; err != nil {
+			return err
+		}
+
+		change.Messages = change.Messages[len(batch.Messages):]
+		if len(change.Messages) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/outbox"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (repository *postgresCategoryRepository) AddEvents(published ...events.Event) error {
+	if len(published) == 0 {
+		return nil
+	}
+
+	messages, err := outbox.NewMessages(published...)
+	if err != nil {
+		return err
+	}
+
+	// Os IDs das mensagens, que vão na notificação, só existem depois do INSERT
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(messages).Error; err != nil {
+			return err
+		}
+
+		change := CategoryChange{Tenant: repository.tenant}
+		for _, message := range messages {
+			change.Messages = append(change.Messages, message.ID)
+		}
+
+		return (&postgresCategoryRepository{db: tx, tenant: repository.tenant}).notify(change)
+	})
+}
+
+// ClaimPending usa FOR UPDATE SKIP LOCKED, então vários relays (uma por
+// instância da API) podem rodar ao mesmo tempo sem entregar a mesma
+// mensagem em paralelo.
+func (repository *postgresCategoryRepository) ClaimPending(limit int, now time.Time, lease time.Duration) ([]*outbox.Message, error) {
+	var messages []*outbox.Message
+
+	err := repository.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND abandoned_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		return tx.Model(&outbox.Message{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (repository *postgresCategoryRepository) MarkDispatched(id uint, at time.Time) error {
+	return repository.updateMessage(id, map[string]any{
+		"attempts":      gorm.Expr("attempts + 1"),
+		"dispatched_at": at,
+		"last_error":    "",
+	})
+}
+
+func (repository *postgresCategoryRepository) MarkFailed(id uint, lastError string, nextAttemptAt *time.Time) error {
+	updates := map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+
+	if nextAttemptAt == nil {
+		updates["abandoned_at"] = time.Now()
+	} else {
+		updates["next_attempt_at"] = *nextAttemptAt
+	}
+
+	return repository.updateMessage(id, updates)
+}
+
+func (repository *postgresCategoryRepository) PruneDispatched(before time.Time) (int, error) {
+	result := repository.db.Where("dispatched_at < ?", before).Delete(&outbox.Message{})
+	return int(result.RowsAffected), result.Error
+}
+
+func (repository *postgresCategoryRepository) updateMessage(id uint, updates map[string]any) error {
+	result := repository.db.Model(&outbox.Message{}).Where("id = ?", id).Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMessageNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,336 @@
+package repositories
+
+import (
+	"errors"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/tenancy"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const saveManyBatchSize = 500
+
+// notDeleted filtra as categorias removidas (Delete é lógico).
+const notDeleted = "deleted_at IS NULL"
+
+// byTenant é o escopo GORM aplicado a toda consulta de categorias.
+func byTenant(tenant string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id = ?", tenant)
+	}
+}
+
+// O db precisa ter TranslateError ligado para que a violação do índice
+// único de nome chegue como gorm.ErrDuplicatedKey.
+type postgresCategoryRepository struct {
+	db     *gorm.DB
+	tenant string
+}
+
+func NewPostgresCategoryRepository(db *gorm.DB) *postgresCategoryRepository {
+	return &postgresCategoryRepository{
+		db:     db,
+		tenant: tenancy.Default,
+	}
+}
+
+func (repository *postgresCategoryRepository) ForTenant(tenant string) ICategoryRepository {
+	return &postgresCategoryRepository{db: repository.db, tenant: tenant}
+}
+
+// categories é o ponto de partida de toda consulta: a tabela de categorias
+// já restrita ao tenant.
+func (repository *postgresCategoryRepository) categories() *gorm.DB {
+	return repository.db.Model(&entities.Category{}).Scopes(byTenant(repository.tenant))
+}
+
+func (repository *postgresCategoryRepository) Save(category *entities.Category) error {
+	category.TenantID = repository.tenant
+	category.Version = 1
+
+	return repository.write(CategoryChange{Tenant: repository.tenant}, func(transaction *postgresCategoryRepository) error {
+		return duplicateName(transaction.db.Create(category).Error, category.Name)
+	})
+}
+
+// SaveMany usa INSERTs de várias linhas, em lotes de saveManyBatchSize.
+func (repository *postgresCategoryRepository) SaveMany(categories []*entities.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	// Valida o lote antes de gravar, como o repositório em memória
+	names := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if names[category.Name] {
+			return &DuplicateNameError{Name: category.Name}
+		}
+		names[category.Name] = true
+
+		category.TenantID = repository.tenant
+		category.Version = 1
+	}
+
+	err := repository.write(CategoryChange{Tenant: repository.tenant}, func(transaction *postgresCategoryRepository) error {
+		return duplicateName(transaction.db.CreateInBatches(categories, saveManyBatchSize).Error, "")
+	})
+
+	if errors.Is(err, ErrDuplicateName) {
+		return repository.duplicateNameIn(categories, err)
+	}
+
+	return err
+}
+
+func (repository *postgresCategoryRepository) List() ([]*entities.Category, error) {
+	categories := make([]*entities.Category, 0)
+
+	err := repository.categories().Where(notDeleted).Order("id").Find(&categories).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (repository *postgresCategoryRepository) ListPage(afterID uint, limit int) ([]*entities.Category, int, error) {
+	categories := make([]*entities.Category, 0, limit)
+
+	var total int64
+	if err := repository.categories().Where(notDeleted).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := repository.categories().Where(notDeleted).Where("id > ?", afterID).Order("id").Limit(limit).Find(&categories).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return categories, int(total), nil
+}
+
+func (repository *postgresCategoryRepository) Stream(fn func(category *entities.Category) error) error {
+	rows, err := repository.categories().Where(notDeleted).Order("id").Rows()
+
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category entities.Category
+
+		if err := repository.db.ScanRows(rows, &category); err != nil {
+			return err
+		}
+
+		if err := fn(&category); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (repository *postgresCategoryRepository) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	categories := make([]*entities.Category, 0, len(ids))
+
+	if len(ids) == 0 {
+		return categories, nil
+	}
+
+	err := repository.categories().Where(notDeleted).Where("id IN ?", ids).Order("id").Find(&categories).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (repository *postgresCategoryRepository) FindChildren(parentIDs []uint) ([]*entities.Category, error) {
+	categories := []*entities.Category{}
+
+	if len(parentIDs) == 0 {
+		return categories, nil
+	}
+
+	err := repository.categories().Where(notDeleted).Where("parent_id IN ?", parentIDs).Order("id").Find(&categories).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (repository *postgresCategoryRepository) FindByID(id uint) (*entities.Category, error) {
+	var category entities.Category
+
+	err := repository.categories().Where(notDeleted).First(&category, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (repository *postgresCategoryRepository) Update(category *entities.Category) error {
+	err := repository.write(CategoryChange{Tenant: repository.tenant, IDs: []uint{category.ID}}, func(transaction *postgresCategoryRepository) error {
+		result := transaction.categories().
+			Where("id = ? AND version = ?", category.ID, category.Version).
+			Where(notDeleted).
+			Updates(map[string]any{
+				"name":       category.Name,
+				"parent_id":  category.ParentID,
+				"updated_at": category.UpdatedAt,
+				"version":    category.Version + 1,
+			})
+
+		if result.Error != nil {
+			return duplicateName(result.Error, category.Name)
+		}
+
+		if result.RowsAffected == 0 {
+			return transaction.missOrConflict(category.ID, category.Version)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	category.Version++
+	return nil
+}
+
+func (repository *postgresCategoryRepository) Delete(id uint, expectedVersion uint) error {
+	return repository.write(CategoryChange{Tenant: repository.tenant, IDs: []uint{id}}, func(transaction *postgresCategoryRepository) error {
+		result := transaction.categories().
+			Where("id = ? AND version = ?", id, expectedVersion).
+			Where(notDeleted).
+			Updates(map[string]any{
+				"deleted_at": time.Now(),
+				"version":    gorm.Expr("version + 1"),
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return transaction.missOrConflict(id, expectedVersion)
+		}
+
+		return nil
+	})
+}
+
+func (repository *postgresCategoryRepository) Restore(id uint) (*entities.Category, error) {
+	var restored *entities.Category
+
+	err := repository.write(CategoryChange{Tenant: repository.tenant, IDs: []uint{id}}, func(transaction *postgresCategoryRepository) error {
+		// O nome é lido antes: depois da violação do índice a transação não
+		// aceita mais consultas
+		var names []string
+		if err := transaction.categories().Where("id = ? AND deleted_at IS NOT NULL", id).Pluck("name", &names).Error; err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return ErrCategoryNotFound
+		}
+
+		result := transaction.categories().
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"updated_at": time.Now(),
+				"version":    gorm.Expr("version + 1"),
+			})
+
+		if result.Error != nil {
+			return duplicateName(result.Error, names[0])
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCategoryNotFound
+		}
+
+		var err error
+		restored, err = transaction.FindByID(id)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+func (repository *postgresCategoryRepository) Transaction(fn func(repository ICategoryRepository) error) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&postgresCategoryRepository{db: tx, tenant: repository.tenant})
+	})
+}
+
+// LockHierarchy usa um advisory lock por tenant, liberado no commit ou no
+// rollback. Em READ COMMITTED cada consulta seguinte já enxerga o que a
+// transação que segurava o lock gravou.
+func (repository *postgresCategoryRepository) LockHierarchy() error {
+	return repository.db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "category_hierarchy:"+repository.tenant).Error
+}
+
+// missOrConflict descobre por que uma escrita condicional não afetou linhas:
+// a categoria não existe mais ou está em outra versão.
+func (repository *postgresCategoryRepository) missOrConflict(id uint, expectedVersion uint) error {
+	var count int64
+
+	err := repository.categories().Where("id = ?", id).Where(notDeleted).Count(&count).Error
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return &VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+}
+
+// duplicateNameIn descobre qual nome do lote já estava em uso, já que o
+// erro do INSERT não diz; se não descobrir devolve err.
+func (repository *postgresCategoryRepository) duplicateNameIn(categories []*entities.Category, err error) error {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+	}
+
+	var taken []string
+	if repository.categories().Where(notDeleted).Where("name IN ?", names).Order("id").Limit(1).Pluck("name", &taken).Error != nil || len(taken) == 0 {
+		return err
+	}
+
+	return &DuplicateNameError{Name: taken[0]}
+}
+
+// duplicateName traduz a violação do índice único (tenant_id, name). Em
+// escritas de várias linhas o nome em conflito não é conhecido.
+func duplicateName(err error, name string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return &DuplicateNameError{Name: name}
+	}
+	return err
+}
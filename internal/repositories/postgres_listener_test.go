@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/outbox"
+	"testing"
+	"time"
+)
+
+// recordingSink guarda os eventos, os IDs e os resets recebidos.
+type recordingSink struct {
+	received *[]events.Event
+	ids      []uint64
+	resets   int
+}
+
+func (sink *recordingSink) BroadcastWithID(ctx context.Context, id uint64, event events.Event) error {
+	sink.ids = append(sink.ids, id)
+	*sink.received = append(*sink.received, event)
+	return nil
+}
+
+func (sink *recordingSink) Reset() {
+	sink.resets++
+}
+
+// newTestListener entrega os eventos em received e lê as mensagens de
+// stored em vez do banco.
+func newTestListener(cache *CategoryCache, stored []*outbox.Message, received *[]events.Event) *CategoryListener {
+	listener := NewCategoryListener("", nil, events.NewRegistry(entities.CategoryEvents...), cache, &recordingSink{received: received})
+
+	listener.messages = func(ids []uint) ([]*outbox.Message, error) {
+		var messages []*outbox.Message
+		for _, message := range stored {
+			for _, id := range ids {
+				if message.ID == id {
+					messages = append(messages, message)
+				}
+			}
+		}
+		return messages, nil
+	}
+
+	return listener
+}
+
+func TestCategoryListener_InvalidatesTheCacheOfAnotherReplica(t *testing.T) {
+	// Arrange
+	shared := NewInMemotyCategoryRepository()
+	cache := NewCategoryCache(CacheOptions{Size: 10, TTL: time.Hour})
+	replica := NewCachingCategoryRepository(shared, cache).ForTenant("acme")
+	category := &entities.Category{Name: "Electronics"}
+	replica.Save(category)
+	replica.List()
+	replica.FindByID(category.ID)
+
+	var received []events.Event
+	listener := newTestListener(cache, nil, &received)
+
+	// Outra réplica renomeia direto no banco, sem passar por este cache
+	category.Name = "Consumer Electronics"
+	shared.ForTenant("acme").Update(category)
+
+	// Act
+	err := listener.handle(context.Background(), `{"tenant":"acme","ids":[1]}`)
+	listed, _ := replica.List()
+	found, _ := replica.FindByID(category.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the notification to be handled, got %v", err)
+	}
+
+	if listed[0].Name != "Consumer Electronics" || found.Name != "Consumer Electronics" {
+		t.Errorf("Expected the renamed category after the notification, got %q and %q", listed[0].Name, found.Name)
+	}
+
+	if len(received) != 0 {
+		t.Errorf("Expected no events without outbox messages, got %v", received)
+	}
+}
+
+func TestCategoryListener_DeliversTheNotifiedEvents(t *testing.T) {
+	// Arrange
+	created := entities.CategoryCreated{EventTenant: entities.EventTenant{TenantID: "acme"}, ID: 1, Name: "Electronics", Version: 1}
+	renamed := entities.CategoryRenamed{EventTenant: entities.EventTenant{TenantID: "acme"}, ID: 1, OldName: "Electronics", NewName: "Consumer Electronics", Version: 2}
+	stored, _ := outbox.NewMessages(created, renamed)
+	stored[0].ID, stored[1].ID = 7, 8
+
+	var received []events.Event
+	listener := newTestListener(nil, stored, &received)
+
+	// Act
+	err := listener.handle(context.Background(), `{"tenant":"acme","messages":[7,8]}`)
+	invalidErr := listener.handle(context.Background(), `not json`)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the notification to be handled, got %v", err)
+	}
+
+	if len(received) != 2 || received[0].EventName() != "category.created" || received[1].(entities.CategoryRenamed).NewName != "Consumer Electronics" {
+		t.Errorf("Expected the created and renamed events in order, got %v", received)
+	}
+
+	if ids := listener.sink.(*recordingSink).ids; len(ids) != 2 || ids[0] != 7 || ids[1] != 8 {
+		t.Errorf("Expected the outbox message IDs 7 and 8, got %v", ids)
+	}
+
+	if invalidErr == nil {
+		t.Error("Expected an error for an invalid payload")
+	}
+}
+
+func TestCategoryListener_ResetsTheStreamAfterReconnecting(t *testing.T) {
+	// Arrange
+	var received []events.Event
+	listener := newTestListener(nil, nil, &received)
+	sink := listener.sink.(*recordingSink)
+
+	// Act
+	listener.listened()
+	first := sink.resets
+	listener.listened()
+
+	// Assert
+	if first != 0 || sink.resets != 1 {
+		t.Errorf("Expected a reset only after reconnecting, got %d and %d", first, sink.resets)
+	}
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"testing"
+
+	"gin-quickstart/internal/entities"
+)
+
+func TestDeduplicateCategoryNames_GeneratesNamesThatAreNotTaken(t *testing.T) {
+	// Arrange
+	db := testDatabase(t)
+	if err := db.Migrator().DropTable(&entities.Category{}); err != nil {
+		t.Fatalf("Failed to drop the categories: %v", err)
+	}
+	if err := db.AutoMigrate(&entities.Category{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// Simula um banco de antes do índice único
+	if err := db.Migrator().DropIndex(&entities.Category{}, "idx_categories_tenant_name"); err != nil {
+		t.Fatalf("Failed to drop the index: %v", err)
+	}
+
+	categories := []*entities.Category{
+		{ID: 1, TenantID: "default", Name: "Books", Version: 1},
+		{ID: 2, TenantID: "default", Name: "Books (3)", Version: 1},
+		{ID: 3, TenantID: "default", Name: "Books", Version: 1},
+		{ID: 4, TenantID: "default", Name: "Books", Version: 1},
+		{ID: 5, TenantID: "acme", Name: "Books", Version: 1},
+	}
+	if err := db.Create(categories).Error; err != nil {
+		t.Fatalf("Failed to save the categories: %v", err)
+	}
+
+	// Act
+	renamed, err := DeduplicateCategoryNames(db)
+	migrateErr := db.AutoMigrate(&entities.Category{})
+
+	// Assert
+	if err != nil || migrateErr != nil {
+		t.Fatalf("Expected no errors, got %v and %v", err, migrateErr)
+	}
+
+	if renamed != 2 {
+		t.Errorf("Expected 2 categories renamed, got %d", renamed)
+	}
+
+	var names []string
+	db.Model(&entities.Category{}).Order("id").Pluck("name", &names)
+	expected := []string{"Books", "Books (3)", "Books (3) (2)", "Books (4)", "Books"}
+	for i := range expected {
+		if i >= len(names) || names[i] != expected[i] {
+			t.Fatalf("Expected names %q, got %q", expected, names)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package stream distribui os eventos de domínio para conexões de longa
+// duração (Server-Sent Events), guardando os mais recentes para que um
+// cliente reconectado retome de onde parou.
+package stream
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/tenancy"
+	"slices"
+	"sync"
+)
+
+const (
+	DefaultReplaySize = 1000
+	// DefaultClientBuffer é quantas mensagens um cliente pode acumular sem
+	// ler antes de ser desconectado.
+	DefaultClientBuffer = 64
+
+	// ResetEvent avisa o cliente que eventos se perderam (fora do buffer de
+	// replay, de antes de um restart ou de quando o listener estava fora)
+	// e que ele deve recarregar a listagem.
+	ResetEvent = "stream.reset"
+)
+
+// Message é um evento numerado. Com Publish os IDs crescem de um em um a
+// partir de 1 e recomeçam quando o processo reinicia; com PublishWithID são
+// os de quem publica (as mensagens da outbox, iguais em todas as réplicas).
+// A numeração é única entre os tenants, então um cliente vê saltos nos IDs
+// onde estão as mensagens dos outros. A mensagem de ResetEvent não tem ID.
+type Message struct {
+	ID     uint64
+	Tenant string
+	Event  string
+	Data   []byte
+}
+
+// Client recebe as mensagens do seu tenant publicadas depois da sua
+// inscrição. O canal é fechado por Unsubscribe ou quando o cliente fica
+// DefaultClientBuffer mensagens para trás (Dropped).
+type Client struct {
+	tenant   string
+	messages chan Message
+	dropped  bool
+}
+
+func (client *Client) Messages() <-chan Message {
+	return client.messages
+}
+
+// Broadcaster nunca bloqueia quem publica: um cliente lento é desconectado
+// e, ao reconectar com o último ID recebido, recupera o que perdeu pelo
+// buffer de replay.
+type Broadcaster struct {
+	mutex        sync.Mutex
+	history      []Message
+	replaySize   int
+	clientBuffer int
+	lastID       uint64
+	// replayFrom é o menor Last-Event-ID que ainda pode ser retomado sem
+	// perdas; sobe a cada Reset
+	replayFrom uint64
+	clients    map[*Client]struct{}
+}
+
+func NewBroadcaster(replaySize, clientBuffer int) *Broadcaster {
+	return &Broadcaster{
+		replaySize:   replaySize,
+		clientBuffer: clientBuffer,
+		clients:      make(map[*Client]struct{}),
+	}
+}
+
+// Broadcast é o events.Handler assinado no bus; publica o evento com o
+// seu tenant, o seu nome e o JSON do evento como dados.
+func (broadcaster *Broadcaster) Broadcast(ctx context.Context, event events.Event) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	broadcaster.Publish(tenancy.Of(event), event.EventName(), data)
+	return nil
+}
+
+// BroadcastWithID é como Broadcast, mas com o ID de quem entrega: o
+// listener do Postgres usa o da mensagem na outbox.
+func (broadcaster *Broadcaster) BroadcastWithID(ctx context.Context, id uint64, event events.Event) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	broadcaster.PublishWithID(id, tenancy.Of(event), event.EventName(), data)
+	return nil
+}
+
+func (broadcaster *Broadcaster) Publish(tenant, event string, data []byte) Message {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.lastID++
+	message := Message{ID: broadcaster.lastID, Tenant: tenant, Event: event, Data: data}
+	broadcaster.publish(message)
+
+	return message
+}
+
+// PublishWithID publica com um ID dado em vez do contador, para que o
+// Last-Event-ID recebido de uma réplica valha nas outras. Os IDs não
+// precisam chegar em ordem; um ID repetido é ignorado. Não deve ser
+// misturado com Publish no mesmo Broadcaster.
+func (broadcaster *Broadcaster) PublishWithID(id uint64, tenant, event string, data []byte) Message {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.lastID = max(broadcaster.lastID, id)
+	message := Message{ID: id, Tenant: tenant, Event: event, Data: data}
+	broadcaster.publish(message)
+
+	return message
+}
+
+// Reset avisa os clientes com ResetEvent de que eventos podem ter se
+// perdido e esvazia o buffer de replay: quem reconectar com um ID anterior
+// também recebe ResetEvent.
+func (broadcaster *Broadcaster) Reset() {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.history = nil
+	broadcaster.replayFrom = broadcaster.lastID + 1
+
+	reset := Message{Event: ResetEvent, Data: []byte(`{}`)}
+	for client := range broadcaster.clients {
+		broadcaster.send(client, reset)
+	}
+}
+
+func (broadcaster *Broadcaster) publish(message Message) {
+	index, found := slices.BinarySearchFunc(broadcaster.history, message.ID, func(buffered Message, id uint64) int {
+		return cmp.Compare(buffered.ID, id)
+	})
+	if found {
+		return
+	}
+
+	broadcaster.history = slices.Insert(broadcaster.history, index, message)
+	if overflow := len(broadcaster.history) - broadcaster.replaySize; overflow > 0 {
+		broadcaster.history = append([]Message(nil), broadcaster.history[overflow:]...)
+	}
+
+	// As mensagens de outros tenants nem entram no buffer do cliente: uma
+	// rajada num tenant não desconecta os clientes dos demais
+	for client := range broadcaster.clients {
+		if client.tenant == message.Tenant {
+			broadcaster.send(client, message)
+		}
+	}
+}
+
+// Subscribe inscreve um cliente que só recebe mensagens novas do tenant.
+func (broadcaster *Broadcaster) Subscribe(tenant string) *Client {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	return broadcaster.add(tenant)
+}
+
+// SubscribeAfter inscreve um cliente do tenant que retoma depois de
+// lastEventID e devolve as mensagens perdidas do tenant. complete é false
+// quando parte delas pode já ter saído do buffer (ou lastEventID é de antes
+// de um restart ou de um Reset): o cliente precisa recarregar o estado por outro meio.
+func (broadcaster *Broadcaster) SubscribeAfter(tenant string, lastEventID uint64) (client *Client, missed []Message, complete bool) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	if lastEventID > broadcaster.lastID || lastEventID < broadcaster.replayFrom {
+		return broadcaster.add(tenant), nil, false
+	}
+
+	missed = make([]Message, 0)
+	for _, message := range broadcaster.history {
+		if message.Tenant == tenant && message.ID > lastEventID {
+			missed = append(missed, message)
+		}
+	}
+
+	oldest := broadcaster.lastID + 1
+	if len(broadcaster.history) > 0 {
+		oldest = broadcaster.history[0].ID
+	}
+
+	return broadcaster.add(tenant), missed, lastEventID+1 >= oldest
+}
+
+// Unsubscribe remove o cliente; pode ser chamado mais de uma vez.
+func (broadcaster *Broadcaster) Unsubscribe(client *Client) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.remove(client)
+}
+
+// Dropped indica se o cliente foi desconectado por não acompanhar as
+// publicações.
+func (broadcaster *Broadcaster) Dropped(client *Client) bool {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	return client.dropped
+}
+
+func (broadcaster *Broadcaster) add(tenant string) *Client {
+	client := &Client{tenant: tenant, messages: make(chan Message, broadcaster.clientBuffer)}
+	broadcaster.clients[client] = struct{}{}
+	return client
+}
+
+func (broadcaster *Broadcaster) send(client *Client, message Message) {
+	select {
+	case client.messages <- message:
+	default:
+		client.dropped = true
+		broadcaster.remove(client)
+	}
+}
+
+func (broadcaster *Broadcaster) remove(client *Client) {
+	if _, ok := broadcaster.clients[client]; !ok {
+		return
+	}
+
+	delete(broadcaster.clients, client)
+	close(client.messages)
+}
@@ -0,0 +1,161 @@
+package stream
+
+import (
+	"gin-quickstart/internal/tenancy"
+	"testing"
+)
+
+func TestBroadcaster_ReplaysMissedMessages(t *testing.T) {
+	// Arrange
+	broadcaster := NewBroadcaster(10, 10)
+	for i := 0; i < 3; i++ {
+		broadcaster.Publish(tenancy.Default, "test.happened", []byte(`{}`))
+	}
+
+	// Act
+	client, missed, complete := broadcaster.SubscribeAfter(tenancy.Default, 1)
+	next := broadcaster.Publish(tenancy.Default, "test.happened", []byte(`{}`))
+
+	// Assert
+	if !complete || len(missed) != 2 || missed[0].ID != 2 || missed[1].ID != 3 {
+		t.Fatalf("Expected messages 2 and 3 to be replayed, got %+v (complete %v)", missed, complete)
+	}
+
+	if received := <-client.Messages(); received.ID != next.ID {
+		t.Errorf("Expected live message %d, got %d", next.ID, received.ID)
+	}
+}
+
+func TestBroadcaster_ReportsGaps(t *testing.T) {
+	testCases := []struct {
+		name        string
+		lastEventID uint64
+		complete    bool
+	}{
+		{"up to date", 5, true},
+		{"oldest still buffered", 2, true},
+		{"evicted from buffer", 1, false},
+		{"from before a restart", 42, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			broadcaster := NewBroadcaster(3, 10)
+			for i := 0; i < 5; i++ {
+				broadcaster.Publish(tenancy.Default, "test.happened", nil)
+			}
+
+			// Act
+			_, _, complete := broadcaster.SubscribeAfter(tenancy.Default, testCase.lastEventID)
+
+			// Assert
+			if complete != testCase.complete {
+				t.Fatalf("Expected complete %v, got %v", testCase.complete, complete)
+			}
+		})
+	}
+}
+
+func TestBroadcaster_DropsSlowClientsWithoutBlocking(t *testing.T) {
+	// Arrange
+	broadcaster := NewBroadcaster(10, 2)
+	slow := broadcaster.Subscribe(tenancy.Default)
+	fast := broadcaster.Subscribe(tenancy.Default)
+
+	// Act
+	for i := 0; i < 3; i++ {
+		broadcaster.Publish(tenancy.Default, "test.happened", nil)
+		<-fast.Messages()
+	}
+
+	// Assert
+	if !broadcaster.Dropped(slow) || broadcaster.Dropped(fast) {
+		t.Fatal("Expected only the slow client to be dropped")
+	}
+
+	received := 0
+	for range slow.Messages() {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("Expected the slow client to keep its 2 buffered messages before the close, got %d", received)
+	}
+
+	broadcaster.Unsubscribe(slow)
+}
+
+func TestBroadcaster_KeepsTenantsApart(t *testing.T) {
+	// Arrange
+	broadcaster := NewBroadcaster(10, 2)
+	acme := broadcaster.Subscribe("acme")
+	broadcaster.Publish("acme", "test.happened", nil)
+
+	// Act
+	for i := 0; i < 5; i++ {
+		broadcaster.Publish("globex", "test.happened", nil)
+	}
+	next := broadcaster.Publish("acme", "test.happened", nil)
+	_, missed, complete := broadcaster.SubscribeAfter("acme", 0)
+
+	// Assert
+	if broadcaster.Dropped(acme) {
+		t.Fatal("Expected a burst in another tenant not to drop the client")
+	}
+
+	if first, second := <-acme.Messages(), <-acme.Messages(); first.ID != 1 || second.ID != next.ID {
+		t.Errorf("Expected only acme's messages 1 and %d, got %d and %d", next.ID, first.ID, second.ID)
+	}
+
+	if !complete || len(missed) != 2 || missed[0].Tenant != "acme" || missed[1].Tenant != "acme" {
+		t.Errorf("Expected only acme's messages to be replayed, got %+v", missed)
+	}
+}
+
+func TestBroadcaster_ReplaysByExternalIDs(t *testing.T) {
+	// Arrange
+	broadcaster := NewBroadcaster(10, 10)
+	broadcaster.PublishWithID(40, tenancy.Default, "test.happened", nil)
+	broadcaster.PublishWithID(42, tenancy.Default, "test.happened", nil)
+	broadcaster.PublishWithID(41, tenancy.Default, "test.happened", nil)
+	broadcaster.PublishWithID(42, tenancy.Default, "test.happened", nil)
+
+	// Act
+	_, missed, complete := broadcaster.SubscribeAfter(tenancy.Default, 40)
+	_, _, ahead := broadcaster.SubscribeAfter(tenancy.Default, 43)
+
+	// Assert
+	if !complete || len(missed) != 2 || missed[0].ID != 41 || missed[1].ID != 42 {
+		t.Fatalf("Expected messages 41 and 42 replayed once and in order, got %+v (complete %v)", missed, complete)
+	}
+
+	if ahead {
+		t.Error("Expected an ID ahead of the last message to be incomplete")
+	}
+}
+
+func TestBroadcaster_ResetNotifiesClientsAndForcesReload(t *testing.T) {
+	// Arrange
+	broadcaster := NewBroadcaster(10, 10)
+	broadcaster.PublishWithID(7, tenancy.Default, "test.happened", nil)
+	client := broadcaster.Subscribe(tenancy.Default)
+
+	// Act
+	broadcaster.Reset()
+	_, missed, complete := broadcaster.SubscribeAfter(tenancy.Default, 7)
+	broadcaster.PublishWithID(9, tenancy.Default, "test.happened", nil)
+	_, _, resumed := broadcaster.SubscribeAfter(tenancy.Default, 9)
+
+	// Assert
+	if received := <-client.Messages(); received.Event != ResetEvent || received.ID != 0 {
+		t.Errorf("Expected a %s message without an ID, got %+v", ResetEvent, received)
+	}
+
+	if complete || len(missed) != 0 {
+		t.Errorf("Expected an ID from before the reset to be incomplete, got %+v (complete %v)", missed, complete)
+	}
+
+	if !resumed {
+		t.Error("Expected an ID from after the reset to resume")
+	}
+}
@@ -0,0 +1,143 @@
+// Package tenancy identifica a qual catálogo (loja) cada requisição
+// pertence. Os use cases leem o tenant do context e escopam os
+// repositórios com ele, então nenhuma porta de entrada enxerga dados de
+// outro tenant.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/auth"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Default é o tenant das requisições que não indicam nenhum, e o dos dados
+// anteriores à separação por tenant.
+const Default = "default"
+
+// Header é o header padrão com o tenant.
+const Header = "X-Tenant-ID"
+
+var ErrInvalidTenant = errors.New("tenant must have 1 to 63 lowercase letters, digits or hyphens")
+
+// MismatchError indica que o principal está preso a outro tenant que não o
+// pedido no header ou no subdomínio.
+type MismatchError struct {
+	Requested string
+	Bound     string
+}
+
+func (err *MismatchError) Error() string {
+	return fmt.Sprintf("credentials belong to tenant %s, not %s", err.Bound, err.Requested)
+}
+
+// Mesmas regras de um rótulo de DNS, para servir também como subdomínio
+var validTenant = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func Validate(tenant string) error {
+	if !validTenant.MatchString(tenant) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// Resolver descobre o tenant da requisição, nesta ordem: claim tenant do
+// JWT (ou tenant da API key), header e subdomínio de BaseDomain. Sem
+// nenhum deles o tenant é Default. Só principais com auth.AnyTenant
+// escolhem o tenant pelo header ou subdomínio; os sem tenant ficam no
+// Default.
+type Resolver struct {
+	Header string
+	// BaseDomain habilita o tenant pelo subdomínio: com "example.com",
+	// loja1.example.com é o tenant loja1
+	BaseDomain string
+}
+
+// Resolve falha com ErrInvalidTenant para um tenant malformado e com
+// *MismatchError quando o header ou o subdomínio pedem um tenant diferente
+// daquele a que o principal está preso.
+func (resolver Resolver) Resolve(request *http.Request, principal *auth.Principal) (string, error) {
+	requested, err := resolver.requested(request)
+	if err != nil {
+		return "", err
+	}
+
+	bound := Default
+	if principal != nil && principal.Tenant != "" {
+		bound = principal.Tenant
+	}
+
+	if bound == auth.AnyTenant {
+		if requested == "" {
+			return Default, nil
+		}
+		return requested, nil
+	}
+
+	if requested != "" && requested != bound {
+		return "", &MismatchError{Requested: requested, Bound: bound}
+	}
+
+	return bound, Validate(bound)
+}
+
+func (resolver Resolver) requested(request *http.Request) (string, error) {
+	header := resolver.Header
+	if header == "" {
+		header = Header
+	}
+
+	if tenant := request.Header.Get(header); tenant != "" {
+		return tenant, Validate(tenant)
+	}
+
+	if resolver.BaseDomain == "" {
+		return "", nil
+	}
+
+	host := request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	subdomain, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(resolver.BaseDomain))
+	if !ok || strings.Contains(subdomain, ".") {
+		return "", nil
+	}
+
+	return subdomain, Validate(subdomain)
+}
+
+type contextKey int
+
+const tenantKey contextKey = iota
+
+// WithTenant guarda no context o tenant usado pelos use cases.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// From devolve o tenant do context, ou Default.
+func From(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey).(string); ok && tenant != "" {
+		return tenant
+	}
+	return Default
+}
+
+// Owned é implementado por eventos e registros que pertencem a um tenant.
+type Owned interface {
+	Tenant() string
+}
+
+// Of devolve o tenant de value, ou Default se ele não pertencer a nenhum.
+func Of(value any) string {
+	if owned, ok := value.(Owned); ok && owned.Tenant() != "" {
+		return owned.Tenant()
+	}
+	return Default
+}
@@ -0,0 +1,102 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"gin-quickstart/internal/auth"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := Resolver{Header: Header, BaseDomain: "example.com"}
+	bound := &auth.Principal{Subject: "importer", Tenant: "acme"}
+	unbound := &auth.Principal{Subject: "maria"}
+	anyTenant := &auth.Principal{Subject: "ops", Tenant: auth.AnyTenant}
+
+	testCases := []struct {
+		name      string
+		host      string
+		header    string
+		principal *auth.Principal
+		want      string
+		wantErr   error
+	}{
+		{"nothing", "api.internal", "", unbound, Default, nil},
+		{"header", "api.internal", "acme", anyTenant, "acme", nil},
+		{"subdomain", "globex.example.com:8000", "", anyTenant, "globex", nil},
+		{"header over subdomain", "globex.example.com", "acme", anyTenant, "acme", nil},
+		{"nested subdomain", "a.b.example.com", "", anyTenant, Default, nil},
+		{"bare base domain", "example.com", "", anyTenant, Default, nil},
+		{"any tenant without a request", "api.internal", "", anyTenant, Default, nil},
+		{"unbound principal, default header", "api.internal", Default, unbound, Default, nil},
+		{"no principal", "api.internal", "", nil, Default, nil},
+		{"bound principal", "api.internal", "", bound, "acme", nil},
+		{"bound principal, same header", "api.internal", "acme", bound, "acme", nil},
+		{"invalid header", "api.internal", "Acme_Corp", anyTenant, "", ErrInvalidTenant},
+		{"invalid subdomain", "-acme.example.com", "", anyTenant, "", ErrInvalidTenant},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			request := httptest.NewRequest("GET", "/v1/categories", nil)
+			request.Host = testCase.host
+			if testCase.header != "" {
+				request.Header.Set(Header, testCase.header)
+			}
+
+			// Act
+			tenant, err := resolver.Resolve(request, testCase.principal)
+
+			// Assert
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("Expected error %v, got %v", testCase.wantErr, err)
+			}
+			if tenant != testCase.want {
+				t.Errorf("Expected tenant %q, got %q", testCase.want, tenant)
+			}
+		})
+	}
+}
+
+func TestResolver_RejectsAnotherTenantForBoundPrincipal(t *testing.T) {
+	resolver := Resolver{BaseDomain: "example.com"}
+
+	principals := []struct {
+		principal *auth.Principal
+		bound     string
+	}{
+		{&auth.Principal{Subject: "importer", Tenant: "acme"}, "acme"},
+		// Sem tenant nas credenciais, o principal fica no Default
+		{&auth.Principal{Subject: "maria", Roles: []string{"editor"}}, Default},
+		{nil, Default},
+	}
+
+	for _, principal := range principals {
+		for _, request := range []struct{ host, header string }{{"api.internal", "globex"}, {"globex.example.com", ""}} {
+			httpRequest := httptest.NewRequest("GET", "/v1/categories", nil)
+			httpRequest.Host = request.host
+			if request.header != "" {
+				httpRequest.Header.Set(Header, request.header)
+			}
+
+			_, err := resolver.Resolve(httpRequest, principal.principal)
+
+			var mismatchError *MismatchError
+			if !errors.As(err, &mismatchError) || mismatchError.Requested != "globex" || mismatchError.Bound != principal.bound {
+				t.Errorf("Expected MismatchError for %+v bound to %s, got %v", request, principal.bound, err)
+			}
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	if tenant := From(context.Background()); tenant != Default {
+		t.Errorf("Expected %q without a tenant, got %q", Default, tenant)
+	}
+
+	if tenant := From(WithTenant(context.Background(), "acme")); tenant != "acme" {
+		t.Errorf("Expected acme, got %q", tenant)
+	}
+}
@@ -0,0 +1,212 @@
+// Package tlsconfig monta o TLS da API a partir de arquivos PEM e os relê
+// quando mudam, sem reiniciar o processo: as conexões novas já usam o
+// certificado (e as CAs de cliente) novos, as abertas seguem com os antigos.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// CipherSuites são as suítes aceitas no TLS 1.2: só ECDHE (sigilo
+// futuro) com AEAD. As do TLS 1.3 não são configuráveis e já são seguras.
+var CipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// ClientAuth é o modo do mTLS.
+type ClientAuth string
+
+const (
+	// ClientAuthNone não pede certificado ao cliente
+	ClientAuthNone ClientAuth = "none"
+	// ClientAuthOptional verifica o certificado de quem mandar um; os
+	// outros clientes seguem com JWT ou API key
+	ClientAuthOptional ClientAuth = "optional"
+	// ClientAuthRequire recusa o handshake sem um certificado válido
+	ClientAuthRequire ClientAuth = "require"
+)
+
+func ParseClientAuth(value string) (ClientAuth, error) {
+	switch mode := ClientAuth(value); mode {
+	case "", ClientAuthNone:
+		return ClientAuthNone, nil
+	case ClientAuthOptional, ClientAuthRequire:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid client auth mode %q: use none, optional or require", value)
+	}
+}
+
+func (mode ClientAuth) tlsType() tls.ClientAuthType {
+	switch mode {
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// Options são os arquivos PEM do servidor. ClientCAFile é obrigatório
+// com ClientAuth optional ou require.
+type Options struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   ClientAuth
+}
+
+// Reloader guarda o tls.Config atual e o troca quando os arquivos mudam.
+type Reloader struct {
+	options  Options
+	mutex    sync.RWMutex
+	current  *tls.Config
+	modTimes map[string]time.Time
+}
+
+func NewReloader(options Options) (*Reloader, error) {
+	if options.CertFile == "" || options.KeyFile == "" {
+		return nil, errors.New("TLS requires a certificate and a key file")
+	}
+	if options.ClientAuth != ClientAuthNone && options.ClientAuth != "" && options.ClientCAFile == "" {
+		return nil, fmt.Errorf("client auth %q requires a client CA file", options.ClientAuth)
+	}
+
+	reloader := &Reloader{options: options}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// TLSConfig é o config a entregar ao servidor: cada handshake pega o
+// config carregado mais recente.
+func (reloader *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			reloader.mutex.RLock()
+			defer reloader.mutex.RUnlock()
+			return reloader.current, nil
+		},
+	}
+}
+
+// Reload relê os arquivos. Em caso de erro o config anterior continua
+// valendo.
+func (reloader *Reloader) Reload() error {
+	modTimes, err := reloader.stat()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(reloader.options.CertFile, reloader.options.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	config := &tls.Config{
+		Certificates:     []tls.Certificate{certificate},
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites:     CipherSuites,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		// O http.Server só acrescenta o h2 no config base, não nos
+		// devolvidos por GetConfigForClient
+		NextProtos: []string{"h2", "http/1.1"},
+		ClientAuth: reloader.options.ClientAuth.tlsType(),
+	}
+
+	if reloader.options.ClientCAFile != "" {
+		content, err := os.ReadFile(reloader.options.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return fmt.Errorf("no certificates found in client CA file %s", reloader.options.ClientCAFile)
+		}
+		config.ClientCAs = pool
+	}
+
+	reloader.mutex.Lock()
+	reloader.current = config
+	reloader.modTimes = modTimes
+	reloader.mutex.Unlock()
+
+	return nil
+}
+
+// Run confere os arquivos a cada intervalo e os relê quando algum mudou,
+// até o ctx ser cancelado. Um arquivo no meio da troca (chave nova com o
+// certificado antigo, por exemplo) falha e é tentado de novo no próximo
+// intervalo.
+func (reloader *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !reloader.changed() {
+				continue
+			}
+			if err := reloader.Reload(); err != nil {
+				log.Printf("tls: keeping the current certificate: %v", err)
+				continue
+			}
+			log.Printf("tls: reloaded %s", reloader.options.CertFile)
+		}
+	}
+}
+
+func (reloader *Reloader) changed() bool {
+	modTimes, err := reloader.stat()
+	if err != nil {
+		return true
+	}
+
+	reloader.mutex.RLock()
+	defer reloader.mutex.RUnlock()
+
+	for path, modTime := range modTimes {
+		if !modTime.Equal(reloader.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (reloader *Reloader) stat() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+
+	for _, path := range []string{reloader.options.CertFile, reloader.options.KeyFile, reloader.options.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
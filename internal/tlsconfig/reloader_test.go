@@ -0,0 +1,239 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/tlsconfig/tlsconfigtest"
+)
+
+// serve aceita conexões TLS com o config do reloader até o fim do teste.
+func serve(t *testing.T, reloader *Reloader) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", reloader.TLSConfig())
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer connection.Close()
+				if connection.(*tls.Conn).Handshake() == nil {
+					io.Copy(io.Discard, connection)
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func handshake(address string, config *tls.Config) (tls.ConnectionState, error) {
+	connection, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", address, config)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer connection.Close()
+
+	// No TLS 1.3 a recusa do certificado de cliente só chega na leitura
+	connection.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err := connection.Read(make([]byte, 1)); err != nil && !isTimeout(err) {
+		return tls.ConnectionState{}, err
+	}
+
+	return connection.ConnectionState(), nil
+}
+
+func isTimeout(err error) bool {
+	netError, ok := err.(net.Error)
+	return ok && netError.Timeout()
+}
+
+// touch adianta o mtime para a troca ser notada mesmo em sistemas de
+// arquivos com resolução de segundos.
+func touch(t *testing.T, paths ...string) {
+	t.Helper()
+	later := time.Now().Add(time.Minute)
+	for _, path := range paths {
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatalf("Error touching %s: %v", path, err)
+		}
+	}
+}
+
+func TestReloader_ReloadsChangedCertificate(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	first, second := ca.Server(t), ca.Server(t)
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, first)
+
+	reloader, err := NewReloader(Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	address := serve(t, reloader)
+	client := &tls.Config{RootCAs: ca.Pool(), ServerName: "localhost"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Run(ctx, 10*time.Millisecond)
+
+	// Act
+	before, err := handshake(address, client)
+	if err != nil {
+		t.Fatalf("Expected no error before the reload, got %v", err)
+	}
+
+	tlsconfigtest.WriteKeyPair(t, dir, second)
+	touch(t, certFile, keyFile)
+
+	var after tls.ConnectionState
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if after, err = handshake(address, client); err == nil && after.PeerCertificates[0].Equal(second.Leaf) {
+			break
+		}
+	}
+
+	// Assert
+	if !before.PeerCertificates[0].Equal(first.Leaf) {
+		t.Errorf("Expected the first certificate before the reload, got serial %v", before.PeerCertificates[0].SerialNumber)
+	}
+
+	if len(after.PeerCertificates) == 0 || !after.PeerCertificates[0].Equal(second.Leaf) {
+		t.Fatal("Expected the second certificate after the files changed")
+	}
+}
+
+func TestReloader_KeepsCurrentCertificateOnInvalidFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certificate := ca.Server(t)
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, certificate)
+
+	reloader, _ := NewReloader(Options{CertFile: certFile, KeyFile: keyFile})
+	address := serve(t, reloader)
+
+	// Act
+	os.WriteFile(certFile, []byte("not a certificate"), 0o600)
+	err := reloader.Reload()
+	state, handshakeErr := handshake(address, &tls.Config{RootCAs: ca.Pool(), ServerName: "localhost"})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error reloading an invalid certificate")
+	}
+
+	if handshakeErr != nil || !state.PeerCertificates[0].Equal(certificate.Leaf) {
+		t.Errorf("Expected the previous certificate still served, got %v", handshakeErr)
+	}
+}
+
+func TestReloader_Defaults(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, ca.Server(t))
+
+	reloader, _ := NewReloader(Options{CertFile: certFile, KeyFile: keyFile})
+	address := serve(t, reloader)
+
+	testCases := []struct {
+		name   string
+		client *tls.Config
+		valid  bool
+	}{
+		{"TLS 1.3", &tls.Config{MinVersion: tls.VersionTLS13}, true},
+		{"TLS 1.2 with AEAD", &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}}, true},
+		{"TLS 1.2 with CBC", &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA}}, false},
+		{"TLS 1.1", &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS11}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			testCase.client.RootCAs = ca.Pool()
+			testCase.client.ServerName = "localhost"
+			testCase.client.NextProtos = []string{"h2", "http/1.1"}
+			state, err := handshake(address, testCase.client)
+
+			// Assert
+			if testCase.valid && err != nil {
+				t.Fatalf("Expected the handshake to succeed, got %v", err)
+			}
+			if testCase.valid && state.NegotiatedProtocol != "h2" {
+				t.Errorf("Expected h2 offered by ALPN, got %q", state.NegotiatedProtocol)
+			}
+			if !testCase.valid && err == nil {
+				t.Fatal("Expected the handshake to fail")
+			}
+		})
+	}
+}
+
+func TestReloader_RequiresClientCertificate(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	otherCA := tlsconfigtest.NewCA(t, "Other CA")
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, ca.Server(t))
+
+	reloader, err := NewReloader(Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: ca.WriteCA(t, dir), ClientAuth: ClientAuthRequire})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	address := serve(t, reloader)
+
+	client := func(certificates ...tls.Certificate) *tls.Config {
+		return &tls.Config{RootCAs: ca.Pool(), ServerName: "localhost", Certificates: certificates, NextProtos: []string{"h2"}}
+	}
+
+	// Act
+	_, trusted := handshake(address, client(ca.Client(t, pkix.Name{CommonName: "billing"})))
+	_, untrusted := handshake(address, client(otherCA.Client(t, pkix.Name{CommonName: "billing"})))
+	_, missing := handshake(address, client())
+
+	// Assert
+	if trusted != nil {
+		t.Errorf("Expected a certificate from the client CA accepted, got %v", trusted)
+	}
+
+	if untrusted == nil {
+		t.Error("Expected a certificate from another CA rejected")
+	}
+
+	if missing == nil {
+		t.Error("Expected a client without certificate rejected")
+	}
+}
+
+func TestNewReloader_InvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlsconfigtest.NewCA(t, "Test CA")
+	certFile, keyFile := tlsconfigtest.WriteKeyPair(t, dir, ca.Server(t))
+
+	if _, err := NewReloader(Options{CertFile: certFile}); err == nil {
+		t.Error("Expected an error without a key file")
+	}
+
+	if _, err := NewReloader(Options{CertFile: certFile, KeyFile: keyFile, ClientAuth: ClientAuthOptional}); err == nil {
+		t.Error("Expected an error for client auth without a client CA file")
+	}
+
+	if _, err := ParseClientAuth("sometimes"); err == nil {
+		t.Error("Expected an error for an unknown client auth mode")
+	}
+}
@@ -0,0 +1,196 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/auth"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+// asRole devolve um context autenticado com os papéis informados.
+func asRole(roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "tester", Roles: roles})
+}
+
+// authorizationFixture tem uma categoria ativa (1), uma removida (2) e uma
+// entrega de webhook na dead-letter (1).
+type authorizationFixture struct {
+	repo          repositories.ICategoryRepository
+	auditLog      audit.IStore
+	webhookStore  webhooks.IStore
+	activeVersion uint
+}
+
+func newAuthorizationFixture(t *testing.T) *authorizationFixture {
+	t.Helper()
+	admin := asRole("admin")
+	repo := repositories.NewInMemotyCategoryRepository()
+	fixture := &authorizationFixture{
+		repo:         repo,
+		auditLog:     repo,
+		webhookStore: webhooks.NewInMemoryStore(),
+	}
+
+	active, err := NewCreateCategoryUseCase(fixture.repo).Execute(admin, "Electronics")
+	if err != nil {
+		t.Fatalf("Error seeding category: %v", err)
+	}
+	fixture.activeVersion = active.Version
+
+	deleted, _ := NewCreateCategoryUseCase(fixture.repo).Execute(admin, "Home & Garden")
+	NewDeleteCategoryUseCase(fixture.repo).Execute(admin, deleted.ID, 0)
+
+	subscription, _ := NewCreateWebhookUseCase(fixture.webhookStore, webhooks.Targets{}).Execute(admin, "https://example.com/hooks", []string{webhooks.AllEvents}, "")
+	fixture.webhookStore.SaveDeliveries(&webhooks.Delivery{SubscriptionID: subscription.ID, Status: webhooks.DeliveryDead, NextAttemptAt: time.Now()})
+
+	return fixture
+}
+
+func TestUseCases_Authorization(t *testing.T) {
+	testCases := []struct {
+		useCase    string
+		permission authz.Permission
+		allowed    []string
+		run        func(ctx context.Context, fixture *authorizationFixture) error
+	}{
+		{"get category", authz.CategoriesRead, []string{"viewer", "editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewGetCategoryUseCase(f.repo).Execute(ctx, 1)
+			return err
+		}},
+		{"list categories", authz.CategoriesRead, []string{"viewer", "editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewListCategoriesUseCase(f.repo).Execute(ctx)
+			return err
+		}},
+		{"export categories", authz.CategoriesRead, []string{"viewer", "editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			writer, _ := categoryio.NewWriter(categoryio.JSONL, io.Discard)
+			_, err := NewExportCategoriesUseCase(f.repo).Execute(ctx, writer)
+			return err
+		}},
+		{"create category", authz.CategoriesCreate, []string{"editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewCreateCategoryUseCase(f.repo).Execute(ctx, "Books & Magazines")
+			return err
+		}},
+		{"import categories", authz.CategoriesCreate, []string{"editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			reader, _ := categoryio.NewReader(categoryio.CSV, strings.NewReader("name\nBooks & Magazines\n"))
+			_, err := NewImportCategoriesUseCase(f.repo).Execute(ctx, reader, false)
+			return err
+		}},
+		{"update category", authz.CategoriesUpdate, []string{"editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewUpdateCategoryUseCase(f.repo).Execute(ctx, 1, "Consumer Electronics", f.activeVersion)
+			return err
+		}},
+		{"category history", authz.CategoriesHistory, []string{"editor", "admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewCategoryHistoryUseCase(f.auditLog).Execute(ctx, 1)
+			return err
+		}},
+		{"delete category", authz.CategoriesDelete, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			return NewDeleteCategoryUseCase(f.repo).Execute(ctx, 1, f.activeVersion)
+		}},
+		{"restore category", authz.CategoriesRestore, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewRestoreCategoryUseCase(f.repo).Execute(ctx, 2)
+			return err
+		}},
+		{"batch with a delete", authz.CategoriesDelete, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewBatchCategoriesUseCase(f.repo).Execute(ctx, []BatchOperation{
+				{Type: BatchDelete, ID: 1},
+				{Type: BatchCreate, Name: "Books & Magazines"},
+			}, true)
+			return err
+		}},
+		{"list webhooks", authz.WebhooksRead, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewListWebhooksUseCase(f.webhookStore).Execute(ctx)
+			return err
+		}},
+		{"get webhook", authz.WebhooksRead, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewGetWebhookUseCase(f.webhookStore).Execute(ctx, 1)
+			return err
+		}},
+		{"list webhook deliveries", authz.WebhooksRead, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewListWebhookDeliveriesUseCase(f.webhookStore).Execute(ctx, 1)
+			return err
+		}},
+		{"list webhook dead letters", authz.WebhooksRead, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewListWebhookDeadLettersUseCase(f.webhookStore).Execute(ctx)
+			return err
+		}},
+		{"create webhook", authz.WebhooksManage, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewCreateWebhookUseCase(f.webhookStore, webhooks.Targets{}).Execute(ctx, "https://example.com/other", []string{webhooks.AllEvents}, "")
+			return err
+		}},
+		{"redeliver webhook", authz.WebhooksManage, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			_, err := NewRedeliverWebhookUseCase(f.webhookStore).Execute(ctx, 1)
+			return err
+		}},
+		{"delete webhook", authz.WebhooksManage, []string{"admin"}, func(ctx context.Context, f *authorizationFixture) error {
+			return NewDeleteWebhookUseCase(f.webhookStore).Execute(ctx, 1)
+		}},
+	}
+
+	for _, testCase := range testCases {
+		for _, role := range []string{"viewer", "editor", "admin", "unknown"} {
+			t.Run(testCase.useCase+"/"+role, func(t *testing.T) {
+				// Arrange
+				fixture := newAuthorizationFixture(t)
+
+				// Act
+				err := testCase.run(asRole(role), fixture)
+
+				// Assert
+				if slices.Contains(testCase.allowed, role) {
+					if err != nil {
+						t.Fatalf("Expected %s to be allowed, got %v", role, err)
+					}
+					return
+				}
+
+				var forbidden *authz.ForbiddenError
+				if !errors.As(err, &forbidden) || forbidden.Permission != testCase.permission {
+					t.Fatalf("Expected missing permission %s, got %v", testCase.permission, err)
+				}
+			})
+		}
+
+		t.Run(testCase.useCase+"/unauthenticated", func(t *testing.T) {
+			// Act
+			err := testCase.run(context.Background(), newAuthorizationFixture(t))
+
+			// Assert
+			if !errors.Is(err, authz.ErrUnauthenticated) {
+				t.Fatalf("Expected ErrUnauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUseCases_ForbiddenWritesChangeNothing(t *testing.T) {
+	// Arrange
+	fixture := newAuthorizationFixture(t)
+
+	// Act
+	err := NewDeleteCategoryUseCase(fixture.repo).Execute(asRole("editor"), 1, fixture.activeVersion)
+
+	// Assert
+	var forbidden *authz.ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("Expected ForbiddenError, got %v", err)
+	}
+
+	if _, err := fixture.repo.FindByID(1); err != nil {
+		t.Errorf("Expected category to survive a forbidden delete, got %v", err)
+	}
+
+	if entries, _ := fixture.auditLog.ListByCategory(tenancy.Default, 1); len(entries) != 1 {
+		t.Errorf("Expected only the create entry in the audit log, got %d", len(entries))
+	}
+}
@@ -0,0 +1,139 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type BatchOperationType string
+
+const (
+	BatchCreate BatchOperationType = "create"
+	BatchUpdate BatchOperationType = "update"
+	BatchDelete BatchOperationType = "delete"
+)
+
+// BatchOperation é um item do lote. ID e Version só valem para update e
+// delete; Name para create e update. Version 0 usa a versão atual.
+type BatchOperation struct {
+	Type    BatchOperationType
+	ID      uint
+	Name    string
+	Version uint
+}
+
+// BatchResult é o resultado de cada operação, na mesma posição do lote.
+type BatchResult struct {
+	Operation BatchOperation
+	Category  *entities.Category
+	Err       error
+}
+
+func (operationType BatchOperationType) permission() authz.Permission {
+	switch operationType {
+	case BatchUpdate:
+		return authz.CategoriesUpdate
+	case BatchDelete:
+		return authz.CategoriesDelete
+	default:
+		return authz.CategoriesCreate
+	}
+}
+
+// ErrBatchAborted indica que, no modo atômico, ao menos uma operação falhou
+// e nenhuma foi aplicada. Os erros de cada item estão nos BatchResult.
+var ErrBatchAborted = errors.New("batch aborted, no operation was applied")
+
+type batchCategoriesUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewBatchCategoriesUseCase(repository repositories.ICategoryRepository) *batchCategoriesUseCase {
+	return &batchCategoriesUseCase{
+		repository,
+	}
+}
+
+// Execute aplica as operações do lote. Com atomic=true tudo roda numa
+// transação e qualquer falha desfaz o lote inteiro (ErrBatchAborted); com
+// atomic=false cada operação é independente e as falhas ficam só no
+// resultado do item. Em ambos os modos as operações são aplicadas na ordem
+// do lote, e os eventos e as entradas de auditoria de cada uma são gravados
+// na mesma transação que ela.
+func (useCase *batchCategoriesUseCase) Execute(ctx context.Context, operations []BatchOperation, atomic bool) ([]BatchResult, error) {
+	// O lote inteiro é recusado se faltar a permissão de qualquer operação
+	for _, operation := range operations {
+		if err := authz.Require(ctx, operation.Type.permission()); err != nil {
+			return nil, err
+		}
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	if !atomic {
+		return useCase.apply(ctx, repository, operations), nil
+	}
+
+	var results []BatchResult
+
+	err := repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		results = useCase.apply(ctx, repository, operations)
+
+		for _, result := range results {
+			if result.Err != nil {
+				return ErrBatchAborted
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, ErrBatchAborted) {
+		return nil, err
+	}
+
+	if err != nil {
+		// Nada foi gravado: os itens que tinham dado certo não devolvem categoria
+		for i := range results {
+			results[i].Category = nil
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// apply executa as operações uma a uma, na ordem do lote, cada uma com a sua
+// transação (dentro da do lote, no modo atômico): uma operação enxerga as
+// anteriores, como se tivessem sido enviadas em requisições separadas.
+func (useCase *batchCategoriesUseCase) apply(ctx context.Context, repository repositories.ICategoryRepository, operations []BatchOperation) []BatchResult {
+	results := make([]BatchResult, len(operations))
+
+	for i, operation := range operations {
+		results[i].Operation = operation
+
+		switch operation.Type {
+		case BatchCreate:
+			results[i].Category, results[i].Err = NewCreateCategoryUseCase(repository).Execute(ctx, operation.Name)
+
+		case BatchUpdate:
+			results[i].Category, results[i].Err = NewUpdateCategoryUseCase(repository).Execute(ctx, operation.ID, operation.Name, operation.Version)
+
+		case BatchDelete:
+			results[i].Err = NewDeleteCategoryUseCase(repository).Execute(ctx, operation.ID, operation.Version)
+
+		default:
+			results[i].Err = &entities.ValidationError{
+				Field:   "op",
+				Message: fmt.Sprintf("unknown batch operation %q", operation.Type),
+			}
+		}
+	}
+
+	return results
+}
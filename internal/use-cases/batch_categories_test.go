@@ -0,0 +1,214 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+)
+
+func TestBatchCategoriesUseCase_Atomic_Success(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	existing, _ := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Old Category")
+	removed, _ := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Removed Category")
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	operations := []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchUpdate, ID: existing.ID, Name: "Renamed Category", Version: existing.Version},
+		{Type: BatchCreate, Name: "Books & Literature"},
+		{Type: BatchDelete, ID: removed.ID, Version: removed.Version},
+	}
+
+	// Act
+	results, err := useCase.Execute(asRole("admin"), operations, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("Operation %d: expected no error, got %v", i, result.Err)
+		}
+	}
+
+	if results[0].Category == nil || results[0].Category.ID == 0 {
+		t.Error("Expected created category to have an ID")
+	}
+
+	categories, _ := repo.List()
+	names := map[string]bool{}
+	for _, category := range categories {
+		names[category.Name] = true
+	}
+
+	if len(categories) != 3 || !names["Electronics"] || !names["Books & Literature"] || !names["Renamed Category"] {
+		t.Errorf("Unexpected categories after batch: %v", names)
+	}
+}
+
+func TestBatchCategoriesUseCase_Atomic_RollsBackOnInvalidItem(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	existing, _ := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Old Category")
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	operations := []BatchOperation{
+		{Type: BatchUpdate, ID: existing.ID, Name: "Renamed Category"},
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "abc"},
+	}
+
+	// Act
+	results, err := useCase.Execute(asRole("admin"), operations, true)
+
+	// Assert
+	if !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("Expected ErrBatchAborted, got %v", err)
+	}
+
+	var validationError *entities.ValidationError
+	if !errors.As(results[2].Err, &validationError) {
+		t.Errorf("Expected validation error on item 2, got %v", results[2].Err)
+	}
+
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("Expected only item 2 to fail, got %v and %v", results[0].Err, results[1].Err)
+	}
+
+	categories, _ := repo.List()
+	if len(categories) != 1 || categories[0].Name != "Old Category" {
+		t.Errorf("Expected repository to be untouched, got %d categories (first %s)", len(categories), categories[0].Name)
+	}
+}
+
+func TestBatchCategoriesUseCase_BestEffort_ReportsPerItem(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	operations := []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "abc"},
+		{Type: BatchDelete, ID: 42},
+		{Type: BatchCreate, Name: "Home & Garden"},
+	}
+
+	// Act
+	results, err := useCase.Execute(asRole("admin"), operations, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if results[0].Err != nil || results[3].Err != nil {
+		t.Errorf("Expected valid creates to succeed, got %v and %v", results[0].Err, results[3].Err)
+	}
+
+	if results[1].Err == nil {
+		t.Error("Expected invalid name to fail")
+	}
+
+	if !errors.Is(results[2].Err, repositories.ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got %v", results[2].Err)
+	}
+
+	categories, _ := repo.List()
+	if len(categories) != 2 {
+		t.Errorf("Expected 2 categories, got %d", len(categories))
+	}
+}
+
+func TestBatchCategoriesUseCase_BestEffort_SaveError(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("repository error")
+	mockRepo := &mockCategoryRepository{saveError: expectedError}
+	useCase := NewBatchCategoriesUseCase(mockRepo)
+
+	// Act
+	results, err := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "Home & Garden"},
+	}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, result := range results {
+		if result.Err != expectedError {
+			t.Errorf("Operation %d: expected repository error, got %v", i, result.Err)
+		}
+	}
+}
+
+func TestBatchCategoriesUseCase_BestEffort_DuplicateOnlyFailsItsItem(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Duplicated")
+	auditLog := repo
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	// Act
+	results, err := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Fresh Name"},
+		{Type: BatchCreate, Name: "Duplicated"},
+	}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if results[0].Err != nil || results[0].Category == nil || results[0].Category.Name != "Fresh Name" {
+		t.Errorf("Expected the fresh name to be created, got %v, %v", results[0].Category, results[0].Err)
+	}
+
+	if !errors.Is(results[1].Err, repositories.ErrDuplicateName) || results[1].Category != nil {
+		t.Errorf("Expected only the duplicated name to fail, got %v", results[1].Err)
+	}
+
+	categories, _ := repo.List()
+	if len(categories) != 2 {
+		t.Errorf("Expected the existing and the fresh category, got %d", len(categories))
+	}
+
+	if entries, _ := auditLog.ListByCategory("default", results[0].Category.ID); len(entries) != 1 {
+		t.Errorf("Expected 1 audit entry for the fresh category, got %d", len(entries))
+	}
+}
+
+func TestBatchCategoriesUseCase_AppliesOperationsInOrder(t *testing.T) {
+	for _, atomic := range []bool{false, true} {
+		// Arrange
+		repo := repositories.NewInMemotyCategoryRepository()
+		books, _ := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Books")
+		useCase := NewBatchCategoriesUseCase(repo)
+
+		// Act
+		results, _ := useCase.Execute(asRole("admin"), []BatchOperation{
+			{Type: BatchCreate, Name: "Electronics"},
+			{Type: BatchUpdate, ID: books.ID, Name: "Electronics"},
+		}, atomic)
+
+		// Assert
+		if results[0].Err != nil {
+			t.Errorf("atomic=%v: expected the create to be applied first, got %v", atomic, results[0].Err)
+		}
+
+		if !errors.Is(results[1].Err, repositories.ErrDuplicateName) {
+			t.Errorf("atomic=%v: expected the later rename to conflict with the create, got %v", atomic, results[1].Err)
+		}
+
+		found, _ := repo.FindByID(books.ID)
+		if found.Name != "Books" {
+			t.Errorf("atomic=%v: expected Books not to be renamed, got %q", atomic, found.Name)
+		}
+	}
+}
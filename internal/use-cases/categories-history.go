@@ -0,0 +1,29 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+)
+
+type categoriesHistoryUseCase struct {
+	auditLog audit.IStore
+}
+
+func NewCategoriesHistoryUseCase(auditLog audit.IStore) *categoriesHistoryUseCase {
+	return &categoriesHistoryUseCase{
+		auditLog,
+	}
+}
+
+// Execute devolve o histórico de várias categorias numa só consulta ao log.
+// Diferente do categoryHistoryUseCase, uma categoria sem entradas não é
+// erro: ela só fica de fora do map.
+func (useCase *categoriesHistoryUseCase) Execute(ctx context.Context, ids []uint) (map[uint][]*audit.Entry, error) {
+	if err := authz.Require(ctx, authz.CategoriesHistory); err != nil {
+		return nil, err
+	}
+
+	return useCase.auditLog.ListByCategories(tenancy.From(ctx), ids)
+}
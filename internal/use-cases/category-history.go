@@ -0,0 +1,40 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type categoryHistoryUseCase struct {
+	auditLog audit.IStore
+}
+
+func NewCategoryHistoryUseCase(auditLog audit.IStore) *categoryHistoryUseCase {
+	return &categoryHistoryUseCase{
+		auditLog,
+	}
+}
+
+// Execute devolve as alterações da categoria em ordem cronológica. Categorias
+// removidas continuam com histórico; sem nenhuma entrada a categoria nunca
+// existiu (ErrCategoryNotFound).
+func (useCase *categoryHistoryUseCase) Execute(ctx context.Context, id uint) ([]*audit.Entry, error) {
+	if err := authz.Require(ctx, authz.CategoriesHistory); err != nil {
+		return nil, err
+	}
+
+	entries, err := useCase.auditLog.ListByCategory(tenancy.From(ctx), id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, repositories.ErrCategoryNotFound
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,105 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/events/eventstest"
+	"gin-quickstart/internal/outbox"
+	"gin-quickstart/internal/repositories"
+)
+
+// relayTo entrega ao recorder tudo o que os use cases gravaram na outbox.
+func relayTo(t *testing.T, repo outbox.IStore) *eventstest.Recorder {
+	t.Helper()
+
+	recorder := eventstest.NewRecorder()
+	relay := outbox.NewRelay(repo, events.NewRegistry(entities.CategoryEvents...), recorder)
+
+	if _, err := relay.DispatchPending(asRole("admin")); err != nil {
+		t.Fatalf("Error dispatching outbox: %v", err)
+	}
+
+	return recorder
+}
+
+func TestCategoryUseCases_WriteDomainEventsToOutbox(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	ctx := asRole("admin")
+
+	// Act
+	created, _ := NewCreateCategoryUseCase(repo).Execute(ctx, "Electronics")
+	_, _ = NewUpdateCategoryUseCase(repo).Execute(ctx, created.ID, "Consumer Electronics", 0)
+	_ = NewDeleteCategoryUseCase(repo).Execute(ctx, created.ID, 0)
+	_, _ = NewRestoreCategoryUseCase(repo).Execute(ctx, created.ID)
+
+	recorder := relayTo(t, repo)
+
+	// Assert
+	recorder.AssertPublished(t, "category.created", "category.renamed", "category.deleted", "category.restored")
+
+	renamed, _ := eventstest.Last[entities.CategoryRenamed](recorder)
+	if renamed.ID != created.ID || renamed.OldName != "Electronics" || renamed.NewName != "Consumer Electronics" || renamed.Version != 2 {
+		t.Errorf("Unexpected CategoryRenamed %+v", renamed)
+	}
+
+	deleted, _ := eventstest.Last[entities.CategoryDeleted](recorder)
+	if deleted.Version != 3 {
+		t.Errorf("Expected CategoryDeleted at version 3, got %d", deleted.Version)
+	}
+
+	for _, message := range repo.OutboxMessages() {
+		if message.DispatchedAt == nil {
+			t.Errorf("Expected every message to be dispatched, got %+v", message)
+		}
+	}
+}
+
+func TestCategoryUseCases_NoEventOnFailure(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{saveError: errors.New("repository error")}
+
+	// Act
+	_, invalidErr := NewCreateCategoryUseCase(mockRepo).Execute(asRole("admin"), "abc")
+	_, saveErr := NewCreateCategoryUseCase(mockRepo).Execute(asRole("admin"), "Electronics")
+
+	// Assert
+	if invalidErr == nil || saveErr == nil {
+		t.Fatal("Expected both creates to fail")
+	}
+	if len(mockRepo.events) != 0 {
+		t.Errorf("Expected no events, got %d", len(mockRepo.events))
+	}
+}
+
+func TestBatchCategoriesUseCase_Atomic_WritesEventsOnlyOnCommit(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	// Act
+	_, abortedErr := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "abc"},
+	}, true)
+	messagesAfterAbort := len(repo.OutboxMessages())
+
+	_, err := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "Home & Garden"},
+	}, true)
+
+	recorder := relayTo(t, repo)
+
+	// Assert
+	if !errors.Is(abortedErr, ErrBatchAborted) || err != nil {
+		t.Fatalf("Expected first batch aborted and second applied, got %v and %v", abortedErr, err)
+	}
+	if messagesAfterAbort != 0 {
+		t.Errorf("Expected no outbox messages from the aborted batch, got %d", messagesAfterAbort)
+	}
+	recorder.AssertPublished(t, "category.created", "category.created")
+}
@@ -0,0 +1,129 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+func TestCategoryHistoryUseCase_RecordsEveryChange(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	auditLog := repo
+	ctx := audit.WithRequestID(audit.WithActor(asRole("admin"), "maria"), "req-1")
+
+	// Act
+	created, err := NewCreateCategoryUseCase(repo).Execute(ctx, "Electronics")
+	if err != nil {
+		t.Fatalf("Expected no error creating, got %v", err)
+	}
+	if _, err := NewUpdateCategoryUseCase(repo).Execute(ctx, created.ID, "Consumer Electronics", 0); err != nil {
+		t.Fatalf("Expected no error updating, got %v", err)
+	}
+	if err := NewDeleteCategoryUseCase(repo).Execute(ctx, created.ID, 0); err != nil {
+		t.Fatalf("Expected no error deleting, got %v", err)
+	}
+	restored, err := NewRestoreCategoryUseCase(repo).Execute(asRole("admin"), created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error restoring, got %v", err)
+	}
+
+	entries, err := NewCategoryHistoryUseCase(auditLog).Execute(asRole("admin"), created.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedActions := []audit.Action{audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete, audit.ActionRestore}
+	if len(entries) != len(expectedActions) {
+		t.Fatalf("Expected %d entries, got %d", len(expectedActions), len(entries))
+	}
+
+	for i, action := range expectedActions {
+		if entries[i].Action != action {
+			t.Errorf("Expected entry %d to be %s, got %s", i, action, entries[i].Action)
+		}
+	}
+
+	rename := entries[1]
+	if rename.Actor != "maria" || rename.RequestID != "req-1" {
+		t.Errorf("Expected actor maria and request req-1, got %s and %s", rename.Actor, rename.RequestID)
+	}
+	if rename.Before.Name != "Electronics" || rename.After.Name != "Consumer Electronics" {
+		t.Errorf("Expected rename snapshot Electronics -> Consumer Electronics, got %s -> %s", rename.Before.Name, rename.After.Name)
+	}
+
+	if entries[2].Before == nil || entries[2].After != nil {
+		t.Error("Expected delete entry with only the before snapshot")
+	}
+
+	if entries[3].Actor != audit.AnonymousActor {
+		t.Errorf("Expected anonymous actor without one in the context, got %s", entries[3].Actor)
+	}
+
+	if restored.Version != 4 {
+		t.Errorf("Expected restored version 4, got %d", restored.Version)
+	}
+}
+
+func TestCategoryHistoryUseCase_UnknownCategory(t *testing.T) {
+	// Arrange
+	useCase := NewCategoryHistoryUseCase(repositories.NewInMemotyCategoryRepository())
+
+	// Act
+	_, err := useCase.Execute(asRole("admin"), 42)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestRestoreCategoryUseCase_NotDeleted(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	created, _ := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Electronics")
+
+	// Act
+	_, err := NewRestoreCategoryUseCase(repo).Execute(asRole("admin"), created.ID)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestBatchCategoriesUseCase_Atomic_AuditsOnlyCommittedBatches(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	auditLog := repo
+	useCase := NewBatchCategoriesUseCase(repo)
+
+	// Act
+	_, abortedErr := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Electronics"},
+		{Type: BatchCreate, Name: "abc"},
+	}, true)
+	results, err := useCase.Execute(asRole("admin"), []BatchOperation{
+		{Type: BatchCreate, Name: "Home & Garden"},
+	}, true)
+
+	// Assert
+	if !errors.Is(abortedErr, ErrBatchAborted) || err != nil {
+		t.Fatalf("Expected first batch aborted and second applied, got %v and %v", abortedErr, err)
+	}
+
+	entries, _ := auditLog.ListByCategory(tenancy.Default, results[0].Category.ID)
+	if len(entries) != 1 || entries[0].Action != audit.ActionCreate {
+		t.Fatalf("Expected a single create entry for the committed batch, got %d", len(entries))
+	}
+
+	// O lote abortado não deixa rastro: a primeira categoria gravada é a do segundo lote
+	if results[0].Category.ID != 1 {
+		t.Errorf("Expected committed category to have ID 1, got %d", results[0].Category.ID)
+	}
+}
@@ -1,8 +1,12 @@
 package use_cases
 
 import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
 	"gin-quickstart/internal/entities"
 	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
 	"log"
 )
 
@@ -16,21 +20,41 @@ func NewCreateCategoryUseCase(repository repositories.ICategoryRepository) *crea
 	}
 }
 
-func (useCase *createCategoryUseCase) Execute(name string) error {
+func (useCase *createCategoryUseCase) Execute(ctx context.Context, name string) (*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesCreate); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
 	category, err := entities.NewCategory(name)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Todo persiste entity to db
 	log.Println(category)
 
-	err = useCase.repository.Save(category)
+	err = repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		if err := repository.Save(category); err != nil {
+			return err
+		}
+		if err := repository.AddEvents(entities.NewCategoryCreated(category)); err != nil {
+			return err
+		}
+		return repository.AddAudit(audit.NewEntry(ctx, audit.ActionCreate, nil, category))
+	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
-}
\ No newline at end of file
+	return category, nil
+}
+
+// Validate aplica as mesmas regras do Execute sem gravar nada.
+func (useCase *createCategoryUseCase) Validate(name string) error {
+	_, err := entities.NewCategory(name)
+	return err
+}
@@ -0,0 +1,96 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+	"net/url"
+	"slices"
+	"time"
+)
+
+type createWebhookUseCase struct {
+	store   webhooks.IStore
+	targets webhooks.Targets
+}
+
+func NewCreateWebhookUseCase(store webhooks.IStore, targets webhooks.Targets) *createWebhookUseCase {
+	return &createWebhookUseCase{
+		store,
+		targets,
+	}
+}
+
+// Execute cadastra a assinatura. eventNames aceita os nomes dos eventos de
+// categoria ou webhooks.AllEvents; sem secret um é gerado, e só é devolvido
+// aqui, na criação. URLs da rede interna são recusadas (veja
+// webhooks.Targets).
+func (useCase *createWebhookUseCase) Execute(ctx context.Context, endpoint string, eventNames []string, secret string) (*webhooks.Subscription, error) {
+	if err := authz.Require(ctx, authz.WebhooksManage); err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(endpoint)
+
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, &entities.ValidationError{
+			Field:   "url",
+			Message: fmt.Sprintf("url must be an absolute http or https URL, got %q", endpoint),
+		}
+	}
+
+	if err := useCase.targets.Check(ctx, parsed.Hostname()); err != nil {
+		return nil, &entities.ValidationError{
+			Field:   "url",
+			Message: fmt.Sprintf("url must not point to an internal address, got %q", endpoint),
+		}
+	}
+
+	if len(eventNames) == 0 {
+		return nil, &entities.ValidationError{Field: "events", Message: "events must not be empty"}
+	}
+
+	for _, name := range eventNames {
+		if !isWebhookEvent(name) {
+			return nil, &entities.ValidationError{
+				Field:   "events",
+				Message: fmt.Sprintf("unknown event %q", name),
+			}
+		}
+	}
+
+	if secret == "" {
+		secret = webhooks.NewSecret()
+	}
+
+	subscription := &webhooks.Subscription{
+		TenantID:  tenancy.From(ctx),
+		URL:       endpoint,
+		Secret:    secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(eventNames))),
+		CreatedAt: time.Now(),
+	}
+
+	if err := useCase.store.SaveSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func isWebhookEvent(name string) bool {
+	if name == webhooks.AllEvents {
+		return true
+	}
+
+	for _, event := range entities.CategoryEvents {
+		if event.EventName() == name {
+			return true
+		}
+	}
+
+	return false
+}
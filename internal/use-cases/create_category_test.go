@@ -1,11 +1,16 @@
 package use_cases
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
+	"gin-quickstart/internal/audit"
 	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events"
 	"gin-quickstart/internal/repositories"
 )
 
@@ -14,6 +19,13 @@ type mockCategoryRepository struct {
 	savedCategories []*entities.Category
 	saveError      error
 	listError      error
+	auditError     error
+	events         []events.Event
+	auditEntries   []*audit.Entry
+	// tenantMutex protege tenant: os testes de concorrência chamam ForTenant
+	// de várias goroutines
+	tenantMutex sync.Mutex
+	tenant      string
 }
 
 func (m *mockCategoryRepository) Save(category *entities.Category) error {
@@ -24,6 +36,18 @@ func (m *mockCategoryRepository) Save(category *entities.Category) error {
 	return nil
 }
 
+func (m *mockCategoryRepository) SaveMany(categories []*entities.Category) error {
+	if m.saveError != nil {
+		return m.saveError
+	}
+	for _, category := range categories {
+		if err := m.Save(category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *mockCategoryRepository) List() ([]*entities.Category, error) {
 	if m.listError != nil {
 		return nil, m.listError
@@ -31,6 +55,134 @@ func (m *mockCategoryRepository) List() ([]*entities.Category, error) {
 	return m.savedCategories, nil
 }
 
+func (m *mockCategoryRepository) Stream(fn func(category *entities.Category) error) error {
+	if m.listError != nil {
+		return m.listError
+	}
+	for _, category := range m.savedCategories {
+		if err := fn(category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockCategoryRepository) ListPage(afterID uint, limit int) ([]*entities.Category, int, error) {
+	categories := []*entities.Category{}
+	for _, category := range m.savedCategories {
+		if category.ID > afterID && len(categories) < limit {
+			categories = append(categories, category)
+		}
+	}
+	return categories, len(m.savedCategories), nil
+}
+
+func (m *mockCategoryRepository) FindByID(id uint) (*entities.Category, error) {
+	for _, category := range m.savedCategories {
+		if category.ID == id {
+			return category, nil
+		}
+	}
+	return nil, repositories.ErrCategoryNotFound
+}
+
+func (m *mockCategoryRepository) FindByIDs(ids []uint) ([]*entities.Category, error) {
+	categories := []*entities.Category{}
+	for _, id := range ids {
+		if category, err := m.FindByID(id); err == nil {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryRepository) FindChildren(parentIDs []uint) ([]*entities.Category, error) {
+	categories := []*entities.Category{}
+	for _, category := range m.savedCategories {
+		if category.ParentID != nil && slices.Contains(parentIDs, *category.ParentID) {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryRepository) Update(category *entities.Category) error {
+	if m.saveError != nil {
+		return m.saveError
+	}
+	for i, saved := range m.savedCategories {
+		if saved.ID == category.ID {
+			if saved.Version != category.Version {
+				return &repositories.VersionConflictError{ID: category.ID, ExpectedVersion: category.Version}
+			}
+			category.Version++
+			m.savedCategories[i] = category
+			return nil
+		}
+	}
+	return repositories.ErrCategoryNotFound
+}
+
+func (m *mockCategoryRepository) Delete(id uint, expectedVersion uint) error {
+	for i, saved := range m.savedCategories {
+		if saved.ID == id {
+			if saved.Version != expectedVersion {
+				return &repositories.VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+			}
+			m.savedCategories = append(m.savedCategories[:i], m.savedCategories[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrCategoryNotFound
+}
+
+// O mock remove de verdade, então não há o que restaurar
+func (m *mockCategoryRepository) Restore(id uint) (*entities.Category, error) {
+	return nil, repositories.ErrCategoryNotFound
+}
+
+// Transaction do mock restaura as categorias salvas e os eventos quando fn falha
+func (m *mockCategoryRepository) LockHierarchy() error {
+	return nil
+}
+
+func (m *mockCategoryRepository) Transaction(fn func(repository repositories.ICategoryRepository) error) error {
+	snapshot := make([]*entities.Category, len(m.savedCategories))
+	copy(snapshot, m.savedCategories)
+	eventCount := len(m.events)
+	auditCount := len(m.auditEntries)
+
+	if err := fn(m); err != nil {
+		m.savedCategories = snapshot
+		m.events = m.events[:eventCount]
+		m.auditEntries = m.auditEntries[:auditCount]
+		return err
+	}
+	return nil
+}
+
+// ForTenant do mock só registra o último tenant pedido
+func (m *mockCategoryRepository) ForTenant(tenant string) repositories.ICategoryRepository {
+	m.tenantMutex.Lock()
+	defer m.tenantMutex.Unlock()
+
+	m.tenant = tenant
+	return m
+}
+
+func (m *mockCategoryRepository) AddEvents(published ...events.Event) error {
+	m.events = append(m.events, published...)
+	return nil
+}
+
+func (m *mockCategoryRepository) AddAudit(entries ...*audit.Entry) error {
+	if m.auditError != nil {
+		return m.auditError
+	}
+	m.auditEntries = append(m.auditEntries, entries...)
+	return nil
+}
+
 func TestCreateCategoryUseCase_Execute_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockCategoryRepository{}
@@ -38,7 +190,7 @@ func TestCreateCategoryUseCase_Execute_Success(t *testing.T) {
 	categoryName := "Test Category"
 
 	// Act
-	err := useCase.Execute(categoryName)
+	_, err := useCase.Execute(asRole("admin"), categoryName)
 
 	// Assert
 	if err != nil {
@@ -70,7 +222,7 @@ func TestCreateCategoryUseCase_Execute_InvalidName(t *testing.T) {
 	invalidName := "abc" // Nome muito curto (< 5 caracteres)
 
 	// Act
-	err := useCase.Execute(invalidName)
+	_, err := useCase.Execute(asRole("admin"), invalidName)
 
 	// Assert
 	if err == nil {
@@ -97,7 +249,7 @@ func TestCreateCategoryUseCase_Execute_RepositoryError(t *testing.T) {
 	categoryName := "Valid Category Name"
 
 	// Act
-	err := useCase.Execute(categoryName)
+	_, err := useCase.Execute(asRole("admin"), categoryName)
 
 	// Assert
 	if err == nil {
@@ -109,6 +261,25 @@ func TestCreateCategoryUseCase_Execute_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestCreateCategoryUseCase_Execute_AuditErrorRollsBackTheCategory(t *testing.T) {
+	// Arrange
+	auditErr := fmt.Errorf("audit log unavailable")
+	mockRepo := &mockCategoryRepository{auditError: auditErr}
+	useCase := NewCreateCategoryUseCase(mockRepo)
+
+	// Act
+	category, err := useCase.Execute(asRole("admin"), "Electronics")
+
+	// Assert
+	if !errors.Is(err, auditErr) || category != nil {
+		t.Fatalf("Expected the audit error, got %v (%v)", err, category)
+	}
+
+	if len(mockRepo.savedCategories) != 0 || len(mockRepo.events) != 0 {
+		t.Errorf("Expected nothing saved without the audit entry, got %d categories and %d events", len(mockRepo.savedCategories), len(mockRepo.events))
+	}
+}
+
 // Teste de integração usando o repositório in-memory real
 func TestCreateCategoryUseCase_Integration(t *testing.T) {
 	// Arrange
@@ -117,7 +288,7 @@ func TestCreateCategoryUseCase_Integration(t *testing.T) {
 	categoryName := "Integration Test Category"
 
 	// Act
-	err := useCase.Execute(categoryName)
+	_, err := useCase.Execute(asRole("admin"), categoryName)
 
 	// Assert
 	if err != nil {
@@ -147,7 +318,7 @@ func BenchmarkCreateCategoryUseCase_Execute(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = useCase.Execute(categoryName)
+		_, _ = useCase.Execute(asRole("admin"), categoryName)
 	}
 }
 
@@ -188,7 +359,7 @@ func TestCreateCategoryUseCase_TableDriven(t *testing.T) {
 			mockRepo := &mockCategoryRepository{}
 			useCase := NewCreateCategoryUseCase(mockRepo)
 
-			err := useCase.Execute(tt.categoryName)
+			_, err := useCase.Execute(asRole("admin"), tt.categoryName)
 
 			if tt.expectError {
 				if err == nil {
@@ -0,0 +1,56 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type deleteCategoryUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewDeleteCategoryUseCase(repository repositories.ICategoryRepository) *deleteCategoryUseCase {
+	return &deleteCategoryUseCase{
+		repository,
+	}
+}
+
+// Execute remove a categoria se ela ainda estiver em expectedVersion.
+// Com expectedVersion 0 a versão atual é usada como esperada.
+func (useCase *deleteCategoryUseCase) Execute(ctx context.Context, id uint, expectedVersion uint) error {
+	if err := authz.Require(ctx, authz.CategoriesDelete); err != nil {
+		return err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	category, err := repository.FindByID(id)
+
+	if err != nil {
+		return err
+	}
+
+	if expectedVersion == 0 {
+		expectedVersion = category.Version
+	}
+
+	err = repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		if err := repository.Delete(id, expectedVersion); err != nil {
+			return err
+		}
+		if err := repository.AddEvents(entities.NewCategoryDeleted(category)); err != nil {
+			return err
+		}
+		return repository.AddAudit(audit.NewEntry(ctx, audit.ActionDelete, category, nil))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/webhooks"
+)
+
+type deleteWebhookUseCase struct {
+	store webhooks.IStore
+}
+
+func NewDeleteWebhookUseCase(store webhooks.IStore) *deleteWebhookUseCase {
+	return &deleteWebhookUseCase{
+		store,
+	}
+}
+
+// Execute remove a assinatura junto com o seu log de entregas; entregas
+// pendentes deixam de ser enviadas.
+func (useCase *deleteWebhookUseCase) Execute(ctx context.Context, id uint) error {
+	if err := authz.Require(ctx, authz.WebhooksManage); err != nil {
+		return err
+	}
+
+	if _, err := findSubscription(ctx, useCase.store, id); err != nil {
+		return err
+	}
+
+	return useCase.store.DeleteSubscription(id)
+}
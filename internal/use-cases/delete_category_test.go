@@ -0,0 +1,99 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/repositories"
+)
+
+func TestDeleteCategoryUseCase_Execute_Success(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewDeleteCategoryUseCase(mockRepo)
+
+	// Act
+	err := useCase.Execute(asRole("admin"), 2, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockRepo.savedCategories) != 2 {
+		t.Fatalf("Expected 2 categories left, got %d", len(mockRepo.savedCategories))
+	}
+
+	for _, category := range mockRepo.savedCategories {
+		if category.ID == 2 {
+			t.Error("Expected category 2 to be deleted")
+		}
+	}
+}
+
+func TestDeleteCategoryUseCase_Execute_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{}
+	useCase := NewDeleteCategoryUseCase(mockRepo)
+
+	// Act
+	err := useCase.Execute(asRole("admin"), 42, 0)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+// Teste de integração usando o repositório in-memory real
+func TestDeleteCategoryUseCase_Integration(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	createUseCase := NewCreateCategoryUseCase(repo)
+	deleteUseCase := NewDeleteCategoryUseCase(repo)
+	listUseCase := NewListCategoriesUseCase(repo)
+
+	category, err := createUseCase.Execute(asRole("admin"), "Electronics")
+	if err != nil {
+		t.Fatalf("Error creating category: %v", err)
+	}
+
+	// Act
+	err = deleteUseCase.Execute(asRole("admin"), category.ID, category.Version)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	categories, err := listUseCase.Execute(asRole("admin"))
+	if err != nil {
+		t.Fatalf("Error listing categories: %v", err)
+	}
+
+	if len(categories) != 0 {
+		t.Fatalf("Expected empty list, got %d categories", len(categories))
+	}
+}
+
+func TestDeleteCategoryUseCase_Execute_StaleVersion(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewDeleteCategoryUseCase(mockRepo)
+
+	// Act
+	err := useCase.Execute(asRole("admin"), 2, 5)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+
+	if len(mockRepo.savedCategories) != 3 {
+		t.Errorf("Expected no category to be deleted, got %d left", len(mockRepo.savedCategories))
+	}
+}
@@ -0,0 +1,43 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type exportCategoriesUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewExportCategoriesUseCase(repository repositories.ICategoryRepository) *exportCategoriesUseCase {
+	return &exportCategoriesUseCase{
+		repository,
+	}
+}
+
+// Execute escreve as categorias uma a uma no writer e retorna quantas foram
+// exportadas.
+func (useCase *exportCategoriesUseCase) Execute(ctx context.Context, writer categoryio.Writer) (int, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return 0, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	count := 0
+
+	err := repository.Stream(func(category *entities.Category) error {
+		count++
+		return writer.Write(category)
+	})
+
+	if err != nil {
+		return count, err
+	}
+
+	return count, writer.Flush()
+}
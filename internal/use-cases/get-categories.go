@@ -0,0 +1,42 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type getCategoriesUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewGetCategoriesUseCase(repository repositories.ICategoryRepository) *getCategoriesUseCase {
+	return &getCategoriesUseCase{
+		repository,
+	}
+}
+
+// Execute busca várias categorias numa só consulta, indexadas pelo ID. As
+// que não existem ficam de fora do map.
+func (useCase *getCategoriesUseCase) Execute(ctx context.Context, ids []uint) (map[uint]*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	categories, err := repository.FindByIDs(ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]*entities.Category, len(categories))
+	for _, category := range categories {
+		found[category.ID] = category
+	}
+
+	return found, nil
+}
@@ -0,0 +1,35 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type getCategoryUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewGetCategoryUseCase(repository repositories.ICategoryRepository) *getCategoryUseCase {
+	return &getCategoryUseCase{
+		repository,
+	}
+}
+
+func (useCase *getCategoryUseCase) Execute(ctx context.Context, id uint) (*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	category, err := repository.FindByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
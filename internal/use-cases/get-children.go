@@ -0,0 +1,42 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type getChildrenUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewGetChildrenUseCase(repository repositories.ICategoryRepository) *getChildrenUseCase {
+	return &getChildrenUseCase{
+		repository,
+	}
+}
+
+// Execute busca as filhas de várias categorias numa só consulta, indexadas
+// pelo ID do pai. Uma categoria sem filhas fica de fora do map.
+func (useCase *getChildrenUseCase) Execute(ctx context.Context, parentIDs []uint) (map[uint][]*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	children, err := repository.FindChildren(parentIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint][]*entities.Category)
+	for _, child := range children {
+		found[*child.ParentID] = append(found[*child.ParentID], child)
+	}
+
+	return found, nil
+}
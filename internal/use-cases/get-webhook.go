@@ -0,0 +1,42 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+type getWebhookUseCase struct {
+	store webhooks.IStore
+}
+
+func NewGetWebhookUseCase(store webhooks.IStore) *getWebhookUseCase {
+	return &getWebhookUseCase{
+		store,
+	}
+}
+
+func (useCase *getWebhookUseCase) Execute(ctx context.Context, id uint) (*webhooks.Subscription, error) {
+	if err := authz.Require(ctx, authz.WebhooksRead); err != nil {
+		return nil, err
+	}
+
+	return findSubscription(ctx, useCase.store, id)
+}
+
+// findSubscription só encontra assinaturas do tenant do context: as dos
+// demais são ErrSubscriptionNotFound.
+func findSubscription(ctx context.Context, store webhooks.IStore, id uint) (*webhooks.Subscription, error) {
+	subscription, err := store.FindSubscription(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tenancy.Of(subscription) != tenancy.From(ctx) {
+		return nil, webhooks.ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,75 @@
+package use_cases
+
+import (
+	"testing"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/repositories"
+)
+
+func TestGetCategoriesUseCase_IgnoresMissingAndDeleted(t *testing.T) {
+	// Arrange
+	fixture := newTenancyFixture(t)
+	useCase := NewGetCategoriesUseCase(fixture.repo)
+
+	// Act
+	found, err := useCase.Execute(asTenant("acme"), []uint{fixture.active, fixture.deleted, 42})
+	other, _ := useCase.Execute(asTenant("globex"), []uint{fixture.active})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(found) != 1 || found[fixture.active] == nil || found[fixture.active].Name != "Electronics" {
+		t.Errorf("Expected only the active category, got %v", found)
+	}
+
+	if len(other) != 0 {
+		t.Errorf("Expected no categories from another tenant, got %v", other)
+	}
+}
+
+func TestGetCategoriesUseCase_RequiresReadPermission(t *testing.T) {
+	// Arrange
+	useCase := NewGetCategoriesUseCase(repositories.NewInMemotyCategoryRepository())
+
+	// Act
+	_, err := useCase.Execute(asRole(), []uint{1})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected a forbidden error without roles, got nil")
+	}
+}
+
+func TestCategoriesHistoryUseCase_GroupsEntriesByCategory(t *testing.T) {
+	// Arrange
+	fixture := newTenancyFixture(t)
+	useCase := NewCategoriesHistoryUseCase(fixture.auditLog)
+
+	// Act
+	history, err := useCase.Execute(asTenant("acme"), []uint{fixture.active, fixture.deleted, 42})
+	other, _ := useCase.Execute(asTenant("globex"), []uint{fixture.active})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if entries := history[fixture.active]; len(entries) != 1 || entries[0].Action != audit.ActionCreate {
+		t.Errorf("Expected the create entry for the active category, got %v", entries)
+	}
+
+	if entries := history[fixture.deleted]; len(entries) != 2 || entries[1].Action != audit.ActionDelete {
+		t.Errorf("Expected create and delete entries for the deleted category, got %v", entries)
+	}
+
+	if _, ok := history[42]; ok {
+		t.Error("Expected no entry for an unknown category")
+	}
+
+	if len(other) != 0 {
+		t.Errorf("Expected no history from another tenant, got %v", other)
+	}
+}
@@ -0,0 +1,81 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/repositories"
+)
+
+func TestGetCategoryUseCase_Execute_Success(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewGetCategoryUseCase(mockRepo)
+
+	// Act
+	category, err := useCase.Execute(asRole("admin"), 2)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if category.Name != "Category 2" {
+		t.Errorf("Expected category name Category 2, got %s", category.Name)
+	}
+}
+
+func TestGetCategoryUseCase_Execute_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewGetCategoryUseCase(mockRepo)
+
+	// Act
+	category, err := useCase.Execute(asRole("admin"), 42)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+
+	if category != nil {
+		t.Error("Expected nil category on error, got non-nil")
+	}
+}
+
+// Teste de integração: o repositório in-memory atribui IDs sequenciais
+func TestGetCategoryUseCase_Integration(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	createUseCase := NewCreateCategoryUseCase(repo)
+	getUseCase := NewGetCategoryUseCase(repo)
+
+	first, err := createUseCase.Execute(asRole("admin"), "First Category")
+	if err != nil {
+		t.Fatalf("Error creating category: %v", err)
+	}
+	second, err := createUseCase.Execute(asRole("admin"), "Second Category")
+	if err != nil {
+		t.Fatalf("Error creating category: %v", err)
+	}
+
+	if first.ID == 0 || first.ID == second.ID {
+		t.Fatalf("Expected distinct non-zero IDs, got %d and %d", first.ID, second.ID)
+	}
+
+	// Act
+	category, err := getUseCase.Execute(asRole("admin"), second.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if category.Name != "Second Category" {
+		t.Errorf("Expected category name Second Category, got %s", category.Name)
+	}
+}
@@ -0,0 +1,124 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+	"io"
+)
+
+type ImportError struct {
+	Line    int
+	Message string
+}
+
+type ImportReport struct {
+	DryRun   bool
+	Total    int
+	Imported int
+	Failed   int
+	Errors   []ImportError
+}
+
+type importCategoriesUseCase struct {
+	repository    repositories.ICategoryRepository
+	createUseCase *createCategoryUseCase
+}
+
+func NewImportCategoriesUseCase(repository repositories.ICategoryRepository) *importCategoriesUseCase {
+	return &importCategoriesUseCase{
+		repository,
+		NewCreateCategoryUseCase(repository),
+	}
+}
+
+// Execute passa cada linha pelo use case de criação e devolve um relatório
+// com os erros por linha. Linhas inválidas não interrompem a importação.
+// Com dryRun=true nada é gravado: as linhas são validadas e os nomes
+// comparados com os das categorias ativas e das linhas anteriores, e
+// Imported passa a contar as linhas que seriam importadas.
+func (useCase *importCategoriesUseCase) Execute(ctx context.Context, reader categoryio.Reader, dryRun bool) (*ImportReport, error) {
+	if err := authz.Require(ctx, authz.CategoriesCreate); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: dryRun, Errors: make([]ImportError, 0)}
+
+	var taken map[string]bool
+	if dryRun {
+		var err error
+		if taken, err = useCase.takenNames(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	for {
+		row, err := reader.Next()
+
+		if err == io.EOF {
+			return report, nil
+		}
+
+		var rowError *categoryio.RowError
+		if errors.As(err, &rowError) {
+			report.Total++
+			report.fail(rowError.Line, rowError.Err)
+			continue
+		}
+
+		if err != nil {
+			return report, err
+		}
+
+		report.Total++
+
+		if dryRun {
+			err = useCase.createUseCase.Validate(row.Name)
+			if err == nil && taken[row.Name] {
+				err = &repositories.DuplicateNameError{Name: row.Name}
+			}
+		} else {
+			_, err = useCase.createUseCase.Execute(ctx, row.Name)
+		}
+
+		var validationError *entities.ValidationError
+		if errors.As(err, &validationError) || errors.Is(err, repositories.ErrDuplicateName) {
+			report.fail(row.Line, err)
+			continue
+		}
+
+		// Erros de infraestrutura (ex: banco fora) interrompem a importação
+		if err != nil {
+			return report, err
+		}
+
+		if dryRun {
+			taken[row.Name] = true
+		}
+		report.Imported++
+	}
+}
+
+// takenNames são os nomes das categorias ativas do tenant, que a importação
+// de verdade recusaria com ErrDuplicateName.
+func (useCase *importCategoriesUseCase) takenNames(ctx context.Context) (map[string]bool, error) {
+	categories, err := useCase.repository.ForTenant(tenancy.From(ctx)).List()
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		taken[category.Name] = true
+	}
+	return taken, nil
+}
+
+func (report *ImportReport) fail(line int, err error) {
+	report.Failed++
+	report.Errors = append(report.Errors, ImportError{Line: line, Message: err.Error()})
+}
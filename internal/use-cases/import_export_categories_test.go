@@ -0,0 +1,137 @@
+package use_cases
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/repositories"
+)
+
+func TestImportCategoriesUseCase_Execute_ReportsLineErrors(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	useCase := NewImportCategoriesUseCase(repo)
+	reader, _ := categoryio.NewReader(categoryio.CSV, strings.NewReader("name\nElectronics\nabc\nHome & Garden\n"))
+
+	// Act
+	report, err := useCase.Execute(asRole("admin"), reader, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Total != 3 || report.Imported != 2 || report.Failed != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	if len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Fatalf("Expected error on line 3, got %+v", report.Errors)
+	}
+
+	if report.Errors[0].Message != "name must be greater than 5, got 3" {
+		t.Errorf("Unexpected message %s", report.Errors[0].Message)
+	}
+
+	categories, _ := repo.List()
+	if len(categories) != 2 {
+		t.Errorf("Expected 2 categories, got %d", len(categories))
+	}
+}
+
+func TestImportCategoriesUseCase_Execute_DryRun(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	useCase := NewImportCategoriesUseCase(repo)
+	reader, _ := categoryio.NewReader(categoryio.JSONL, strings.NewReader("{\"name\":\"Electronics\"}\n{\"name\":\"abc\"}\n"))
+
+	// Act
+	report, err := useCase.Execute(asRole("admin"), reader, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.DryRun || report.Imported != 1 || report.Failed != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	categories, _ := repo.List()
+	if len(categories) != 0 {
+		t.Errorf("Expected dry run to save nothing, got %d categories", len(categories))
+	}
+}
+
+func TestImportCategoriesUseCase_Execute_DryRunReportsDuplicateNames(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Electronics")
+	useCase := NewImportCategoriesUseCase(repo)
+	reader, _ := categoryio.NewReader(categoryio.CSV, strings.NewReader("name\nElectronics\nHome & Garden\nHome & Garden\n"))
+
+	// Act
+	report, err := useCase.Execute(asRole("admin"), reader, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Total != 3 || report.Imported != 1 || report.Failed != 2 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	if len(report.Errors) != 2 || report.Errors[0].Line != 2 || report.Errors[1].Line != 4 {
+		t.Fatalf("Expected errors on lines 2 and 4, got %+v", report.Errors)
+	}
+
+	if report.Errors[1].Message != `a category named "Home & Garden" already exists` {
+		t.Errorf("Unexpected message %s", report.Errors[1].Message)
+	}
+}
+
+func TestImportCategoriesUseCase_Execute_RepositoryError(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("repository error")
+	useCase := NewImportCategoriesUseCase(&mockCategoryRepository{saveError: expectedError})
+	reader, _ := categoryio.NewReader(categoryio.CSV, strings.NewReader("name\nElectronics\n"))
+
+	// Act
+	_, err := useCase.Execute(asRole("admin"), reader, false)
+
+	// Assert
+	if err != expectedError {
+		t.Errorf("Expected repository error, got %v", err)
+	}
+}
+
+func TestExportCategoriesUseCase_Execute(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewExportCategoriesUseCase(mockRepo)
+	var buffer bytes.Buffer
+	writer, _ := categoryio.NewWriter(categoryio.CSV, &buffer)
+
+	// Act
+	count, err := useCase.Execute(asRole("admin"), writer)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 exported categories, got %d", count)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "1,Category 1,") {
+		t.Errorf("Unexpected CSV output:\n%s", buffer.String())
+	}
+}
@@ -31,7 +31,7 @@ func TestIntegration_CreateAndListCategories(t *testing.T) {
 
 	// Act & Assert - Criar categorias
 	for _, tc := range testCategories {
-		err := createUseCase.Execute(tc.name)
+		_, err := createUseCase.Execute(asRole("admin"), tc.name)
 		
 		if tc.expectError {
 			if err == nil {
@@ -47,7 +47,7 @@ func TestIntegration_CreateAndListCategories(t *testing.T) {
 	}
 
 	// Act - Listar todas as categorias
-	categories, err := listUseCase.Execute()
+	categories, err := listUseCase.Execute(asRole("admin"))
 
 	// Assert - Verificar lista
 	if err != nil {
@@ -101,14 +101,14 @@ func TestIntegration_CategoryOrder(t *testing.T) {
 
 	// Act - Criar categorias em ordem
 	for _, name := range categoryNames {
-		err := createUseCase.Execute(name)
+		_, err := createUseCase.Execute(asRole("admin"), name)
 		if err != nil {
 			t.Fatalf("Error creating category %s: %v", name, err)
 		}
 	}
 
 	// Act - Listar categorias
-	categories, err := listUseCase.Execute()
+	categories, err := listUseCase.Execute(asRole("admin"))
 	if err != nil {
 		t.Fatalf("Error listing categories: %v", err)
 	}
@@ -133,7 +133,7 @@ func TestIntegration_EmptyRepository(t *testing.T) {
 	listUseCase := NewListCategoriesUseCase(repo)
 
 	// Act
-	categories, err := listUseCase.Execute()
+	categories, err := listUseCase.Execute(asRole("admin"))
 
 	// Assert
 	if err != nil {
@@ -158,10 +158,10 @@ func TestIntegration_MultipleCreatesAndList(t *testing.T) {
 
 	// Act - Criar múltiplas categorias
 	for i := 1; i <= 5; i++ {
-		err := createUseCase.Execute("Category " + string(rune('0'+i)))
+		_, err := createUseCase.Execute(asRole("admin"), "Category " + string(rune('0'+i)))
 		if err != nil {
 			// Nome muito curto, vamos usar nome válido
-			err = createUseCase.Execute("Valid Category " + string(rune('0'+i)))
+			_, err = createUseCase.Execute(asRole("admin"), "Valid Category " + string(rune('0'+i)))
 			if err != nil {
 				t.Fatalf("Error creating category %d: %v", i, err)
 			}
@@ -170,7 +170,7 @@ func TestIntegration_MultipleCreatesAndList(t *testing.T) {
 
 	// Act - Listar múltiplas vezes para verificar consistência
 	for i := 0; i < 3; i++ {
-		categories, err := listUseCase.Execute()
+		categories, err := listUseCase.Execute(asRole("admin"))
 		if err != nil {
 			t.Fatalf("Error listing categories (iteration %d): %v", i+1, err)
 		}
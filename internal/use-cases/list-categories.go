@@ -1,8 +1,11 @@
 package use_cases
 
 import (
+	"context"
+	"gin-quickstart/internal/authz"
 	"gin-quickstart/internal/entities"
 	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
 )
 
 type listCategoryUseCase struct {
@@ -15,8 +18,14 @@ func NewListCategoriesUseCase(repository repositories.ICategoryRepository) *list
 	}
 }
 
-func (useCase *listCategoryUseCase) Execute() ([]*entities.Category, error) {
-	categories, err := useCase.repository.List()
+func (useCase *listCategoryUseCase) Execute(ctx context.Context) ([]*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	categories, err := repository.List()
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,31 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type listCategoryPageUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewListCategoryPageUseCase(repository repositories.ICategoryRepository) *listCategoryPageUseCase {
+	return &listCategoryPageUseCase{
+		repository,
+	}
+}
+
+// Execute devolve até limit categorias com ID maior que afterID e o total
+// de categorias do tenant.
+func (useCase *listCategoryPageUseCase) Execute(ctx context.Context, afterID uint, limit int) ([]*entities.Category, int, error) {
+	if err := authz.Require(ctx, authz.CategoriesRead); err != nil {
+		return nil, 0, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	return repository.ListPage(afterID, limit)
+}
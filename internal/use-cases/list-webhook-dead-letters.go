@@ -0,0 +1,28 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+type listWebhookDeadLettersUseCase struct {
+	store webhooks.IStore
+}
+
+func NewListWebhookDeadLettersUseCase(store webhooks.IStore) *listWebhookDeadLettersUseCase {
+	return &listWebhookDeadLettersUseCase{
+		store,
+	}
+}
+
+// Execute devolve as entregas das assinaturas do tenant que esgotaram as
+// tentativas.
+func (useCase *listWebhookDeadLettersUseCase) Execute(ctx context.Context) ([]*webhooks.Delivery, error) {
+	if err := authz.Require(ctx, authz.WebhooksRead); err != nil {
+		return nil, err
+	}
+
+	return useCase.store.ListDeadLetters(tenancy.From(ctx))
+}
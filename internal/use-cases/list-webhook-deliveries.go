@@ -0,0 +1,31 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/webhooks"
+)
+
+type listWebhookDeliveriesUseCase struct {
+	store webhooks.IStore
+}
+
+func NewListWebhookDeliveriesUseCase(store webhooks.IStore) *listWebhookDeliveriesUseCase {
+	return &listWebhookDeliveriesUseCase{
+		store,
+	}
+}
+
+// Execute devolve o log de entregas da assinatura, da mais antiga para a
+// mais recente.
+func (useCase *listWebhookDeliveriesUseCase) Execute(ctx context.Context, subscriptionID uint) ([]*webhooks.Delivery, error) {
+	if err := authz.Require(ctx, authz.WebhooksRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := findSubscription(ctx, useCase.store, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return useCase.store.ListDeliveries(subscriptionID)
+}
@@ -0,0 +1,32 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+type listWebhooksUseCase struct {
+	store webhooks.IStore
+}
+
+func NewListWebhooksUseCase(store webhooks.IStore) *listWebhooksUseCase {
+	return &listWebhooksUseCase{
+		store,
+	}
+}
+
+func (useCase *listWebhooksUseCase) Execute(ctx context.Context) ([]*webhooks.Subscription, error) {
+	if err := authz.Require(ctx, authz.WebhooksRead); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := useCase.store.ListSubscriptions(tenancy.From(ctx))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
@@ -17,7 +17,7 @@ func TestListCategoriesUseCase_Execute_Success(t *testing.T) {
 	useCase := NewListCategoriesUseCase(mockRepo)
 
 	// Act
-	categories, err := useCase.Execute()
+	categories, err := useCase.Execute(asRole("admin"))
 
 	// Assert
 	if err != nil {
@@ -45,7 +45,7 @@ func TestListCategoriesUseCase_Execute_EmptyList(t *testing.T) {
 	useCase := NewListCategoriesUseCase(mockRepo)
 
 	// Act
-	categories, err := useCase.Execute()
+	categories, err := useCase.Execute(asRole("admin"))
 
 	// Assert
 	if err != nil {
@@ -70,7 +70,7 @@ func TestListCategoriesUseCase_Execute_RepositoryError(t *testing.T) {
 	useCase := NewListCategoriesUseCase(mockRepo)
 
 	// Act
-	categories, err := useCase.Execute()
+	categories, err := useCase.Execute(asRole("admin"))
 
 	// Assert
 	if err == nil {
@@ -96,14 +96,14 @@ func TestListCategoriesUseCase_Integration(t *testing.T) {
 	// Adicionar algumas categorias
 	categoryNames := []string{"Electronics", "Books", "Clothing"}
 	for _, name := range categoryNames {
-		err := createUseCase.Execute(name)
+		_, err := createUseCase.Execute(asRole("admin"), name)
 		if err != nil {
 			t.Fatalf("Error creating category %s: %v", name, err)
 		}
 	}
 
 	// Act
-	categories, err := listUseCase.Execute()
+	categories, err := listUseCase.Execute(asRole("admin"))
 
 	// Assert
 	if err != nil {
@@ -139,7 +139,7 @@ func BenchmarkListCategoriesUseCase_Execute(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = useCase.Execute()
+		_, _ = useCase.Execute(asRole("admin"))
 	}
 }
 
@@ -159,7 +159,7 @@ func BenchmarkListCategoriesUseCase_LargeList(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = useCase.Execute()
+		_, _ = useCase.Execute(asRole("admin"))
 	}
 }
 
@@ -177,7 +177,7 @@ func TestListCategoriesUseCase_ConcurrentAccess(t *testing.T) {
 
 	for i := 0; i < 10; i++ {
 		go func() {
-			categories, err := useCase.Execute()
+			categories, err := useCase.Execute(asRole("admin"))
 			if err != nil {
 				errors <- err
 				return
@@ -212,18 +212,21 @@ func createMockCategories() []*entities.Category {
 			Name:      "Category 1",
 			CreatedAt: now,
 			UpdatedAt: now,
+			Version:   1,
 		},
 		{
 			ID:        2,
 			Name:      "Category 2",
 			CreatedAt: now,
 			UpdatedAt: now,
+			Version:   1,
 		},
 		{
 			ID:        3,
 			Name:      "Category 3",
 			CreatedAt: now,
 			UpdatedAt: now,
+			Version:   1,
 		},
 	}
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type moveCategoryUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewMoveCategoryUseCase(repository repositories.ICategoryRepository) *moveCategoryUseCase {
+	return &moveCategoryUseCase{
+		repository,
+	}
+}
+
+// Execute coloca a categoria abaixo de parentID (nil a leva para a raiz) se
+// ela ainda estiver em expectedVersion; com 0 vale a versão lida agora. O
+// pai tem que ser uma categoria ativa do tenant e não pode estar abaixo da
+// própria categoria.
+func (useCase *moveCategoryUseCase) Execute(ctx context.Context, id uint, parentID *uint, expectedVersion uint) (*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesUpdate); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	category, err := repository.FindByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedVersion != 0 && category.Version != expectedVersion {
+		return nil, &repositories.VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+	}
+
+	before := *category
+
+	err = category.MoveTo(parentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Só a transação não basta: no Postgres (READ COMMITTED, sem lock nas
+	// linhas lidas) mover A para baixo de B e B para baixo de A ao mesmo
+	// tempo passaria nas duas conferências. O LockHierarchy serializa as
+	// mudanças de pai do tenant, e a conferência enxerga a anterior.
+	err = repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		if err := repository.LockHierarchy(); err != nil {
+			return err
+		}
+		if err := checkParent(repository, id, parentID); err != nil {
+			return err
+		}
+		if err := repository.Update(category); err != nil {
+			return err
+		}
+		if err := repository.AddEvents(entities.NewCategoryMoved(before.ParentID, category)); err != nil {
+			return err
+		}
+		return repository.AddAudit(audit.NewEntry(ctx, audit.ActionUpdate, &before, category))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// checkParent sobe de parentID até a raiz procurando id. Um ancestral
+// removido encerra a subida: abaixo dele a categoria não aparece em lugar
+// nenhum.
+func checkParent(repository repositories.ICategoryRepository, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	ancestor, err := repository.FindByID(*parentID)
+
+	if errors.Is(err, repositories.ErrCategoryNotFound) {
+		return &entities.ValidationError{Field: "parent_id", Message: fmt.Sprintf("parent category %d not found", *parentID)}
+	}
+
+	visited := map[uint]bool{}
+	for err == nil && !visited[ancestor.ID] {
+		if ancestor.ID == id {
+			return &entities.ValidationError{Field: "parent_id", Message: fmt.Sprintf("category %d is below category %d", *parentID, id)}
+		}
+		if ancestor.ParentID == nil {
+			return nil
+		}
+
+		visited[ancestor.ID] = true
+		ancestor, err = repository.FindByID(*ancestor.ParentID)
+	}
+
+	if errors.Is(err, repositories.ErrCategoryNotFound) {
+		return nil
+	}
+	return err
+}
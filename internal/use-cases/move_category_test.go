@@ -0,0 +1,137 @@
+package use_cases
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/events/eventstest"
+	"gin-quickstart/internal/repositories"
+)
+
+func TestMoveCategoryUseCase_BuildsTheTree(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	ctx := asTenant("acme")
+	create := NewCreateCategoryUseCase(repo)
+	electronics, _ := create.Execute(ctx, "Electronics")
+	phones, _ := create.Execute(ctx, "Phones")
+	cases, _ := create.Execute(ctx, "Phone cases")
+	useCase := NewMoveCategoryUseCase(repo)
+
+	// Act
+	movedPhones, phonesErr := useCase.Execute(ctx, phones.ID, &electronics.ID, phones.Version)
+	_, casesErr := useCase.Execute(ctx, cases.ID, &phones.ID, 0)
+	children, _ := NewGetChildrenUseCase(repo).Execute(ctx, []uint{electronics.ID, phones.ID, cases.ID})
+	otherTenant, _ := NewGetChildrenUseCase(repo).Execute(asTenant("globex"), []uint{electronics.ID})
+
+	movedToRoot, rootErr := useCase.Execute(ctx, cases.ID, nil, 0)
+
+	// Assert
+	if phonesErr != nil || casesErr != nil {
+		t.Fatalf("Expected no errors moving, got %v and %v", phonesErr, casesErr)
+	}
+
+	if *movedPhones.ParentID != electronics.ID || movedPhones.Version != 2 {
+		t.Errorf("Expected Phones under Electronics at version 2, got %+v", movedPhones)
+	}
+
+	if len(children) != 2 || children[electronics.ID][0].ID != phones.ID || children[phones.ID][0].ID != cases.ID {
+		t.Errorf("Expected one child for Electronics and one for Phones, got %v", children)
+	}
+
+	if len(otherTenant) != 0 {
+		t.Errorf("Expected no children from another tenant, got %v", otherTenant)
+	}
+
+	if rootErr != nil || movedToRoot.ParentID != nil {
+		t.Errorf("Expected Phone cases back at the root, got %+v, %v", movedToRoot, rootErr)
+	}
+
+	moved, _ := eventstest.Last[entities.CategoryMoved](relayTo(t, repo))
+	if moved.ID != cases.ID || moved.OldParentID == nil || *moved.OldParentID != phones.ID || moved.NewParentID != nil {
+		t.Errorf("Unexpected CategoryMoved %+v", moved)
+	}
+}
+
+func TestMoveCategoryUseCase_RejectsMissingParentsAndCycles(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	ctx := asTenant("acme")
+	create := NewCreateCategoryUseCase(repo)
+	electronics, _ := create.Execute(ctx, "Electronics")
+	phones, _ := create.Execute(ctx, "Phones")
+	otherTenant, _ := create.Execute(asTenant("globex"), "Furniture")
+	useCase := NewMoveCategoryUseCase(repo)
+	useCase.Execute(ctx, phones.ID, &electronics.ID, 0)
+
+	// Act
+	_, selfErr := useCase.Execute(ctx, electronics.ID, &electronics.ID, 0)
+	_, cycleErr := useCase.Execute(ctx, electronics.ID, &phones.ID, 0)
+	_, missingErr := useCase.Execute(ctx, electronics.ID, &otherTenant.ID, 0)
+	unchanged, _ := repo.ForTenant("acme").FindByID(electronics.ID)
+
+	// Assert
+	for name, err := range map[string]error{"self": selfErr, "cycle": cycleErr, "other tenant": missingErr} {
+		var validationError *entities.ValidationError
+		if !errors.As(err, &validationError) || validationError.Field != "parent_id" {
+			t.Errorf("Expected a parent_id validation error for %s, got %v", name, err)
+		}
+	}
+
+	if unchanged.ParentID != nil || unchanged.Version != 1 {
+		t.Errorf("Expected Electronics untouched at the root, got %+v", unchanged)
+	}
+}
+
+// hierarchyRecorder registra as chamadas feitas dentro da transação.
+type hierarchyRecorder struct {
+	repositories.ICategoryRepository
+	calls *[]string
+}
+
+func (recorder hierarchyRecorder) ForTenant(tenant string) repositories.ICategoryRepository {
+	return hierarchyRecorder{recorder.ICategoryRepository.ForTenant(tenant), recorder.calls}
+}
+
+func (recorder hierarchyRecorder) Transaction(fn func(repository repositories.ICategoryRepository) error) error {
+	return recorder.ICategoryRepository.Transaction(func(transaction repositories.ICategoryRepository) error {
+		*recorder.calls = append(*recorder.calls, "begin")
+		return fn(hierarchyRecorder{transaction, recorder.calls})
+	})
+}
+
+func (recorder hierarchyRecorder) LockHierarchy() error {
+	*recorder.calls = append(*recorder.calls, "lock")
+	return recorder.ICategoryRepository.LockHierarchy()
+}
+
+func (recorder hierarchyRecorder) FindByID(id uint) (*entities.Category, error) {
+	*recorder.calls = append(*recorder.calls, "find")
+	return recorder.ICategoryRepository.FindByID(id)
+}
+
+func TestMoveCategoryUseCase_LocksTheHierarchyBeforeCheckingTheParent(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	ctx := asTenant("acme")
+	create := NewCreateCategoryUseCase(repo)
+	electronics, _ := create.Execute(ctx, "Electronics")
+	phones, _ := create.Execute(ctx, "Phones")
+	var calls []string
+	useCase := NewMoveCategoryUseCase(hierarchyRecorder{repo, &calls})
+
+	// Act
+	_, err := useCase.Execute(ctx, phones.ID, &electronics.ID, 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A primeira leitura é a da categoria, fora da transação
+	if want := []string{"find", "begin", "lock", "find"}; !slices.Equal(calls, want) {
+		t.Errorf("Expected the lock before reading the ancestors, got %v", calls)
+	}
+}
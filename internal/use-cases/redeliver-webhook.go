@@ -0,0 +1,54 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+	"time"
+)
+
+type redeliverWebhookUseCase struct {
+	store webhooks.IStore
+}
+
+func NewRedeliverWebhookUseCase(store webhooks.IStore) *redeliverWebhookUseCase {
+	return &redeliverWebhookUseCase{
+		store,
+	}
+}
+
+// Execute tira a entrega da dead-letter com as tentativas zeradas, para ser
+// enviada na próxima rodada do dispatcher. Retorna ErrDeliveryNotDead se
+// ela não estiver na dead-letter.
+func (useCase *redeliverWebhookUseCase) Execute(ctx context.Context, id uint) (*webhooks.Delivery, error) {
+	if err := authz.Require(ctx, authz.WebhooksManage); err != nil {
+		return nil, err
+	}
+
+	delivery, err := useCase.store.FindDelivery(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tenancy.Of(delivery) != tenancy.From(ctx) {
+		return nil, webhooks.ErrDeliveryNotFound
+	}
+
+	if delivery.Status != webhooks.DeliveryDead {
+		return nil, webhooks.ErrDeliveryNotDead
+	}
+
+	now := time.Now()
+	delivery.Status = webhooks.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	if err := useCase.store.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
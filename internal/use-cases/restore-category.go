@@ -0,0 +1,50 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type restoreCategoryUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewRestoreCategoryUseCase(repository repositories.ICategoryRepository) *restoreCategoryUseCase {
+	return &restoreCategoryUseCase{
+		repository,
+	}
+}
+
+// Execute desfaz a remoção da categoria. Retorna ErrCategoryNotFound se
+// ela não existir ou não estiver removida.
+func (useCase *restoreCategoryUseCase) Execute(ctx context.Context, id uint) (*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesRestore); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	var category *entities.Category
+
+	err := repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		restored, err := repository.Restore(id)
+		if err != nil {
+			return err
+		}
+		category = restored
+		if err := repository.AddEvents(entities.NewCategoryRestored(restored)); err != nil {
+			return err
+		}
+		return repository.AddAudit(audit.NewEntry(ctx, audit.ActionRestore, nil, restored))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
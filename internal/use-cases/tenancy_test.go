@@ -0,0 +1,206 @@
+package use_cases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/categoryio"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+// asTenant devolve um context de admin no tenant informado.
+func asTenant(tenant string) context.Context {
+	return tenancy.WithTenant(asRole("admin"), tenant)
+}
+
+// tenancyFixture tem, no tenant acme, uma categoria ativa (1), uma removida
+// (2) e uma assinatura de webhook (1) com uma entrega na dead-letter (1).
+type tenancyFixture struct {
+	repo         repositories.ICategoryRepository
+	auditLog     audit.IStore
+	webhookStore webhooks.IStore
+	active       uint
+	deleted      uint
+	subscription uint
+	delivery     uint
+}
+
+func newTenancyFixture(t *testing.T) *tenancyFixture {
+	t.Helper()
+	acme := asTenant("acme")
+	repo := repositories.NewInMemotyCategoryRepository()
+	fixture := &tenancyFixture{
+		repo:         repo,
+		auditLog:     repo,
+		webhookStore: webhooks.NewInMemoryStore(),
+	}
+
+	active, err := NewCreateCategoryUseCase(fixture.repo).Execute(acme, "Electronics")
+	if err != nil {
+		t.Fatalf("Error seeding category: %v", err)
+	}
+	fixture.active = active.ID
+
+	deleted, _ := NewCreateCategoryUseCase(fixture.repo).Execute(acme, "Home & Garden")
+	NewDeleteCategoryUseCase(fixture.repo).Execute(acme, deleted.ID, 0)
+	fixture.deleted = deleted.ID
+
+	subscription, _ := NewCreateWebhookUseCase(fixture.webhookStore, webhooks.Targets{}).Execute(acme, "https://acme.example.com/hooks", []string{webhooks.AllEvents}, "")
+	fixture.subscription = subscription.ID
+
+	delivery := &webhooks.Delivery{TenantID: "acme", SubscriptionID: subscription.ID, Status: webhooks.DeliveryDead, NextAttemptAt: time.Now()}
+	fixture.webhookStore.SaveDeliveries(delivery)
+	fixture.delivery = delivery.ID
+
+	return fixture
+}
+
+func TestUseCases_TenantCannotReachAnotherTenant(t *testing.T) {
+	testCases := []struct {
+		useCase string
+		want    error
+		run     func(ctx context.Context, f *tenancyFixture) error
+	}{
+		{"get category", repositories.ErrCategoryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewGetCategoryUseCase(f.repo).Execute(ctx, f.active)
+			return err
+		}},
+		{"update category", repositories.ErrCategoryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewUpdateCategoryUseCase(f.repo).Execute(ctx, f.active, "Gadgets & Gizmos", 0)
+			return err
+		}},
+		{"delete category", repositories.ErrCategoryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			return NewDeleteCategoryUseCase(f.repo).Execute(ctx, f.active, 0)
+		}},
+		{"restore category", repositories.ErrCategoryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewRestoreCategoryUseCase(f.repo).Execute(ctx, f.deleted)
+			return err
+		}},
+		{"category history", repositories.ErrCategoryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewCategoryHistoryUseCase(f.auditLog).Execute(ctx, f.active)
+			return err
+		}},
+		{"batch update", ErrBatchAborted, func(ctx context.Context, f *tenancyFixture) error {
+			results, err := NewBatchCategoriesUseCase(f.repo).Execute(ctx, []BatchOperation{{Type: BatchUpdate, ID: f.active, Name: "Gadgets & Gizmos"}}, true)
+			if len(results) == 1 && !errors.Is(results[0].Err, repositories.ErrCategoryNotFound) {
+				t.Errorf("Expected ErrCategoryNotFound for the item, got %v", results[0].Err)
+			}
+			return err
+		}},
+		{"get webhook", webhooks.ErrSubscriptionNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewGetWebhookUseCase(f.webhookStore).Execute(ctx, f.subscription)
+			return err
+		}},
+		{"delete webhook", webhooks.ErrSubscriptionNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			return NewDeleteWebhookUseCase(f.webhookStore).Execute(ctx, f.subscription)
+		}},
+		{"list webhook deliveries", webhooks.ErrSubscriptionNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewListWebhookDeliveriesUseCase(f.webhookStore).Execute(ctx, f.subscription)
+			return err
+		}},
+		{"redeliver webhook", webhooks.ErrDeliveryNotFound, func(ctx context.Context, f *tenancyFixture) error {
+			_, err := NewRedeliverWebhookUseCase(f.webhookStore).Execute(ctx, f.delivery)
+			return err
+		}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.useCase, func(t *testing.T) {
+			// Arrange
+			fixture := newTenancyFixture(t)
+
+			// Act
+			err := testCase.run(asTenant("globex"), fixture)
+
+			// Assert
+			if !errors.Is(err, testCase.want) {
+				t.Fatalf("Expected %v, got %v", testCase.want, err)
+			}
+
+			// O tenant dono continua vendo tudo como estava
+			acme := asTenant("acme")
+			category, err := NewGetCategoryUseCase(fixture.repo).Execute(acme, fixture.active)
+			if err != nil || category.Name != "Electronics" || category.Version != 1 {
+				t.Errorf("Expected acme's category untouched, got %+v (%v)", category, err)
+			}
+			if _, err := NewGetWebhookUseCase(fixture.webhookStore).Execute(acme, fixture.subscription); err != nil {
+				t.Errorf("Expected acme's webhook untouched, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUseCases_TenantListsOnlyItsOwnData(t *testing.T) {
+	// Arrange
+	fixture := newTenancyFixture(t)
+	globex := asTenant("globex")
+	NewCreateCategoryUseCase(fixture.repo).Execute(globex, "Furniture")
+
+	// Act
+	categories, err := NewListCategoriesUseCase(fixture.repo).Execute(globex)
+
+	// Assert
+	if err != nil || len(categories) != 1 || categories[0].Name != "Furniture" || categories[0].TenantID != "globex" {
+		t.Fatalf("Expected only globex's category, got %+v (%v)", categories, err)
+	}
+
+	var exported bytes.Buffer
+	writer, _ := categoryio.NewWriter(categoryio.JSONL, &exported)
+	if count, _ := NewExportCategoriesUseCase(fixture.repo).Execute(globex, writer); count != 1 || strings.Contains(exported.String(), "Electronics") {
+		t.Errorf("Expected the export to only have globex's category, got %d: %s", count, exported.String())
+	}
+
+	if subscriptions, _ := NewListWebhooksUseCase(fixture.webhookStore).Execute(globex); len(subscriptions) != 0 {
+		t.Errorf("Expected no webhooks for globex, got %d", len(subscriptions))
+	}
+
+	if deadLetters, _ := NewListWebhookDeadLettersUseCase(fixture.webhookStore).Execute(globex); len(deadLetters) != 0 {
+		t.Errorf("Expected no dead letters for globex, got %d", len(deadLetters))
+	}
+
+	// Sem tenant no context vale o default, que também não vê o acme
+	if categories, _ := NewListCategoriesUseCase(fixture.repo).Execute(asRole("admin")); len(categories) != 0 {
+		t.Errorf("Expected no categories in the default tenant, got %d", len(categories))
+	}
+}
+
+func TestUseCases_CategoryNamesAreUniquePerTenant(t *testing.T) {
+	// Arrange
+	fixture := newTenancyFixture(t)
+	acme, globex := asTenant("acme"), asTenant("globex")
+	create := NewCreateCategoryUseCase(fixture.repo)
+
+	// Act
+	_, duplicateErr := create.Execute(acme, "Electronics")
+	_, otherTenantErr := create.Execute(globex, "Electronics")
+
+	// Assert
+	if !errors.Is(duplicateErr, repositories.ErrDuplicateName) {
+		t.Errorf("Expected ErrDuplicateName in the same tenant, got %v", duplicateErr)
+	}
+
+	if otherTenantErr != nil {
+		t.Errorf("Expected the name to be free in another tenant, got %v", otherTenantErr)
+	}
+
+	// O nome de uma categoria removida pode ser reusado, mas aí ela não volta
+	if _, err := create.Execute(acme, "Home & Garden"); err != nil {
+		t.Fatalf("Expected the removed category's name to be free, got %v", err)
+	}
+
+	if _, err := NewRestoreCategoryUseCase(fixture.repo).Execute(acme, fixture.deleted); !errors.Is(err, repositories.ErrDuplicateName) {
+		t.Errorf("Expected ErrDuplicateName restoring over a taken name, got %v", err)
+	}
+
+	renamed, _ := create.Execute(acme, "Furniture")
+	if _, err := NewUpdateCategoryUseCase(fixture.repo).Execute(acme, renamed.ID, "Electronics", 0); !errors.Is(err, repositories.ErrDuplicateName) {
+		t.Errorf("Expected ErrDuplicateName renaming to a taken name, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package use_cases
+
+import (
+	"context"
+	"gin-quickstart/internal/audit"
+	"gin-quickstart/internal/authz"
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/internal/tenancy"
+)
+
+type updateCategoryUseCase struct {
+	repository repositories.ICategoryRepository
+}
+
+func NewUpdateCategoryUseCase(repository repositories.ICategoryRepository) *updateCategoryUseCase {
+	return &updateCategoryUseCase{
+		repository,
+	}
+}
+
+// Execute renomeia a categoria se ela ainda estiver em expectedVersion.
+// Com expectedVersion 0 a versão lida agora é usada como esperada.
+func (useCase *updateCategoryUseCase) Execute(ctx context.Context, id uint, name string, expectedVersion uint) (*entities.Category, error) {
+	if err := authz.Require(ctx, authz.CategoriesUpdate); err != nil {
+		return nil, err
+	}
+
+	repository := useCase.repository.ForTenant(tenancy.From(ctx))
+
+	category, err := repository.FindByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedVersion != 0 && category.Version != expectedVersion {
+		return nil, &repositories.VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+	}
+
+	before := *category
+
+	err = category.Rename(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = repository.Transaction(func(repository repositories.ICategoryRepository) error {
+		if err := repository.Update(category); err != nil {
+			return err
+		}
+		if err := repository.AddEvents(entities.NewCategoryRenamed(before.Name, category)); err != nil {
+			return err
+		}
+		return repository.AddAudit(audit.NewEntry(ctx, audit.ActionUpdate, &before, category))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
@@ -0,0 +1,136 @@
+package use_cases
+
+import (
+	"errors"
+	"testing"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/repositories"
+)
+
+func TestUpdateCategoryUseCase_Execute_Success(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	previousUpdatedAt := mockRepo.savedCategories[0].UpdatedAt
+	useCase := NewUpdateCategoryUseCase(mockRepo)
+
+	// Act
+	category, err := useCase.Execute(asRole("admin"), 1, "Renamed Category", 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if category.Name != "Renamed Category" {
+		t.Errorf("Expected category name Renamed Category, got %s", category.Name)
+	}
+
+	if !category.UpdatedAt.After(previousUpdatedAt) {
+		t.Error("Expected UpdatedAt to move forward")
+	}
+
+	if mockRepo.savedCategories[0].Name != "Renamed Category" {
+		t.Errorf("Expected repository to be updated, got %s", mockRepo.savedCategories[0].Name)
+	}
+}
+
+func TestUpdateCategoryUseCase_Execute_InvalidName(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewUpdateCategoryUseCase(mockRepo)
+
+	// Act
+	category, err := useCase.Execute(asRole("admin"), 1, "abc", 1)
+
+	// Assert
+	var validationError *entities.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	if category != nil {
+		t.Error("Expected nil category on error, got non-nil")
+	}
+
+	if mockRepo.savedCategories[0].Name != "Category 1" {
+		t.Errorf("Expected name to be unchanged, got %s", mockRepo.savedCategories[0].Name)
+	}
+}
+
+func TestUpdateCategoryUseCase_Execute_NotFound(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{}
+	useCase := NewUpdateCategoryUseCase(mockRepo)
+
+	// Act
+	_, err := useCase.Execute(asRole("admin"), 42, "Renamed Category", 0)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestUpdateCategoryUseCase_Execute_StaleVersion(t *testing.T) {
+	// Arrange
+	mockRepo := &mockCategoryRepository{
+		savedCategories: createMockCategories(),
+	}
+	useCase := NewUpdateCategoryUseCase(mockRepo)
+
+	// Act
+	_, err := useCase.Execute(asRole("admin"), 1, "Renamed Category", 7)
+
+	// Assert
+	if !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+
+	if mockRepo.savedCategories[0].Name != "Category 1" {
+		t.Errorf("Expected name to be unchanged, got %s", mockRepo.savedCategories[0].Name)
+	}
+}
+
+// Dois admins leem a mesma versão e salvam em sequência: o segundo não pode
+// sobrescrever o primeiro.
+func TestUpdateCategoryUseCase_Integration_ConcurrentEdits(t *testing.T) {
+	// Arrange
+	repo := repositories.NewInMemotyCategoryRepository()
+	created, err := NewCreateCategoryUseCase(repo).Execute(asRole("admin"), "Electronics")
+	if err != nil {
+		t.Fatalf("Error creating category: %v", err)
+	}
+	useCase := NewUpdateCategoryUseCase(repo)
+
+	// Act
+	first, firstErr := useCase.Execute(asRole("admin"), created.ID, "First Admin Name", created.Version)
+	_, secondErr := useCase.Execute(asRole("admin"), created.ID, "Second Admin Name", created.Version)
+
+	// Assert
+	if firstErr != nil {
+		t.Fatalf("Expected first update to succeed, got %v", firstErr)
+	}
+
+	if first.Version != created.Version+1 {
+		t.Errorf("Expected version %d, got %d", created.Version+1, first.Version)
+	}
+
+	var conflict *repositories.VersionConflictError
+	if !errors.As(secondErr, &conflict) {
+		t.Fatalf("Expected VersionConflictError, got %v", secondErr)
+	}
+
+	stored, err := repo.FindByID(created.ID)
+	if err != nil {
+		t.Fatalf("Error finding category: %v", err)
+	}
+
+	if stored.Name != "First Admin Name" {
+		t.Errorf("Expected first admin's name to be kept, got %s", stored.Name)
+	}
+}
@@ -0,0 +1,98 @@
+package use_cases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/entities"
+	"gin-quickstart/internal/tenancy"
+	"gin-quickstart/internal/webhooks"
+)
+
+func TestCreateWebhookUseCase_GeneratesSecretAndNormalizesEvents(t *testing.T) {
+	// Arrange
+	useCase := NewCreateWebhookUseCase(webhooks.NewInMemoryStore(), webhooks.Targets{})
+
+	// Act
+	subscription, err := useCase.Execute(asRole("admin"), "https://example.com/hooks", []string{"category.deleted", "category.created", "category.deleted"}, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(subscription.Secret, "whsec_") {
+		t.Errorf("Expected a generated secret, got %q", subscription.Secret)
+	}
+
+	if strings.Join(subscription.Events, ",") != "category.created,category.deleted" {
+		t.Errorf("Expected sorted unique events, got %v", subscription.Events)
+	}
+}
+
+func TestCreateWebhookUseCase_InvalidInput(t *testing.T) {
+	testCases := []struct {
+		name   string
+		url    string
+		events []string
+		field  string
+	}{
+		{"relative url", "/hooks", []string{webhooks.AllEvents}, "url"},
+		{"unsupported scheme", "ftp://example.com", []string{webhooks.AllEvents}, "url"},
+		{"loopback", "http://127.0.0.1:8080/hooks", []string{webhooks.AllEvents}, "url"},
+		{"localhost", "http://localhost:9000/hooks", []string{webhooks.AllEvents}, "url"},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data", []string{webhooks.AllEvents}, "url"},
+		{"private network", "https://10.0.0.5/hooks", []string{webhooks.AllEvents}, "url"},
+		{"unspecified", "http://[::]/hooks", []string{webhooks.AllEvents}, "url"},
+		{"mapped private address", "http://[::ffff:192.168.0.1]/hooks", []string{webhooks.AllEvents}, "url"},
+		{"no events", "https://example.com", nil, "events"},
+		{"unknown event", "https://example.com", []string{"category.exploded"}, "events"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			_, err := NewCreateWebhookUseCase(webhooks.NewInMemoryStore(), webhooks.Targets{}).Execute(asRole("admin"), testCase.url, testCase.events, "")
+
+			// Assert
+			var validationError *entities.ValidationError
+			if !errors.As(err, &validationError) || validationError.Field != testCase.field {
+				t.Fatalf("Expected validation error on %s, got %v", testCase.field, err)
+			}
+		})
+	}
+}
+
+func TestRedeliverWebhookUseCase(t *testing.T) {
+	// Arrange
+	store := webhooks.NewInMemoryStore()
+	store.SaveDeliveries(
+		&webhooks.Delivery{SubscriptionID: 1, Status: webhooks.DeliveryDead, Attempts: webhooks.MaxAttempts, NextAttemptAt: time.Now()},
+		&webhooks.Delivery{SubscriptionID: 1, Status: webhooks.DeliverySucceeded, Attempts: 1, NextAttemptAt: time.Now()},
+	)
+	useCase := NewRedeliverWebhookUseCase(store)
+
+	// Act
+	redelivered, err := useCase.Execute(asRole("admin"), 1)
+	_, notDeadErr := useCase.Execute(asRole("admin"), 2)
+	_, unknownErr := useCase.Execute(asRole("admin"), 3)
+
+	// Assert
+	if err != nil || redelivered.Status != webhooks.DeliveryPending || redelivered.Attempts != 0 {
+		t.Fatalf("Expected the dead delivery back to pending with no attempts, got %+v (%v)", redelivered, err)
+	}
+
+	if dead, _ := store.ListDeadLetters(tenancy.Default); len(dead) != 0 {
+		t.Errorf("Expected an empty dead-letter list, got %d", len(dead))
+	}
+
+	if !errors.Is(notDeadErr, webhooks.ErrDeliveryNotDead) {
+		t.Errorf("Expected ErrDeliveryNotDead, got %v", notDeadErr)
+	}
+
+	if !errors.Is(unknownErr, webhooks.ErrDeliveryNotFound) {
+		t.Errorf("Expected ErrDeliveryNotFound, got %v", unknownErr)
+	}
+}
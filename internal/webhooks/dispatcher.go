@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/events"
+	"gin-quickstart/internal/outbox"
+	"gin-quickstart/internal/tenancy"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBatchSize = 50
+	// DefaultConcurrency é quantas entregas de um lote são enviadas ao mesmo
+	// tempo
+	DefaultConcurrency = 8
+	// MaxAttempts é quantas tentativas uma entrega tem antes de ir para a
+	// dead-letter.
+	MaxAttempts = 8
+	// DefaultTimeout limita cada POST ao receptor
+	DefaultTimeout = 10 * time.Second
+	// lease é quanto tempo uma entrega fica reservada para quem a pegou
+	lease = time.Minute
+)
+
+// payload é o corpo enviado ao receptor.
+type payload struct {
+	Event string       `json:"event"`
+	Data  events.Event `json:"data"`
+}
+
+// Dispatcher recebe os eventos do bus, grava uma entrega para cada
+// assinatura interessada e as envia (DeliverDue) com backoff exponencial
+// entre as tentativas. Entregas que esgotam MaxAttempts vão para a
+// dead-letter.
+type Dispatcher struct {
+	store       IStore
+	client      *http.Client
+	batchSize   int
+	concurrency int
+	now         func() time.Time
+}
+
+// NewDispatcher usa o Targets{}.Client(), que só envia a endereços
+// públicos, quando client é nil.
+func NewDispatcher(store IStore, client *http.Client) *Dispatcher {
+	if client == nil {
+		client = Targets{}.Client()
+	}
+
+	return &Dispatcher{
+		store:       store,
+		client:      client,
+		batchSize:   DefaultBatchSize,
+		concurrency: DefaultConcurrency,
+		now:         time.Now,
+	}
+}
+
+// Enqueue é o events.Handler assinado no bus. Só grava as entregas, para as
+// assinaturas do tenant do evento; o envio fica para DeliverDue, então um
+// receptor lento não segura o bus.
+func (dispatcher *Dispatcher) Enqueue(ctx context.Context, event events.Event) error {
+	// Um tenant nunca recebe eventos de outro
+	subscriptions, err := dispatcher.store.ListSubscriptions(tenancy.Of(event))
+
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload{Event: event.EventName(), Data: event})
+
+	if err != nil {
+		return err
+	}
+
+	now := dispatcher.now()
+	deliveries := make([]*Delivery, 0)
+
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.EventName()) {
+			continue
+		}
+
+		deliveries = append(deliveries, &Delivery{
+			TenantID:       tenancy.Of(subscription),
+			SubscriptionID: subscription.ID,
+			EventName:      event.EventName(),
+			Payload:        body,
+			Status:         DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return dispatcher.store.SaveDeliveries(deliveries...)
+}
+
+// Run chama DeliverDue a cada interval até ctx ser cancelado.
+func (dispatcher *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dispatcher.DeliverDue(ctx); err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue envia um lote de entregas vencidas, até concurrency ao mesmo
+// tempo, e retorna quantas foram aceitas pelo receptor (2xx). Falhas do
+// receptor reagendam a entrega; só erros do store são devolvidos.
+func (dispatcher *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := dispatcher.store.ClaimDueDeliveries(dispatcher.batchSize, dispatcher.now(), lease)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		delivered int
+		errs      []error
+	)
+
+	queue := make(chan *Delivery)
+
+	for range min(dispatcher.concurrency, len(deliveries)) {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for delivery := range queue {
+				ok, err := dispatcher.deliver(ctx, delivery)
+
+				mutex.Lock()
+				if ok {
+					delivered++
+				}
+				if err != nil {
+					errs = append(errs, err)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, delivery := range deliveries {
+		queue <- delivery
+	}
+
+	close(queue)
+	waitGroup.Wait()
+
+	return delivered, errors.Join(errs...)
+}
+
+// deliver envia uma entrega e grava o resultado; ok diz se o receptor a
+// aceitou.
+func (dispatcher *Dispatcher) deliver(ctx context.Context, delivery *Delivery) (bool, error) {
+	subscription, err := dispatcher.store.FindSubscription(delivery.SubscriptionID)
+
+	// A assinatura foi removida depois do claim (junto com a entrega)
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	statusCode, sendErr := dispatcher.send(ctx, subscription, delivery)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.UpdatedAt = dispatcher.now()
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = DeliverySucceeded
+		delivery.LastError = ""
+		deliveredAt := dispatcher.now()
+		delivery.DeliveredAt = &deliveredAt
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = DeliveryDead
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = dispatcher.now().Add(outbox.Backoff(delivery.Attempts))
+	}
+
+	if err := dispatcher.store.UpdateDelivery(delivery); err != nil {
+		return false, err
+	}
+
+	return sendErr == nil, nil
+}
+
+// send faz o POST assinado e devolve o status HTTP da resposta (0 se não
+// houve resposta).
+func (dispatcher *Dispatcher) send(ctx context.Context, subscription *Subscription, delivery *Delivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "gin-quickstart-webhooks/1.0")
+	request.Header.Set(EventHeader, delivery.EventName)
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, dispatcher.now(), delivery.Payload))
+
+	response, err := dispatcher.client.Do(request)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("receiver responded %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
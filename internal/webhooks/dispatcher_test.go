@@ -0,0 +1,239 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gin-quickstart/internal/tenancy"
+)
+
+type testEvent struct {
+	Value int `json:"value"`
+}
+
+func (testEvent) EventName() string { return "test.happened" }
+
+// tenantEvent é um testEvent de um tenant.
+type tenantEvent struct {
+	testEvent
+	tenant string
+}
+
+func (event tenantEvent) Tenant() string { return event.tenant }
+
+// receiver é um endpoint httptest que guarda as requisições recebidas e
+// responde status.
+type receiver struct {
+	mutex    sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (receiver *receiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, _ := io.ReadAll(request.Body)
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	receiver.requests = append(receiver.requests, request)
+	receiver.bodies = append(receiver.bodies, body)
+	writer.WriteHeader(receiver.status)
+}
+
+func newTestDispatcher(t *testing.T, status int, events ...string) (*Dispatcher, *inMemoryStore, *receiver) {
+	target := &receiver{status: status}
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	store := NewInMemoryStore()
+	store.SaveSubscription(&Subscription{URL: server.URL, Secret: "s3cr3t-s3cr3t-s3cr3t", Events: events})
+
+	return NewDispatcher(store, server.Client()), store, target
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	// Arrange
+	dispatcher, store, target := newTestDispatcher(t, http.StatusNoContent, AllEvents)
+
+	// Act
+	if err := dispatcher.Enqueue(context.Background(), testEvent{Value: 7}); err != nil {
+		t.Fatalf("Expected no error enqueuing, got %v", err)
+	}
+	delivered, err := dispatcher.DeliverDue(context.Background())
+
+	// Assert
+	if err != nil || delivered != 1 {
+		t.Fatalf("Expected 1 delivery, got %d (%v)", delivered, err)
+	}
+
+	request, body := target.requests[0], target.bodies[0]
+	if request.Header.Get(EventHeader) != "test.happened" || request.Header.Get(DeliveryHeader) != "1" {
+		t.Errorf("Expected event and delivery headers, got %v", request.Header)
+	}
+
+	if err := Verify("s3cr3t-s3cr3t-s3cr3t", request.Header.Get(SignatureHeader), body, time.Now(), time.Minute); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+
+	if err := Verify("another-secret", request.Header.Get(SignatureHeader), body, time.Now(), time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected the signature to depend on the secret, got %v", err)
+	}
+
+	var received struct {
+		Event string    `json:"event"`
+		Data  testEvent `json:"data"`
+	}
+	if err := json.Unmarshal(body, &received); err != nil || received.Event != "test.happened" || received.Data.Value != 7 {
+		t.Errorf("Expected the event in the payload, got %s", body)
+	}
+
+	deliveries, _ := store.ListDeliveries(1)
+	if deliveries[0].Status != DeliverySucceeded || deliveries[0].Attempts != 1 || deliveries[0].LastStatusCode != http.StatusNoContent {
+		t.Errorf("Expected a logged successful attempt, got %+v", deliveries[0])
+	}
+}
+
+func TestDispatcher_SkipsSubscriptionsForOtherEvents(t *testing.T) {
+	// Arrange
+	dispatcher, store, _ := newTestDispatcher(t, http.StatusOK, "other.happened")
+
+	// Act
+	err := dispatcher.Enqueue(context.Background(), testEvent{})
+
+	// Assert
+	deliveries, _ := store.ListDeliveries(1)
+	if err != nil || len(deliveries) != 0 {
+		t.Fatalf("Expected no deliveries, got %d (%v)", len(deliveries), err)
+	}
+}
+
+func TestDispatcher_OnlyDeliversToTheEventTenant(t *testing.T) {
+	// Arrange
+	dispatcher, store, _ := newTestDispatcher(t, http.StatusOK, AllEvents)
+	store.SaveSubscription(&Subscription{TenantID: "acme", URL: "https://acme.example.com/hooks", Events: []string{AllEvents}})
+
+	// Act
+	err := dispatcher.Enqueue(context.Background(), tenantEvent{tenant: "acme"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error enqueuing, got %v", err)
+	}
+
+	if deliveries, _ := store.ListDeliveries(1); len(deliveries) != 0 {
+		t.Errorf("Expected no deliveries to the default tenant, got %d", len(deliveries))
+	}
+
+	deliveries, _ := store.ListDeliveries(2)
+	if len(deliveries) != 1 || deliveries[0].TenantID != "acme" {
+		t.Fatalf("Expected 1 delivery for acme, got %+v", deliveries)
+	}
+}
+
+func TestDispatcher_BacksOffThenDeadLetters(t *testing.T) {
+	// Arrange
+	dispatcher, store, target := newTestDispatcher(t, http.StatusInternalServerError, AllEvents)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	dispatcher.Enqueue(context.Background(), testEvent{})
+
+	// Act
+	dispatcher.DeliverDue(context.Background())
+	first, _ := store.FindDelivery(1)
+	delivered, _ := dispatcher.DeliverDue(context.Background())
+
+	// Assert
+	if first.Status != DeliveryPending || first.LastStatusCode != http.StatusInternalServerError || !first.NextAttemptAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("Expected a retry scheduled in 1s after a 500, got %+v", first)
+	}
+
+	if delivered != 0 || len(target.requests) != 1 {
+		t.Fatalf("Expected no attempt before the backoff, got %d requests", len(target.requests))
+	}
+
+	for attempt := 2; attempt <= MaxAttempts; attempt++ {
+		now = now.Add(time.Hour)
+		dispatcher.DeliverDue(context.Background())
+	}
+
+	dead, _ := store.ListDeadLetters(tenancy.Default)
+	if len(dead) != 1 || dead[0].Attempts != MaxAttempts || dead[0].LastError == "" {
+		t.Fatalf("Expected the delivery in the dead-letter after %d attempts, got %+v", MaxAttempts, dead)
+	}
+
+	now = now.Add(time.Hour)
+	dispatcher.DeliverDue(context.Background())
+	if len(target.requests) != MaxAttempts {
+		t.Errorf("Expected no attempts after dead-lettering, got %d requests", len(target.requests))
+	}
+}
+
+func TestDispatcher_SendsDeliveriesConcurrently(t *testing.T) {
+	// Arrange
+	arrived := make(chan struct{}, 3)
+	release := make(chan struct{})
+	releaseAll := sync.OnceFunc(func() { close(release) })
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		arrived <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(releaseAll)
+
+	store := NewInMemoryStore()
+	for range 3 {
+		store.SaveSubscription(&Subscription{URL: server.URL, Secret: "s3cr3t-s3cr3t-s3cr3t", Events: []string{AllEvents}})
+	}
+
+	dispatcher := NewDispatcher(store, server.Client())
+	dispatcher.Enqueue(context.Background(), testEvent{})
+
+	// Act
+	result := make(chan int)
+	go func() {
+		delivered, _ := dispatcher.DeliverDue(context.Background())
+		result <- delivered
+	}()
+
+	// Assert
+	for range 3 {
+		select {
+		case <-arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the 3 deliveries to be in flight at the same time")
+		}
+	}
+
+	releaseAll()
+	if delivered := <-result; delivered != 3 {
+		t.Errorf("Expected 3 deliveries, got %d", delivered)
+	}
+}
+
+func TestVerify_RejectsStaleSignatures(t *testing.T) {
+	// Arrange
+	body := []byte(`{"event":"test.happened"}`)
+	signedAt := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	header := Sign("secret", signedAt, body)
+
+	// Act
+	fresh := Verify("secret", header, body, signedAt.Add(time.Minute), 5*time.Minute)
+	stale := Verify("secret", header, body, signedAt.Add(time.Hour), 5*time.Minute)
+	tampered := Verify("secret", header, []byte(`{"event":"other"}`), signedAt, 5*time.Minute)
+
+	// Assert
+	if fresh != nil {
+		t.Errorf("Expected fresh signature to verify, got %v", fresh)
+	}
+	if !errors.Is(stale, ErrInvalidSignature) || !errors.Is(tampered, ErrInvalidSignature) {
+		t.Errorf("Expected stale and tampered signatures to fail, got %v and %v", stale, tampered)
+	}
+}
@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"gin-quickstart/internal/tenancy"
+	"sort"
+	"sync"
+	"time"
+)
+
+type inMemoryStore struct {
+	mutex              sync.RWMutex
+	subscriptions      map[uint]*Subscription
+	deliveries         map[uint]*Delivery
+	nextSubscriptionID uint
+	nextDeliveryID     uint
+}
+
+func NewInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{
+		subscriptions:      make(map[uint]*Subscription),
+		deliveries:         make(map[uint]*Delivery),
+		nextSubscriptionID: 1,
+		nextDeliveryID:     1,
+	}
+}
+
+func (store *inMemoryStore) SaveSubscription(subscription *Subscription) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if subscription.ID == 0 {
+		subscription.ID = store.nextSubscriptionID
+		store.nextSubscriptionID++
+	}
+
+	stored := *subscription
+	stored.Events = append([]string(nil), subscription.Events...)
+	store.subscriptions[stored.ID] = &stored
+	return nil
+}
+
+func (store *inMemoryStore) ListSubscriptions(tenant string) ([]*Subscription, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	subscriptions := make([]*Subscription, 0)
+	for _, subscription := range store.subscriptions {
+		if tenancy.Of(subscription) != tenant {
+			continue
+		}
+
+		copied := *subscription
+		subscriptions = append(subscriptions, &copied)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+func (store *inMemoryStore) FindSubscription(id uint) (*Subscription, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	subscription, ok := store.subscriptions[id]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	copied := *subscription
+	return &copied, nil
+}
+
+func (store *inMemoryStore) DeleteSubscription(id uint) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if _, ok := store.subscriptions[id]; !ok {
+		return ErrSubscriptionNotFound
+	}
+
+	delete(store.subscriptions, id)
+	for deliveryID, delivery := range store.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(store.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+func (store *inMemoryStore) SaveDeliveries(deliveries ...*Delivery) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, delivery := range deliveries {
+		delivery.ID = store.nextDeliveryID
+		store.nextDeliveryID++
+
+		stored := *delivery
+		store.deliveries[stored.ID] = &stored
+	}
+
+	return nil
+}
+
+func (store *inMemoryStore) FindDelivery(id uint) (*Delivery, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	delivery, ok := store.deliveries[id]
+	if !ok {
+		return nil, ErrDeliveryNotFound
+	}
+
+	copied := *delivery
+	return &copied, nil
+}
+
+func (store *inMemoryStore) UpdateDelivery(delivery *Delivery) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if _, ok := store.deliveries[delivery.ID]; !ok {
+		return ErrDeliveryNotFound
+	}
+
+	stored := *delivery
+	store.deliveries[stored.ID] = &stored
+	return nil
+}
+
+func (store *inMemoryStore) ClaimDueDeliveries(limit int, now time.Time, lease time.Duration) ([]*Delivery, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	due := store.filter(func(delivery *Delivery) bool {
+		return delivery.Status == DeliveryPending && !delivery.NextAttemptAt.After(now)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, delivery := range due {
+		store.deliveries[delivery.ID].NextAttemptAt = now.Add(lease)
+	}
+
+	return due, nil
+}
+
+func (store *inMemoryStore) ListDeliveries(subscriptionID uint) ([]*Delivery, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	return store.filter(func(delivery *Delivery) bool {
+		return delivery.SubscriptionID == subscriptionID
+	}), nil
+}
+
+func (store *inMemoryStore) ListDeadLetters(tenant string) ([]*Delivery, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	return store.filter(func(delivery *Delivery) bool {
+		return tenancy.Of(delivery) == tenant && delivery.Status == DeliveryDead
+	}), nil
+}
+
+// filter devolve cópias das entregas que passam em keep, em ordem de ID.
+func (store *inMemoryStore) filter(keep func(delivery *Delivery) bool) []*Delivery {
+	deliveries := make([]*Delivery, 0)
+	for _, delivery := range store.deliveries {
+		if keep(delivery) {
+			copied := *delivery
+			deliveries = append(deliveries, &copied)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries
+}
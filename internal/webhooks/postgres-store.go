@@ -0,0 +1,152 @@
+package webhooks
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *postgresStore {
+	return &postgresStore{
+		db: db,
+	}
+}
+
+func (store *postgresStore) SaveSubscription(subscription *Subscription) error {
+	return store.db.Save(subscription).Error
+}
+
+func (store *postgresStore) ListSubscriptions(tenant string) ([]*Subscription, error) {
+	var subscriptions []*Subscription
+
+	if err := store.db.Where("tenant_id = ?", tenant).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (store *postgresStore) FindSubscription(id uint) (*Subscription, error) {
+	var subscription Subscription
+
+	err := store.db.First(&subscription, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (store *postgresStore) DeleteSubscription(id uint) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&Subscription{}, id)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrSubscriptionNotFound
+		}
+
+		return tx.Where("subscription_id = ?", id).Delete(&Delivery{}).Error
+	})
+}
+
+func (store *postgresStore) SaveDeliveries(deliveries ...*Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return store.db.Create(deliveries).Error
+}
+
+func (store *postgresStore) FindDelivery(id uint) (*Delivery, error) {
+	var delivery Delivery
+
+	err := store.db.First(&delivery, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeliveryNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (store *postgresStore) UpdateDelivery(delivery *Delivery) error {
+	result := store.db.Model(delivery).Select("*").Omit("created_at").Updates(delivery)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries usa FOR UPDATE SKIP LOCKED, como a outbox, para que
+// várias instâncias entreguem em paralelo sem repetir a mesma entrega.
+func (store *postgresStore) ClaimDueDeliveries(limit int, now time.Time, lease time.Duration) ([]*Delivery, error) {
+	var deliveries []*Delivery
+
+	err := store.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("id").
+			Limit(limit).
+			Find(&deliveries).Error
+
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+
+		return tx.Model(&Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (store *postgresStore) ListDeliveries(subscriptionID uint) ([]*Delivery, error) {
+	return store.listDeliveries("subscription_id = ?", subscriptionID)
+}
+
+func (store *postgresStore) ListDeadLetters(tenant string) ([]*Delivery, error) {
+	return store.listDeliveries("tenant_id = ? AND status = ?", tenant, DeliveryDead)
+}
+
+func (store *postgresStore) listDeliveries(query string, args ...any) ([]*Delivery, error) {
+	var deliveries []*Delivery
+
+	if err := store.db.Where(query, args...).Order("id").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign gera o header de assinatura "t=<unix>,v1=<hex>", onde v1 é o
+// HMAC-SHA256 de "<unix>.<body>" com o secret da assinatura. O timestamp
+// no conteúdo assinado impede o replay de entregas antigas.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, signature(secret, unix, body))
+}
+
+// Verify confere o header gerado por Sign, recusando assinaturas mais velhas
+// que tolerance. É o que um receptor em Go deve fazer com cada entrega.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix, received string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			received = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || received == "" {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(received), []byte(signature(secret, unix, body))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// NewSecret gera um secret aleatório para assinaturas criadas sem um.
+func NewSecret() string {
+	buffer := make([]byte, 32)
+	_, _ = rand.Read(buffer)
+	return "whsec_" + hex.EncodeToString(buffer)
+}
+
+func signature(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
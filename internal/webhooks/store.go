@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryNotDead      = errors.New("webhook delivery is not in the dead-letter list")
+)
+
+// AllEvents assina todos os eventos de categoria.
+const AllEvents = "*"
+
+// Subscription é um endpoint que recebe os eventos em Events (ou todos, com
+// AllEvents) do seu tenant. Secret assina o corpo de cada entrega com
+// HMAC-SHA256.
+type Subscription struct {
+	ID        uint      `gorm:"primaryKey"`
+	TenantID  string    `gorm:"not null;default:default;index"`
+	URL       string    `gorm:"not null"`
+	Secret    string    `gorm:"not null"`
+	Events    []string  `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (subscription *Subscription) Tenant() string {
+	return subscription.TenantID
+}
+
+func (subscription *Subscription) Matches(eventName string) bool {
+	for _, name := range subscription.Events {
+		if name == AllEvents || name == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead é a dead-letter: esgotou as tentativas e só volta com Retry
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery é o envio de um evento a uma assinatura e também o seu log:
+// quantas tentativas, o último status HTTP e o último erro.
+type Delivery struct {
+	ID             uint           `gorm:"primaryKey"`
+	TenantID       string         `gorm:"not null;default:default;index"`
+	SubscriptionID uint           `gorm:"not null;index"`
+	EventName      string         `gorm:"not null"`
+	Payload        []byte         `gorm:"type:jsonb;not null"`
+	Status         DeliveryStatus `gorm:"not null;index"`
+	Attempts       int            `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time      `gorm:"not null;index"`
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (delivery *Delivery) Tenant() string {
+	return delivery.TenantID
+}
+
+// IStore guarda assinaturas e entregas. ListSubscriptions e
+// ListDeadLetters devolvem só as assinaturas e entregas do tenant. DeleteSubscription remove também as entregas da
+// assinatura. ClaimDueDeliveries devolve até limit entregas
+// pendentes já vencidas e as reserva por lease.
+type IStore interface {
+	SaveSubscription(subscription *Subscription) error
+	ListSubscriptions(tenant string) ([]*Subscription, error)
+	FindSubscription(id uint) (*Subscription, error)
+	DeleteSubscription(id uint) error
+
+	SaveDeliveries(deliveries ...*Delivery) error
+	FindDelivery(id uint) (*Delivery, error)
+	UpdateDelivery(delivery *Delivery) error
+	ClaimDueDeliveries(limit int, now time.Time, lease time.Duration) ([]*Delivery, error)
+	ListDeliveries(subscriptionID uint) ([]*Delivery, error)
+	ListDeadLetters(tenant string) ([]*Delivery, error)
+}
@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenTarget = errors.New("webhook target is a loopback, link-local, private or unspecified address")
+
+// Faixas que não são privadas para o netip mas também só alcançam a rede
+// interna
+var forbiddenPrefixes = []netip.Prefix{
+	// "esta rede": no Linux 0.x.x.x chega ao próprio host
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// CGNAT, usada pelo serviço de metadados de alguns provedores
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Targets decide para quais endereços as entregas podem ser enviadas. Por
+// padrão só endereços públicos: sem isso um admin de tenant faria o
+// servidor mandar POSTs assinados para a rede interna (e usaria o
+// LastStatusCode do log de entregas para varrê-la).
+type Targets struct {
+	// AllowPrivate libera os endereços internos; só para desenvolvimento e
+	// testes, com receptores na própria máquina
+	AllowPrivate bool
+}
+
+// Check recusa com ErrForbiddenTarget um host que é (ou resolve para) um
+// endereço interno. Um nome que ainda não resolve é aceito: o endereço é
+// conferido de novo a cada conexão pelo Client.
+func (targets Targets) Check(ctx context.Context, host string) error {
+	if targets.AllowPrivate {
+		return nil
+	}
+
+	if address, err := netip.ParseAddr(host); err == nil {
+		return checkAddress(address)
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenTarget
+	}
+
+	addresses, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	for _, address := range addresses {
+		if err := checkAddress(address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Client é o http.Client das entregas: timeout de DefaultTimeout, sem
+// seguir redirecionamentos (um 3xx conta como falha) e, sem AllowPrivate,
+// recusando na conexão os endereços internos, já que o DNS de um host pode
+// mudar depois do cadastro.
+func (targets Targets) Client() *http.Client {
+	dialer := &net.Dialer{Timeout: DefaultTimeout}
+	if !targets.AllowPrivate {
+		dialer.Control = controlDial
+	}
+
+	transport := &http.Transport{
+		// Sem proxy: a conexão tem que ir direto ao endereço conferido
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   DefaultTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   DefaultTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// controlDial roda depois da resolução, com o endereço que vai ser usado.
+func controlDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	return checkAddress(ip)
+}
+
+func checkAddress(address netip.Addr) error {
+	address = address.Unmap()
+
+	if address.IsLoopback() || address.IsLinkLocalUnicast() || address.IsLinkLocalMulticast() ||
+		address.IsInterfaceLocalMulticast() || address.IsPrivate() || address.IsUnspecified() {
+		return ErrForbiddenTarget
+	}
+
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(address) {
+			return ErrForbiddenTarget
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTargets_Check(t *testing.T) {
+	testCases := []struct {
+		host    string
+		wantErr error
+	}{
+		{"93.184.215.14", nil},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", nil},
+		{"127.0.0.1", ErrForbiddenTarget},
+		{"::1", ErrForbiddenTarget},
+		{"localhost", ErrForbiddenTarget},
+		{"api.localhost.", ErrForbiddenTarget},
+		{"169.254.169.254", ErrForbiddenTarget},
+		{"172.16.0.10", ErrForbiddenTarget},
+		{"192.168.1.1", ErrForbiddenTarget},
+		{"fd00::1", ErrForbiddenTarget},
+		{"0.0.0.0", ErrForbiddenTarget},
+		{"100.100.100.200", ErrForbiddenTarget},
+		{"::ffff:10.0.0.1", ErrForbiddenTarget},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.host, func(t *testing.T) {
+			// Act
+			err := Targets{}.Check(context.Background(), testCase.host)
+
+			// Assert
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Expected %v, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+
+	if err := (Targets{AllowPrivate: true}).Check(context.Background(), "127.0.0.1"); err != nil {
+		t.Errorf("Expected loopback allowed with AllowPrivate, got %v", err)
+	}
+}
+
+func TestTargets_ClientRefusesInternalAddressesOnDial(t *testing.T) {
+	// Arrange
+	target := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	store := NewInMemoryStore()
+	store.SaveSubscription(&Subscription{URL: server.URL, Secret: "s3cr3t-s3cr3t-s3cr3t", Events: []string{AllEvents}})
+	dispatcher := NewDispatcher(store, nil)
+	dispatcher.Enqueue(context.Background(), testEvent{})
+
+	// Act
+	delivered, err := dispatcher.DeliverDue(context.Background())
+
+	// Assert
+	if err != nil || delivered != 0 {
+		t.Fatalf("Expected no delivery, got %d (%v)", delivered, err)
+	}
+
+	if len(target.requests) != 0 {
+		t.Errorf("Expected no request to the loopback receiver, got %d", len(target.requests))
+	}
+
+	delivery, _ := store.FindDelivery(1)
+	if delivery.Status != DeliveryPending || delivery.LastStatusCode != 0 || delivery.LastError == "" {
+		t.Errorf("Expected a failed attempt without a status code, got %+v", delivery)
+	}
+}
+
+func TestTargets_ClientDoesNotFollowRedirects(t *testing.T) {
+	// Arrange
+	followed := false
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/internal" {
+			followed = true
+			return
+		}
+		http.Redirect(writer, request, "/internal", http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+
+	// Act
+	response, err := Targets{AllowPrivate: true}.Client().Post(server.URL+"/hooks", "application/json", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusTemporaryRedirect || followed {
+		t.Errorf("Expected the redirect returned and not followed, got %d (followed %v)", response.StatusCode, followed)
+	}
+}